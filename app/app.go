@@ -6,12 +6,16 @@ import (
 
 	"codeberg.org/puregotk/puregotk/v4/adw"
 	"codeberg.org/puregotk/puregotk/v4/gio"
+	"codeberg.org/puregotk/puregotk/v4/gobject"
 	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/player"
 	"github.com/0skillallluck/scanline/app/dialogs/secretservice"
+	"github.com/0skillallluck/scanline/app/preference"
 	"github.com/0skillallluck/scanline/app/router"
 	"github.com/0skillallluck/scanline/app/secrets"
 	"github.com/0skillallluck/scanline/app/sources"
 	"github.com/0skillallluck/scanline/app/windows"
+	"github.com/0skillallluck/scanline/internal/signals"
 )
 
 func OnActivate(application *adw.Application) func(gio.Application) {
@@ -35,6 +39,26 @@ func OnActivate(application *adw.Application) func(gio.Application) {
 			go mgr.RefreshServers(ctx)
 		}
 
+		// Re-probe server connections periodically and when the network
+		// changes, e.g. when leaving the home network
+		mgr.StartConnectionMonitor(ctx)
+		networkMonitor := gobject.ObjectNewFromInternalPtr(gio.NetworkMonitorGetDefault().GoPointer())
+		networkMonitor.ConnectSignal("network-changed", new(func() {
+			mgr.NetworkChanged()
+		}))
+
+		// Copy the watch state of played items to the other servers
+		player.Stopped.On(func(stop player.Stop) bool {
+			if preference.General().SyncWatchState() {
+				go func() {
+					if _, err := mgr.SyncItemWatchState(ctx, stop.ServerID, stop.ItemID); err != nil {
+						slog.Warn("failed to sync watch state", "server", stop.ServerID, "item", stop.ItemID, "error", err)
+					}
+				}()
+			}
+			return signals.Continue
+		})
+
 		window := windows.NewWindow(application, appCtx)
 		appCtx.Window = &window.Window
 
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/0skillallluck/scanline/app/secrets"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/provider/emby"
+)
+
+// embyExchangeTimeout bounds each attempt to reach a server linked to an
+// Emby Connect account.
+const embyExchangeTimeout = 5 * time.Second
+
+// ErrNoEmbyServers is returned by EmbyConnectSignIn when none of the
+// servers linked to the Emby Connect account could be reached.
+var ErrNoEmbyServers = errors.New("no reachable Emby servers linked to the account")
+
+// EmbySignIn signs in to an Emby server with a username and password and
+// adds the account.
+func EmbySignIn(ctx context.Context, mgr *sources.Manager, serverURL, username, password string) error {
+	clientID := secrets.GetOrCreateClientID()
+	client := emby.NewClient(serverURL, "", "", clientID)
+
+	info, err := client.PublicInfo(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := client.AuthenticateByName(ctx, username, password)
+	if err != nil {
+		slog.Debug("emby: authentication failed", "server", info.ServerName, "error", err)
+		return err
+	}
+	mgr.AddEmbyAccount(serverURL, clientID, info, result)
+	return nil
+}
+
+// EmbyConnectSignIn signs in to Emby Connect and adds the account with
+// every linked server that can be reached, trying the local address of
+// each server before its remote one.
+func EmbyConnectSignIn(ctx context.Context, mgr *sources.Manager, nameOrEmail, password string) error {
+	clientID := secrets.GetOrCreateClientID()
+
+	result, err := emby.ConnectAuthenticate(ctx, nameOrEmail, password)
+	if err != nil {
+		return err
+	}
+	linked, err := emby.ConnectServers(ctx, result.User.ID, result.AccessToken)
+	if err != nil {
+		return err
+	}
+
+	var servers []sources.EmbyConnectServer
+	for _, srv := range linked {
+		if resolved, ok := exchangeEmbyServer(ctx, srv, result.User.ID, clientID); ok {
+			servers = append(servers, resolved)
+		}
+	}
+	if len(servers) == 0 {
+		return ErrNoEmbyServers
+	}
+	mgr.AddEmbyConnectAccount(clientID, result, servers)
+	return nil
+}
+
+// exchangeEmbyServer exchanges the access key of a linked server for a
+// server token at the first address that answers.
+func exchangeEmbyServer(ctx context.Context, srv emby.ConnectServer, connectUserID, clientID string) (sources.EmbyConnectServer, bool) {
+	for _, address := range []string{srv.LocalAddress, srv.URL} {
+		if address == "" {
+			continue
+		}
+		address = JellyfinServerURL(address)
+		client := emby.NewClient(address, srv.AccessKey, "", clientID)
+
+		attemptCtx, cancel := context.WithTimeout(ctx, embyExchangeTimeout)
+		exchange, err := client.ExchangeConnectToken(attemptCtx, connectUserID)
+		cancel()
+		if err != nil {
+			slog.Debug("emby: connect exchange failed", "server", srv.Name, "address", address, "error", err)
+			continue
+		}
+		return sources.EmbyConnectServer{Server: srv, URL: address, Exchange: exchange}, true
+	}
+	slog.Warn("emby: linked server unreachable", "server", srv.Name)
+	return sources.EmbyConnectServer{}, false
+}
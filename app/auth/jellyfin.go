@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/0skillallluck/scanline/app/secrets"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/provider/jellyfin"
+)
+
+// quickConnectInterval is how often Quick Connect requests are polled.
+const quickConnectInterval = 3 * time.Second
+
+// JellyfinServerURL normalizes a server address entered by the user,
+// defaulting to HTTP as Jellyfin servers usually run without TLS.
+func JellyfinServerURL(address string) string {
+	address = strings.TrimRight(strings.TrimSpace(address), "/")
+	if address != "" && !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return address
+}
+
+// JellyfinSignIn signs in to a Jellyfin server with a username and
+// password and adds the account.
+func JellyfinSignIn(ctx context.Context, mgr *sources.Manager, serverURL, username, password string) error {
+	clientID := secrets.GetOrCreateClientID()
+	client := jellyfin.NewClient(serverURL, "", "", clientID)
+
+	info, err := client.PublicInfo(ctx)
+	if err != nil {
+		return err
+	}
+	result, err := client.AuthenticateByName(ctx, username, password)
+	if err != nil {
+		slog.Debug("jellyfin: authentication failed", "server", info.ServerName, "error", err)
+		return err
+	}
+	mgr.AddJellyfinAccount(serverURL, clientID, info, result)
+	return nil
+}
+
+// JellyfinQuickConnect signs in to a Jellyfin server with Quick Connect and
+// adds the account. onCode is called with the code to approve in another
+// client; the flow waits for the approval until ctx is canceled.
+func JellyfinQuickConnect(ctx context.Context, mgr *sources.Manager, serverURL string, onCode func(code string)) error {
+	clientID := secrets.GetOrCreateClientID()
+	client := jellyfin.NewClient(serverURL, "", "", clientID)
+
+	info, err := client.PublicInfo(ctx)
+	if err != nil {
+		return err
+	}
+	qc, err := client.InitiateQuickConnect(ctx)
+	if err != nil {
+		return err
+	}
+	onCode(qc.Code)
+
+	result, err := client.PollQuickConnect(ctx, qc.Secret, quickConnectInterval)
+	if err != nil {
+		slog.Debug("jellyfin: quick connect failed", "server", info.ServerName, "error", err)
+		return err
+	}
+	mgr.AddJellyfinAccount(serverURL, clientID, info, result)
+	return nil
+}
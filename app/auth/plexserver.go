@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/0skillallluck/scanline/app/secrets"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/provider/plex"
+	"github.com/0skillallluck/scanline/provider/plex/gdm"
+)
+
+// plexDefaultPort is the port Plex Media Server listens on by default.
+const plexDefaultPort = "32400"
+
+// PlexServerURL normalizes a Plex server address entered by the user.
+// Addresses without a scheme default to HTTP and the default Plex port;
+// full URLs, such as those of a reverse proxy, are kept as entered.
+func PlexServerURL(address string) string {
+	hasScheme := strings.Contains(address, "://")
+	address = JellyfinServerURL(address)
+	u, err := url.Parse(address)
+	if err != nil || hasScheme || u.Host == "" || u.Port() != "" {
+		return address
+	}
+	u.Host += ":" + plexDefaultPort
+	return u.String()
+}
+
+// AddPlexServer validates the Plex server at serverURL and adds it as a
+// standalone account. token may be empty for servers that allow access
+// without signing in.
+func AddPlexServer(ctx context.Context, mgr *sources.Manager, serverURL, token string) error {
+	clientID := secrets.GetOrCreateClientID()
+	client := plex.NewClient(serverURL, token, clientID)
+
+	identity, err := client.Server.Identity(ctx)
+	if err != nil {
+		return err
+	}
+	// The identity endpoint answers without a token; reading the server
+	// info checks that the token grants access.
+	info, err := client.Server.Info(ctx)
+	if err != nil {
+		slog.Debug("plex: server rejected access", "url", serverURL, "error", err)
+		return err
+	}
+	name := info.FriendlyName
+	if name == "" {
+		name = serverURL
+	}
+	return mgr.AddPlexServer(serverURL, token, clientID, identity.MachineIdentifier, name)
+}
+
+// DiscoverPlexServers finds Plex servers on the local network that are
+// not added yet.
+func DiscoverPlexServers(ctx context.Context, mgr *sources.Manager) []gdm.Resource {
+	resources, err := gdm.Discover(ctx, gdm.DefaultWait)
+	if err != nil {
+		slog.Debug("plex: LAN discovery failed", "error", err)
+		return nil
+	}
+	var found []gdm.Resource
+	for _, r := range resources {
+		if !mgr.HasServer(r.ResourceIdentifier) {
+			found = append(found, r)
+		}
+	}
+	return found
+}
@@ -0,0 +1,313 @@
+package audio
+
+import (
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"codeberg.org/puregotk/puregotk/v4/pango"
+	"github.com/0skillallluck/scanline/app/components/widgets"
+	"github.com/0skillallluck/scanline/app/preference"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/internal/signals"
+	"github.com/0skillallluck/scanline/utils/imageutils"
+)
+
+// artSize is the mini-player album art size in pixels.
+const artSize = 48
+
+// NewMiniPlayer creates the bottom bar that shows and controls music
+// playback. It hides itself while the queue is empty.
+func NewMiniPlayer() *gtk.Widget {
+	root := gtk.NewBox(gtk.OrientationVerticalValue, 0)
+	root.AddCssClass("toolbar")
+	root.SetVisible(false)
+
+	lyrics := newLyricsPanel()
+	root.Append(&lyrics.revealer.Widget)
+
+	// Seek bar
+	seek := gtk.NewScaleWithRange(gtk.OrientationHorizontalValue, 0, 1, 1)
+	seek.SetDrawValue(false)
+	seek.SetHexpand(true)
+	positionLabel := gtk.NewLabel("0:00")
+	positionLabel.AddCssClass("numeric")
+	positionLabel.AddCssClass("dim-label")
+	positionLabel.AddCssClass("caption")
+	durationLabel := gtk.NewLabel("0:00")
+	durationLabel.AddCssClass("numeric")
+	durationLabel.AddCssClass("dim-label")
+	durationLabel.AddCssClass("caption")
+	seekRow := gtk.NewBox(gtk.OrientationHorizontalValue, 6)
+	seekRow.Append(&positionLabel.Widget)
+	seekRow.Append(&seek.Widget)
+	seekRow.Append(&durationLabel.Widget)
+	root.Append(&seekRow.Widget)
+
+	layout := gtk.NewCenterBox()
+	layout.SetHexpand(true)
+	root.Append(&layout.Widget)
+
+	// Now playing
+	art := gtk.NewPicture()
+	art.SetSizeRequest(artSize, artSize)
+	art.SetContentFit(gtk.ContentFitCoverValue)
+	art.AddCssClass("card")
+	art.SetOverflow(gtk.OverflowHiddenValue)
+
+	titleLabel := gtk.NewLabel("")
+	titleLabel.AddCssClass("heading")
+	titleLabel.SetXalign(0)
+	titleLabel.SetEllipsize(pango.EllipsizeEndValue)
+	artistLabel := gtk.NewLabel("")
+	artistLabel.AddCssClass("dim-label")
+	artistLabel.AddCssClass("caption")
+	artistLabel.SetXalign(0)
+	artistLabel.SetEllipsize(pango.EllipsizeEndValue)
+	labels := gtk.NewBox(gtk.OrientationVerticalValue, 2)
+	labels.SetValign(gtk.AlignCenterValue)
+	labels.Append(&titleLabel.Widget)
+	labels.Append(&artistLabel.Widget)
+
+	nowPlayingContent := gtk.NewBox(gtk.OrientationHorizontalValue, 10)
+	nowPlayingContent.Append(&art.Widget)
+	nowPlayingContent.Append(&labels.Widget)
+	nowPlaying := gtk.NewButton()
+	nowPlaying.AddCssClass("flat")
+	nowPlaying.SetChild(&nowPlayingContent.Widget)
+	nowPlaying.SetTooltipText(gettext.Get("Go to Album"))
+	nowPlaying.SetSizeRequest(240, -1)
+	layout.SetStartWidget(&nowPlaying.Widget)
+
+	// Transport controls
+	previousButton := controlButton("media-skip-backward-symbolic", gettext.Get("Previous"), Previous)
+	playPauseButton := controlButton("media-playback-start-symbolic", gettext.Get("Play"), TogglePlayPause)
+	playPauseButton.AddCssClass("circular")
+	nextButton := controlButton("media-skip-forward-symbolic", gettext.Get("Next"), Next)
+	transport := gtk.NewBox(gtk.OrientationHorizontalValue, 6)
+	transport.SetValign(gtk.AlignCenterValue)
+	transport.Append(&previousButton.Widget)
+	transport.Append(&playPauseButton.Widget)
+	transport.Append(&nextButton.Widget)
+	layout.SetCenterWidget(&transport.Widget)
+
+	// Queue options
+	shuffleButton := gtk.NewToggleButton()
+	shuffleButton.SetIconName("media-playlist-shuffle-symbolic")
+	shuffleButton.SetTooltipText(gettext.Get("Shuffle"))
+	shuffleButton.AddCssClass("flat")
+	var updating bool // set while syncing widgets to avoid feedback loops
+	shuffleButton.ConnectToggled(new(func(b gtk.ToggleButton) {
+		if !updating {
+			SetShuffle(b.GetActive())
+		}
+	}))
+
+	repeatButton := controlButton("media-playlist-repeat-symbolic", gettext.Get("Repeat"), CycleRepeat)
+
+	volumeButton := gtk.NewScaleButton(0, 1, 0.05, []string{
+		"audio-volume-muted-symbolic",
+		"audio-volume-high-symbolic",
+		"audio-volume-low-symbolic",
+		"audio-volume-medium-symbolic",
+	})
+	volumeButton.SetValue(userVolume())
+	volumeButton.SetTooltipText(gettext.Get("Volume"))
+	volumeButton.ConnectValueChanged(new(func(_ gtk.ScaleButton, v float64) {
+		if !updating {
+			SetVolume(v)
+		}
+	}))
+
+	lyricsButton := gtk.NewToggleButton()
+	lyricsButton.SetIconName("format-justify-center-symbolic")
+	lyricsButton.SetTooltipText(gettext.Get("Lyrics"))
+	lyricsButton.AddCssClass("flat")
+	lyricsButton.ConnectToggled(new(func(b gtk.ToggleButton) {
+		lyrics.SetRevealed(b.GetActive())
+	}))
+
+	queueButton := gtk.NewMenuButton()
+	queueButton.SetIconName("view-list-symbolic")
+	queueButton.SetTooltipText(gettext.Get("Queue"))
+	queueButton.SetPopover(queuePopover())
+
+	closeButton := controlButton("window-close-symbolic", gettext.Get("Stop Playback"), Stop)
+
+	options := gtk.NewBox(gtk.OrientationHorizontalValue, 6)
+	options.SetValign(gtk.AlignCenterValue)
+	options.Append(&shuffleButton.Widget)
+	options.Append(&repeatButton.Widget)
+	options.Append(&volumeButton.Widget)
+	options.Append(&lyricsButton.Widget)
+	options.Append(&queueButton.Widget)
+	options.Append(&closeButton.Widget)
+	layout.SetEndWidget(&options.Widget)
+
+	// Seeking
+	var seeking bool
+	seek.ConnectChangeValue(new(func(_ gtk.Range, _ gtk.ScrollType, value float64) bool {
+		seeking = true
+		positionLabel.SetText(widgets.FormatTimestamp(int(value)))
+		return false
+	}))
+	release := gtk.NewGestureClick()
+	release.ConnectReleased(new(func(_ gtk.GestureClick, _ int32, _, _ float64) {
+		if seeking {
+			seeking = false
+			Seek(int(seek.GetValue()))
+		}
+	}))
+	seek.AddController(&release.EventController)
+
+	var (
+		artKey   string
+		albumKey string
+	)
+	update := func(state State) {
+		updating = true
+		defer func() { updating = false }()
+
+		root.SetVisible(state.Track != nil)
+		lyrics.update(state)
+		if state.Track == nil {
+			return
+		}
+		track := state.Track
+
+		titleLabel.SetText(track.Title)
+		artistLabel.SetText(joinNonEmpty(track.Artist, track.Album))
+		if key := track.Source.ID() + track.Thumb; key != artKey {
+			artKey = key
+			art.SetPaintable(nil)
+			if track.Thumb != "" && preference.Performance().AllowPreviewImages() {
+				imageutils.LoadIntoPictureScaled(track.Source.PhotoTranscodeURL(track.Thumb, artSize*2, artSize*2), artSize, artSize, art)
+			}
+		}
+		if key := track.Source.ID() + "/" + string(track.AlbumKey); key != albumKey {
+			albumKey = key
+			nowPlaying.SetSensitive(track.AlbumKey != "")
+			nowPlaying.SetActionName("win.route.album")
+			nowPlaying.SetActionTargetValue(glib.NewVariantString(key))
+		}
+
+		if state.Playing {
+			playPauseButton.SetIconName("media-playback-pause-symbolic")
+			playPauseButton.SetTooltipText(gettext.Get("Pause"))
+		} else {
+			playPauseButton.SetIconName("media-playback-start-symbolic")
+			playPauseButton.SetTooltipText(gettext.Get("Play"))
+		}
+		nextButton.SetSensitive(state.HasNext)
+
+		shuffleButton.SetActive(state.Shuffle)
+		switch state.Repeat {
+		case RepeatAll:
+			repeatButton.SetIconName("media-playlist-repeat-symbolic")
+			repeatButton.SetTooltipText(gettext.Get("Repeat All"))
+			repeatButton.AddCssClass("accent")
+		case RepeatOne:
+			repeatButton.SetIconName("media-playlist-repeat-song-symbolic")
+			repeatButton.SetTooltipText(gettext.Get("Repeat One"))
+			repeatButton.AddCssClass("accent")
+		default:
+			repeatButton.SetIconName("media-playlist-repeat-symbolic")
+			repeatButton.SetTooltipText(gettext.Get("Repeat"))
+			repeatButton.RemoveCssClass("accent")
+		}
+
+		if !seeking {
+			seek.SetRange(0, float64(max(state.Duration, 1)))
+			seek.SetValue(float64(state.Position))
+			positionLabel.SetText(widgets.FormatTimestamp(state.Position))
+		}
+		durationLabel.SetText(widgets.FormatTimestamp(state.Duration))
+	}
+
+	sub := Changed.On(func(state State) bool {
+		schwifty.OnMainThreadOncePure(func() {
+			update(state)
+		})
+		return signals.Continue
+	})
+	root.ConnectDestroy(new(func(gtk.Widget) {
+		Changed.Unsubscribe(sub)
+	}))
+	update(Current())
+
+	return &root.Widget
+}
+
+// queuePopover lists the queued tracks. The list is rebuilt every time the
+// popover opens; activating a row jumps to that track.
+func queuePopover() *gtk.Popover {
+	list := gtk.NewListBox()
+	list.SetSelectionMode(gtk.SelectionNoneValue)
+	list.AddCssClass("navigation-sidebar")
+	list.ConnectRowActivated(new(func(_ gtk.ListBox, rowPtr uintptr) {
+		JumpTo(int(gtk.ListBoxRowNewFromInternalPtr(rowPtr).GetIndex()))
+	}))
+
+	scroller := gtk.NewScrolledWindow()
+	scroller.SetPolicy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue)
+	scroller.SetPropagateNaturalHeight(true)
+	scroller.SetMaxContentHeight(400)
+	scroller.SetMinContentWidth(320)
+	scroller.SetChild(&list.Widget)
+
+	popover := gtk.NewPopover()
+	popover.SetChild(&scroller.Widget)
+	popover.ConnectShow(new(func(gtk.Widget) {
+		list.RemoveAll()
+		tracks, pos := Queued()
+		for i, track := range tracks {
+			title := gtk.NewLabel(track.Title)
+			title.SetXalign(0)
+			title.SetEllipsize(pango.EllipsizeEndValue)
+			if i == pos {
+				title.AddCssClass("heading")
+			}
+			artist := gtk.NewLabel(track.Artist)
+			artist.SetXalign(0)
+			artist.SetEllipsize(pango.EllipsizeEndValue)
+			artist.AddCssClass("dim-label")
+			artist.AddCssClass("caption")
+
+			labels := gtk.NewBox(gtk.OrientationVerticalValue, 2)
+			labels.SetHexpand(true)
+			labels.Append(&title.Widget)
+			labels.Append(&artist.Widget)
+
+			content := gtk.NewBox(gtk.OrientationHorizontalValue, 10)
+			content.Append(&labels.Widget)
+			if track.Duration > 0 {
+				duration := gtk.NewLabel(widgets.FormatTimestamp(track.Duration))
+				duration.AddCssClass("dim-label")
+				duration.AddCssClass("numeric")
+				content.Append(&duration.Widget)
+			}
+			list.Append(&content.Widget)
+		}
+	}))
+	return popover
+}
+
+func controlButton(icon, tooltip string, onClick func()) *gtk.Button {
+	button := gtk.NewButtonFromIconName(icon)
+	button.SetTooltipText(tooltip)
+	button.AddCssClass("flat")
+	button.ConnectClicked(new(func(gtk.Button) {
+		onClick()
+	}))
+	return button
+}
+
+func joinNonEmpty(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + " — " + b
+	}
+}
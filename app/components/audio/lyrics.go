@@ -0,0 +1,188 @@
+package audio
+
+import (
+	"log/slog"
+	"time"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/puregotk/puregotk/v4/graphene"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"codeberg.org/puregotk/puregotk/v4/pango"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/utils/lrcutils"
+)
+
+// lyricsPanel shows the current track's lyrics. Synced lyrics highlight
+// the line being sung and keep it centered.
+type lyricsPanel struct {
+	revealer *gtk.Revealer
+	scroller *gtk.ScrolledWindow
+	lines    *gtk.Box
+
+	key    string // identifies the lyrics being shown
+	lyrics *lrcutils.Lyrics
+	labels []*gtk.Label
+	active int
+}
+
+func newLyricsPanel() *lyricsPanel {
+	p := &lyricsPanel{active: -1}
+
+	p.lines = gtk.NewBox(gtk.OrientationVerticalValue, 12)
+	p.lines.SetMarginTop(24)
+	p.lines.SetMarginBottom(24)
+	p.lines.SetMarginStart(24)
+	p.lines.SetMarginEnd(24)
+
+	p.scroller = gtk.NewScrolledWindow()
+	p.scroller.SetPolicy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue)
+	p.scroller.SetSizeRequest(-1, 280)
+	p.scroller.SetChild(&p.lines.Widget)
+
+	p.revealer = gtk.NewRevealer()
+	p.revealer.SetTransitionType(gtk.RevealerTransitionTypeSlideUpValue)
+	p.revealer.SetChild(&p.scroller.Widget)
+	return p
+}
+
+// SetRevealed shows or hides the panel.
+func (p *lyricsPanel) SetRevealed(revealed bool) {
+	p.revealer.SetRevealChild(revealed)
+	if revealed {
+		p.scrollToActive()
+	}
+}
+
+// update follows the player state, loading lyrics when the track changes
+// and moving the highlight as playback progresses.
+func (p *lyricsPanel) update(state State) {
+	if state.Track == nil {
+		if p.key != "" {
+			p.show("", nil)
+		}
+		return
+	}
+
+	track := state.Track
+	key := track.Source.ID() + "/" + string(track.ItemID)
+	switch {
+	case track.Lyrics != nil:
+		key += "/" + track.Lyrics.Key
+	case !track.detailed:
+		// Stream details are still loading; re-evaluate once they arrive.
+		key += "/pending"
+	}
+	if key != p.key {
+		p.key = key
+		switch {
+		case track.Lyrics != nil:
+			p.message(gettext.Get("Loading lyrics…"))
+			p.load(key, *track)
+		case track.detailed:
+			p.message(gettext.Get("No lyrics available for this track"))
+		default:
+			p.message(gettext.Get("Loading lyrics…"))
+		}
+		return
+	}
+
+	if p.lyrics != nil {
+		p.highlight(p.lyrics.LineAt(time.Duration(state.Position) * time.Millisecond))
+	}
+}
+
+func (p *lyricsPanel) load(key string, track Track) {
+	go func() {
+		lyrics, err := track.Source.GetLyrics(ctx, track.Lyrics)
+		schwifty.OnMainThreadOncePure(func() {
+			if key != p.key {
+				return
+			}
+			if err != nil {
+				slog.Error("failed to load lyrics", "item", track.ItemID, "error", err)
+				p.message(gettext.Get("Could not load lyrics"))
+				return
+			}
+			parsed := lrcutils.Parse(lyrics.Text)
+			if len(parsed.Lines) == 0 {
+				p.message(gettext.Get("No lyrics available for this track"))
+				return
+			}
+			p.show(key, parsed)
+			p.update(Current())
+		})
+	}()
+}
+
+// message replaces the lyrics with a single status line.
+func (p *lyricsPanel) message(text string) {
+	p.clear()
+	label := gtk.NewLabel(text)
+	label.AddCssClass("dim-label")
+	label.SetVexpand(true)
+	p.lines.Append(&label.Widget)
+}
+
+func (p *lyricsPanel) show(key string, lyrics *lrcutils.Lyrics) {
+	p.clear()
+	p.key = key
+	p.lyrics = lyrics
+	if lyrics == nil {
+		return
+	}
+	for _, line := range lyrics.Lines {
+		text := line.Text
+		if text == "" && lyrics.Synced {
+			text = "♪"
+		}
+		label := gtk.NewLabel(text)
+		label.SetWrap(true)
+		label.SetWrapMode(pango.WrapWordCharValue)
+		label.SetJustify(gtk.JustifyCenterValue)
+		label.AddCssClass("title-4")
+		if lyrics.Synced {
+			label.AddCssClass("dim-label")
+		}
+		p.lines.Append(&label.Widget)
+		p.labels = append(p.labels, label)
+	}
+}
+
+func (p *lyricsPanel) clear() {
+	for child := p.lines.GetFirstChild(); child != nil; child = p.lines.GetFirstChild() {
+		p.lines.Remove(child)
+	}
+	p.lyrics = nil
+	p.labels = nil
+	p.active = -1
+	p.scroller.GetVadjustment().SetValue(0)
+}
+
+func (p *lyricsPanel) highlight(index int) {
+	if index == p.active {
+		return
+	}
+	if p.active >= 0 && p.active < len(p.labels) {
+		p.labels[p.active].AddCssClass("dim-label")
+	}
+	p.active = index
+	if index >= 0 && index < len(p.labels) {
+		p.labels[index].RemoveCssClass("dim-label")
+	}
+	p.scrollToActive()
+}
+
+// scrollToActive centers the highlighted line in the panel.
+func (p *lyricsPanel) scrollToActive() {
+	if p.active < 0 || p.active >= len(p.labels) || !p.revealer.GetRevealChild() {
+		return
+	}
+	label := p.labels[p.active]
+	var out graphene.Point
+	if !label.ComputePoint(&p.lines.Widget, &graphene.Point{}, &out) {
+		return
+	}
+	adj := p.scroller.GetVadjustment()
+	target := float64(out.Y) - (adj.GetPageSize()-float64(label.GetHeight()))/2
+	adj.SetValue(min(max(target, 0), adj.GetUpper()-adj.GetPageSize()))
+}
@@ -0,0 +1,509 @@
+// Package audio implements music playback: a track queue with shuffle and
+// repeat, gapless transitions between tracks and loudness normalization.
+// Playback lives outside the router so it keeps going while the user
+// navigates; the mini-player bar follows it through the Changed signal.
+//
+// All functions must be called from the main thread.
+package audio
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/puregotk/puregotk/v4/gio"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/preference"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/signals"
+)
+
+const (
+	// tickInterval is how often playback state is polled, in milliseconds.
+	// It also bounds the gap between gapless tracks.
+	tickInterval = 200
+
+	// progressInterval is how often timeline updates are sent while
+	// playing, in milliseconds.
+	progressInterval = 10000
+
+	// preloadWindow is how long before the end of a track the next one is
+	// opened, in microseconds.
+	preloadWindow = 15 * 1000 * 1000
+
+	// scrobbleThreshold is the fraction of a track that must be played
+	// before it is marked as played.
+	scrobbleThreshold = 0.9
+)
+
+// State is a snapshot of the player for UI updates.
+type State struct {
+	Track       *Track // nil when nothing is queued
+	Playing     bool
+	Position    int // milliseconds
+	Duration    int // milliseconds
+	Volume      float64
+	Shuffle     bool
+	Repeat      RepeatMode
+	HasNext     bool
+	HasPrevious bool
+}
+
+// Changed is notified whenever the player state changes, including
+// position updates during playback.
+var Changed = signals.NewStatelessSignal[State]()
+
+// deck is an opened media stream for a queued track.
+type deck struct {
+	track Track
+	media *gtk.MediaFile
+}
+
+func openDeck(track Track) *deck {
+	url := track.Source.StreamURL(track.PartKey)
+	media := gtk.NewMediaFileForFile(gio.FileNewForUri(url))
+	media.SetMuted(false)
+	return &deck{track: track, media: media}
+}
+
+func (d *deck) close() {
+	d.media.Pause()
+	d.media.Clear()
+}
+
+// position returns the playback position in milliseconds.
+func (d *deck) position() int {
+	return int(d.media.GetTimestamp() / 1000)
+}
+
+// duration returns the track duration in milliseconds, falling back to the
+// metadata duration until the stream is prepared.
+func (d *deck) duration() int {
+	if dur := d.media.GetDuration(); dur > 0 {
+		return int(dur / 1000)
+	}
+	return d.track.Duration
+}
+
+var (
+	// ctx is independent of page contexts, which are cancelled on
+	// navigation.
+	ctx = context.Background()
+
+	queue     Queue
+	current   *deck
+	preloaded *deck // the next track, opened ahead of time for gapless playback
+
+	tickerID     uint32
+	lastProgress int64 // monotonic ms of last timeline update
+	scrobbled    bool  // whether the current track was already scrobbled
+
+	volume           = -1.0 // user volume; loaded from settings on first use
+	normalizeWatched bool
+)
+
+// Play replaces the queue and starts playing at the given track index.
+func Play(tracks []Track, start int) {
+	if len(tracks) == 0 {
+		return
+	}
+	stopCurrent(sources.StateStopped)
+	discardPreloaded()
+	queue.Set(tracks, start)
+	startCurrent()
+}
+
+// PlayShuffled replaces the queue with the tracks in random order.
+func PlayShuffled(tracks []Track) {
+	if len(tracks) == 0 {
+		return
+	}
+	queue.SetShuffle(true)
+	Play(tracks, rand.IntN(len(tracks)))
+}
+
+// Enqueue appends tracks to the queue, starting playback if it was empty.
+func Enqueue(tracks ...Track) {
+	if len(tracks) == 0 {
+		return
+	}
+	wasEmpty := queue.Len() == 0
+	queue.Append(tracks...)
+	if wasEmpty {
+		startCurrent()
+		return
+	}
+	// The preloaded track may no longer be the one that follows.
+	discardPreloaded()
+	notify()
+}
+
+// Current returns the current player state.
+func Current() State {
+	state := State{
+		Volume:      userVolume(),
+		Shuffle:     queue.Shuffle(),
+		Repeat:      queue.Repeat(),
+		HasNext:     queue.HasNext(),
+		HasPrevious: queue.HasPrevious(),
+	}
+	if current != nil {
+		track := current.track
+		state.Track = &track
+		state.Playing = current.media.GetPlaying()
+		state.Position = current.position()
+		state.Duration = current.duration()
+	}
+	return state
+}
+
+// Queued returns the queued tracks in play order and the current position.
+func Queued() ([]Track, int) {
+	return queue.Tracks(), queue.Position()
+}
+
+// TogglePlayPause pauses or resumes playback.
+func TogglePlayPause() {
+	if current == nil {
+		return
+	}
+	if current.media.GetPlaying() {
+		current.media.Pause()
+		sendProgress(current, sources.StatePaused)
+	} else {
+		if current.media.GetEnded() {
+			current.media.Seek(0)
+			scrobbled = false
+		}
+		current.media.Play()
+		sendProgress(current, sources.StatePlaying)
+		startTicker()
+	}
+	notify()
+}
+
+// Next skips to the next track.
+func Next() {
+	if !queue.Next() {
+		return
+	}
+	stopCurrent(sources.StateStopped)
+	discardPreloaded()
+	startCurrent()
+}
+
+// Previous restarts the current track, or goes back one track when playback
+// is near the start.
+func Previous() {
+	if current != nil && current.position() > 3000 {
+		Seek(0)
+		return
+	}
+	if !queue.Previous() {
+		return
+	}
+	stopCurrent(sources.StateStopped)
+	discardPreloaded()
+	startCurrent()
+}
+
+// JumpTo plays the track at the given position in play order.
+func JumpTo(pos int) {
+	if !queue.Jump(pos) {
+		return
+	}
+	stopCurrent(sources.StateStopped)
+	discardPreloaded()
+	startCurrent()
+}
+
+// Seek moves playback to the given position in milliseconds.
+func Seek(positionMs int) {
+	if current == nil || !current.media.IsSeekable() {
+		return
+	}
+	current.media.Seek(int64(positionMs) * 1000)
+	notify()
+}
+
+// Stop ends playback and clears the queue.
+func Stop() {
+	stopCurrent(sources.StateStopped)
+	discardPreloaded()
+	queue.Clear()
+	notify()
+}
+
+// SetShuffle enables or disables shuffle for the rest of the queue.
+func SetShuffle(shuffle bool) {
+	queue.SetShuffle(shuffle)
+	discardPreloaded()
+	notify()
+}
+
+// CycleRepeat switches to the next repeat mode (off, all, one).
+func CycleRepeat() {
+	queue.SetRepeat((queue.Repeat() + 1) % 3)
+	discardPreloaded()
+	notify()
+}
+
+// SetVolume sets the user volume (0–1). Normalization is applied on top.
+func SetVolume(v float64) {
+	volume = min(max(v, 0), 1)
+	preference.General().SetMusicVolume(volume)
+	if current != nil {
+		applyVolume(current)
+	}
+	notify()
+}
+
+func userVolume() float64 {
+	if volume < 0 {
+		volume = preference.General().GetMusicVolume()
+	}
+	if !normalizeWatched {
+		normalizeWatched = true
+		preference.General().OnNormalizeVolumeChanged(func() {
+			if current != nil {
+				applyVolume(current)
+			}
+		})
+	}
+	return volume
+}
+
+// startCurrent begins playback of the queue's current track, reusing the
+// preloaded stream when it matches.
+func startCurrent() {
+	track, ok := queue.Current()
+	if !ok {
+		notify()
+		return
+	}
+
+	if preloaded != nil && preloaded.track.ItemID == track.ItemID &&
+		preloaded.track.Source.ID() == track.Source.ID() {
+		current = preloaded
+		preloaded = nil
+	} else {
+		discardPreloaded()
+		current = openDeck(track)
+	}
+
+	scrobbled = false
+	lastProgress = glib.GetMonotonicTime() / 1000
+	applyVolume(current)
+	current.media.Play()
+	sendProgress(current, sources.StatePlaying)
+	loadDetails(current)
+	startTicker()
+	notify()
+}
+
+// stopCurrent releases the current stream after reporting its final state.
+func stopCurrent(state sources.PlaybackState) {
+	if current == nil {
+		return
+	}
+	finishReporting(current, state)
+	current.close()
+	current = nil
+}
+
+func discardPreloaded() {
+	if preloaded != nil {
+		preloaded.close()
+		preloaded = nil
+	}
+}
+
+// finishReporting sends the final timeline update and scrobbles the track
+// when enough of it was played.
+func finishReporting(d *deck, state sources.PlaybackState) {
+	sendProgress(d, state)
+	if !scrobbled && d.duration() > 0 && float64(d.position())/float64(d.duration()) >= scrobbleThreshold {
+		scrobble(d)
+	}
+}
+
+func scrobble(d *deck) {
+	scrobbled = true
+	src, rk := d.track.Source, d.track.ItemID
+	go func() {
+		if err := src.Scrobble(ctx, rk); err != nil {
+			slog.Error("failed to scrobble track", "ratingKey", rk, "error", err)
+		}
+	}()
+}
+
+func sendProgress(d *deck, state sources.PlaybackState) {
+	durationMs := d.duration()
+	if durationMs <= 0 {
+		return
+	}
+	timeMs := d.position()
+	src, rk := d.track.Source, d.track.ItemID
+	go func() {
+		if err := src.UpdateProgress(ctx, rk, state, timeMs, durationMs); err != nil {
+			slog.Error("failed to update track progress", "ratingKey", rk, "error", err)
+		}
+	}()
+}
+
+func startTicker() {
+	if tickerID != 0 {
+		return
+	}
+	cb := glib.SourceFunc(func(uintptr) bool {
+		if !tick() {
+			tickerID = 0
+			return false
+		}
+		return true
+	})
+	tickerID = glib.TimeoutAdd(tickInterval, &cb, 0)
+}
+
+// tick drives timeline reporting, preloading and track transitions.
+// It returns false once playback has stopped.
+func tick() bool {
+	if current == nil {
+		return false
+	}
+	media := current.media
+
+	if err := media.GetError(); err != nil {
+		slog.Error("audio playback failed", "item", current.track.ItemID, "error", err.Error())
+		stopCurrent(sources.StateStopped)
+		if queue.Next() {
+			discardPreloaded()
+			startCurrent()
+		} else {
+			notify()
+		}
+		return current != nil
+	}
+
+	if media.GetEnded() {
+		finishReporting(current, sources.StateStopped)
+		if !queue.Advance() {
+			// End of the queue: keep the last track loaded so playing
+			// again restarts it.
+			discardPreloaded()
+			notify()
+			return false
+		}
+		current.close()
+		current = nil
+		startCurrent()
+		return true
+	}
+
+	if media.IsPrepared() && !media.GetPlaying() {
+		notify()
+		return false
+	}
+
+	dur := media.GetDuration()
+	ts := media.GetTimestamp()
+
+	nowMs := glib.GetMonotonicTime() / 1000
+	if nowMs-lastProgress >= progressInterval {
+		lastProgress = nowMs
+		sendProgress(current, sources.StatePlaying)
+	}
+
+	if !scrobbled && dur > 0 && float64(ts)/float64(dur) >= scrobbleThreshold {
+		scrobble(current)
+	}
+
+	// Open the next track ahead of time so it is buffered by the time the
+	// current one ends.
+	if preloaded == nil && dur > 0 && dur-ts <= preloadWindow {
+		if next, ok := queue.PeekNext(); ok {
+			preloaded = openDeck(next)
+			preloaded.media.Pause()
+			loadDetails(preloaded)
+		}
+	}
+
+	notify()
+	return true
+}
+
+// applyVolume sets the stream volume from the user volume and the track's
+// replay gain. Album gain is preferred when consecutive tracks come from
+// the same album so relative loudness within the album is preserved.
+func applyVolume(d *deck) {
+	v := userVolume()
+	if preference.General().NormalizeVolume() {
+		v *= normalizationFactor(d.track, queue.albumContext())
+	}
+	d.media.SetVolume(min(max(v, 0), 1))
+}
+
+// normalizationFactor converts the replay gain to a linear factor, limited
+// so the track peak does not clip.
+func normalizationFactor(t Track, albumMode bool) float64 {
+	gain, peak := t.Gain, t.Peak
+	if albumMode && t.AlbumGain != 0 {
+		gain, peak = t.AlbumGain, t.AlbumPeak
+	}
+	if gain == 0 {
+		return 1
+	}
+	factor := math.Pow(10, gain/20)
+	if peak > 0 {
+		factor = min(factor, 1/peak)
+	}
+	return factor
+}
+
+// albumContext reports whether the tracks around the current one belong to
+// the same album, i.e. an album is being played in order.
+func (q *Queue) albumContext() bool {
+	cur, ok := q.Current()
+	if !ok || cur.AlbumKey == "" || q.shuffle {
+		return false
+	}
+	next, ok := q.PeekNext()
+	return ok && next.AlbumKey == cur.AlbumKey
+}
+
+// loadDetails fetches the full track metadata when the queued track lacks
+// stream details (loudness data and lyrics), since list endpoints omit them.
+func loadDetails(d *deck) {
+	if d.track.detailed {
+		return
+	}
+	src, rk := d.track.Source, d.track.ItemID
+	go func() {
+		meta, err := src.GetMetadata(ctx, rk)
+		if err != nil {
+			slog.Debug("audio: failed to fetch track details", "ratingKey", rk, "error", err)
+			return
+		}
+		full, ok := TrackFromMetadata(src, meta)
+		if !ok {
+			return
+		}
+		schwifty.OnMainThreadOncePure(func() {
+			d.track.Gain, d.track.AlbumGain = full.Gain, full.AlbumGain
+			d.track.Peak, d.track.AlbumPeak = full.Peak, full.AlbumPeak
+			d.track.Lyrics = full.Lyrics
+			d.track.detailed = true
+			if d == current {
+				applyVolume(d)
+				notify()
+			}
+		})
+	}()
+}
+
+func notify() {
+	Changed.Notify(Current())
+}
@@ -0,0 +1,305 @@
+package audio
+
+import (
+	"math/rand/v2"
+
+	"github.com/0skillallluck/scanline/app/sources"
+)
+
+// Track is a playable audio item in the queue.
+type Track struct {
+	Source   sources.Source
+	ItemID   sources.ItemID
+	Title    string
+	Artist   string
+	Album    string
+	AlbumKey sources.ItemID // album item ID, used to link from the mini-player
+	Thumb    string         // album art path
+	PartKey  string         // raw media part key (e.g. "/library/parts/12345/file.flac")
+	Duration int            // duration in milliseconds
+
+	// Loudness analysis from the server, used for volume normalization.
+	Gain      float64 // track gain in dB
+	AlbumGain float64 // album gain in dB
+	Peak      float64 // track peak amplitude
+	AlbumPeak float64 // album peak amplitude
+
+	// Lyrics is the preferred lyrics stream, nil when the track has none.
+	Lyrics *sources.Stream
+
+	// detailed is set once stream details (loudness, lyrics) are known;
+	// list endpoints omit them.
+	detailed bool
+}
+
+// TrackFromMetadata builds a queue track from track metadata.
+// It returns false when the item has no playable media part.
+func TrackFromMetadata(src sources.Source, meta *sources.Item) (Track, bool) {
+	if len(meta.Media) == 0 || len(meta.Media[0].Part) == 0 {
+		return Track{}, false
+	}
+
+	artist := meta.OriginalTitle
+	if artist == "" {
+		artist = meta.GrandparentTitle
+	}
+	thumb := meta.ParentThumb
+	if thumb == "" {
+		thumb = meta.Thumb
+	}
+
+	track := Track{
+		Source:   src,
+		ItemID:   meta.ID,
+		Title:    meta.Title,
+		Artist:   artist,
+		Album:    meta.ParentTitle,
+		AlbumKey: meta.ParentID,
+		Thumb:    thumb,
+		PartKey:  meta.Media[0].Part[0].Key,
+		Duration: meta.Duration,
+	}
+	part := &meta.Media[0].Part[0]
+	for _, stream := range part.Stream {
+		if stream.StreamType == sources.StreamAudio {
+			track.Gain = stream.Gain
+			track.AlbumGain = stream.AlbumGain
+			track.Peak = stream.Peak
+			track.AlbumPeak = stream.AlbumPeak
+			break
+		}
+	}
+	if lyrics := part.LyricsStream(); lyrics != nil {
+		stream := *lyrics
+		track.Lyrics = &stream
+	}
+	track.detailed = len(part.Stream) > 0
+	return track, true
+}
+
+// RepeatMode controls what happens when the end of the queue is reached.
+type RepeatMode int
+
+const (
+	RepeatOff RepeatMode = iota // stop after the last track
+	RepeatAll                   // start over from the first track
+	RepeatOne                   // replay the current track
+)
+
+// Queue is an ordered list of tracks with shuffle and repeat support.
+// It is not safe for concurrent use; the player only touches it from the
+// main thread.
+type Queue struct {
+	tracks  []Track
+	order   []int // play order as indices into tracks
+	pos     int   // position in order, -1 when empty
+	shuffle bool
+	repeat  RepeatMode
+}
+
+// Set replaces the queue contents and starts at the given track index.
+func (q *Queue) Set(tracks []Track, start int) {
+	q.tracks = tracks
+	q.order = make([]int, len(tracks))
+	for i := range q.order {
+		q.order[i] = i
+	}
+	q.pos = -1
+	if len(tracks) == 0 {
+		return
+	}
+	start = min(max(start, 0), len(tracks)-1)
+	q.pos = start
+	if q.shuffle {
+		q.reshuffle()
+	}
+}
+
+// Append adds tracks to the end of the queue.
+func (q *Queue) Append(tracks ...Track) {
+	for _, t := range tracks {
+		q.tracks = append(q.tracks, t)
+		q.order = append(q.order, len(q.tracks)-1)
+	}
+	if q.pos < 0 && len(q.tracks) > 0 {
+		q.pos = 0
+	}
+}
+
+// Clear empties the queue.
+func (q *Queue) Clear() {
+	q.tracks = nil
+	q.order = nil
+	q.pos = -1
+}
+
+// Len returns the number of tracks in the queue.
+func (q *Queue) Len() int {
+	return len(q.tracks)
+}
+
+// Position returns the current position in play order, or -1 when empty.
+func (q *Queue) Position() int {
+	if len(q.tracks) == 0 {
+		return -1
+	}
+	return q.pos
+}
+
+// Tracks returns the tracks in play order.
+func (q *Queue) Tracks() []Track {
+	tracks := make([]Track, len(q.order))
+	for i, idx := range q.order {
+		tracks[i] = q.tracks[idx]
+	}
+	return tracks
+}
+
+// Current returns the track at the current position.
+func (q *Queue) Current() (Track, bool) {
+	if q.pos < 0 || q.pos >= len(q.order) {
+		return Track{}, false
+	}
+	return q.tracks[q.order[q.pos]], true
+}
+
+// Jump moves to the given position in play order.
+func (q *Queue) Jump(pos int) bool {
+	if pos < 0 || pos >= len(q.order) {
+		return false
+	}
+	q.pos = pos
+	return true
+}
+
+// PeekNext returns the track that Advance would move to, without moving.
+func (q *Queue) PeekNext() (Track, bool) {
+	pos, ok := q.advancePos()
+	if !ok {
+		return Track{}, false
+	}
+	return q.tracks[q.order[pos]], true
+}
+
+// Advance moves to the track that follows when the current one finishes,
+// honoring the repeat mode. It returns false at the end of the queue.
+func (q *Queue) Advance() bool {
+	pos, ok := q.advancePos()
+	if ok {
+		q.pos = pos
+	}
+	return ok
+}
+
+func (q *Queue) advancePos() (int, bool) {
+	if len(q.order) == 0 {
+		return 0, false
+	}
+	if q.repeat == RepeatOne {
+		return q.pos, true
+	}
+	return q.nextPos()
+}
+
+// Next skips to the following track. Unlike Advance it never repeats the
+// current track, but still wraps around with RepeatAll.
+func (q *Queue) Next() bool {
+	pos, ok := q.nextPos()
+	if ok {
+		q.pos = pos
+	}
+	return ok
+}
+
+func (q *Queue) nextPos() (int, bool) {
+	if len(q.order) == 0 {
+		return 0, false
+	}
+	if q.pos+1 < len(q.order) {
+		return q.pos + 1, true
+	}
+	if q.repeat != RepeatOff {
+		return 0, true
+	}
+	return 0, false
+}
+
+// Previous moves to the preceding track, wrapping around with RepeatAll.
+func (q *Queue) Previous() bool {
+	if len(q.order) == 0 {
+		return false
+	}
+	if q.pos > 0 {
+		q.pos--
+		return true
+	}
+	if q.repeat != RepeatOff {
+		q.pos = len(q.order) - 1
+		return true
+	}
+	return false
+}
+
+// HasNext reports whether Next would move to another track.
+func (q *Queue) HasNext() bool {
+	_, ok := q.nextPos()
+	return ok
+}
+
+// HasPrevious reports whether Previous would move to another track.
+func (q *Queue) HasPrevious() bool {
+	return len(q.order) > 0 && (q.pos > 0 || q.repeat != RepeatOff)
+}
+
+// Shuffle reports whether shuffle is enabled.
+func (q *Queue) Shuffle() bool {
+	return q.shuffle
+}
+
+// SetShuffle enables or disables shuffle. The current track keeps playing;
+// enabling shuffle randomizes the remaining order, disabling it restores
+// the original order.
+func (q *Queue) SetShuffle(shuffle bool) {
+	if q.shuffle == shuffle {
+		return
+	}
+	q.shuffle = shuffle
+	if len(q.tracks) == 0 {
+		return
+	}
+	if shuffle {
+		q.reshuffle()
+		return
+	}
+	current := q.order[q.pos]
+	for i := range q.order {
+		q.order[i] = i
+	}
+	q.pos = current
+}
+
+// reshuffle randomizes the play order with the current track first.
+func (q *Queue) reshuffle() {
+	current := q.order[q.pos]
+	rest := make([]int, 0, len(q.tracks)-1)
+	for i := range q.tracks {
+		if i != current {
+			rest = append(rest, i)
+		}
+	}
+	rand.Shuffle(len(rest), func(i, j int) {
+		rest[i], rest[j] = rest[j], rest[i]
+	})
+	q.order = append([]int{current}, rest...)
+	q.pos = 0
+}
+
+// Repeat returns the repeat mode.
+func (q *Queue) Repeat() RepeatMode {
+	return q.repeat
+}
+
+// SetRepeat sets the repeat mode.
+func (q *Queue) SetRepeat(mode RepeatMode) {
+	q.repeat = mode
+}
@@ -13,7 +13,7 @@ import (
 
 // MediaInfo creates an HStack of info cards showing resolution, video codec, audio, and container.
 // Returns nil if no media info is available.
-func MediaInfo(media []sources.Media) schwifty.Box {
+func MediaInfo(media []sources.MediaVersion) schwifty.Box {
 	if len(media) == 0 {
 		return nil
 	}
@@ -14,27 +14,36 @@ func poster[T any](title string, subTitle schwifty.Widgetable[T], coverURL strin
 	return posterWithProgress(title, subTitle, coverURL, 0)
 }
 
+// squarePoster creates a poster card with square artwork, used for music.
+func squarePoster[T any](title string, subTitle schwifty.Widgetable[T], coverURL string) schwifty.Button {
+	return posterSized(title, subTitle, coverURL, 180, 180, 0)
+}
+
 func posterWithProgress[T any](title string, subTitle schwifty.Widgetable[T], coverURL string, progress float64) schwifty.Button {
+	return posterSized(title, subTitle, coverURL, 180, 270, progress)
+}
+
+func posterSized[T any](title string, subTitle schwifty.Widgetable[T], coverURL string, width, height int32, progress float64) schwifty.Button {
 	picture := Picture().
-		SizeRequest(180, 270).
+		SizeRequest(width, height).
 		FromPaintable(gdk.NewTextureFromResource("/dev/skillless/Scanline/icons/scalable/state/missing-album.svg")).
 		ConnectRealize(func(w gtk.Widget) {
 			if preference.Performance().AllowPreviewImages() {
-				imageutils.LoadIntoPictureScaled(coverURL, 180, 270, gtk.PictureNewFromInternalPtr(w.Ptr))
+				imageutils.LoadIntoPictureScaled(coverURL, width, height, gtk.PictureNewFromInternalPtr(w.Ptr))
 			}
 		})
 
 	var image any
 	if progress > 0 {
 		progressBar := Box(gtk.OrientationHorizontalValue).
-			SizeRequest(int32(180*progress), 4).
+			SizeRequest(int32(float64(width)*progress), 4).
 			VAlign(gtk.AlignEndValue).
 			HAlign(gtk.AlignStartValue).
 			CSS("box { background-color: @accent_bg_color; }")
 
 		image = Bin().
 			Child(Overlay(picture).AddOverlay(progressBar)).
-			SizeRequest(180, 270).
+			SizeRequest(width, height).
 			CornerRadius(10).
 			Overflow(gtk.OverflowHiddenValue)
 	} else {
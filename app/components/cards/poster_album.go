@@ -0,0 +1,30 @@
+package cards
+
+import (
+	"strconv"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"github.com/0skillallluck/scanline/app/sources"
+)
+
+// NewAlbumPoster creates a new poster card for a music album.
+// The subtitle shows the album artist, or the year when showArtist is false
+// (e.g. on the artist's own discography).
+func NewAlbumPoster(metadata *sources.Item, coverURL, serverID string, showArtist bool) schwifty.Button {
+	subtitle := metadata.ParentTitle
+	if !showArtist || subtitle == "" {
+		subtitle = ""
+		if metadata.Year > 0 {
+			subtitle = strconv.Itoa(metadata.Year)
+		}
+	}
+
+	return squarePoster(
+		metadata.Title,
+		subTitle(subtitle),
+		coverURL,
+	).
+		ActionName("win.route.album").
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
+}
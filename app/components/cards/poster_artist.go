@@ -0,0 +1,24 @@
+package cards
+
+import (
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+// NewArtistPoster creates a new poster card for a music artist.
+func NewArtistPoster(metadata *sources.Item, coverURL, serverID string) schwifty.Button {
+	subtitle := gettext.Get("Artist")
+	if metadata.ChildCount > 0 {
+		subtitle = gettext.GetN("%d Album", "%d Albums", metadata.ChildCount, metadata.ChildCount)
+	}
+
+	return squarePoster(
+		metadata.Title,
+		subTitle(subtitle),
+		coverURL,
+	).
+		ActionName("win.route.artist").
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
+}
@@ -10,7 +10,7 @@ import (
 )
 
 // NewEpisodePoster creates a new poster card for a tv-show episode.
-func NewEpisodePoster(metadata *sources.Metadata, coverURL, serverID string) schwifty.Button {
+func NewEpisodePoster(metadata *sources.Item, coverURL, serverID string) schwifty.Button {
 	return poster(
 		metadata.GrandparentTitle,
 		VStack(
@@ -20,5 +20,5 @@ func NewEpisodePoster(metadata *sources.Metadata, coverURL, serverID string) sch
 		coverURL,
 	).
 		ActionName("win.route.episode").
-		ActionTargetValue(glib.NewVariantString(serverID + "/" + metadata.RatingKey))
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
 }
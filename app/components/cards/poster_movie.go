@@ -9,12 +9,12 @@ import (
 )
 
 // NewMoviePoster creates a new poster card for a movie.
-func NewMoviePoster(metadata *sources.Metadata, coverURL, serverID string) schwifty.Button {
+func NewMoviePoster(metadata *sources.Item, coverURL, serverID string) schwifty.Button {
 	return poster(
 		metadata.Title,
 		subTitle(strconv.Itoa(metadata.Year)),
 		coverURL,
 	).
 		ActionName("win.route.movie").
-		ActionTargetValue(glib.NewVariantString(serverID + "/" + metadata.RatingKey))
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
 }
@@ -0,0 +1,51 @@
+package cards
+
+import (
+	"time"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+// NewPhotoAlbumPoster creates a new poster card for a photo album.
+func NewPhotoAlbumPoster(metadata *sources.Item, coverURL, serverID string) schwifty.Button {
+	subtitle := gettext.Get("Album")
+	if metadata.LeafCount > 0 {
+		subtitle = gettext.GetN("%d Photo", "%d Photos", metadata.LeafCount, metadata.LeafCount)
+	}
+
+	return squarePoster(
+		metadata.Title,
+		subTitle(subtitle),
+		coverURL,
+	).
+		ActionName("win.route.photoalbum").
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
+}
+
+// NewPhotoPoster creates a new poster card for a photo.
+// Activating the card opens the photo viewer.
+func NewPhotoPoster(metadata *sources.Item, coverURL, serverID string) schwifty.Button {
+	return squarePoster(
+		metadata.Title,
+		subTitle(FormatPhotoDate(metadata.OriginallyAvailableAt)),
+		coverURL,
+	).
+		ActionName("win.route.photo").
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
+}
+
+// FormatPhotoDate formats a "YYYY-MM-DD" capture date as "Jan 2, 2006".
+// Unparseable dates are returned unchanged.
+func FormatPhotoDate(date string) string {
+	if len(date) < 10 {
+		return date
+	}
+	t, err := time.Parse("2006-01-02", date[:10])
+	if err != nil {
+		return date
+	}
+	return t.Format("Jan 2, 2006")
+}
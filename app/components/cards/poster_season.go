@@ -8,7 +8,7 @@ import (
 )
 
 // NewSeasonPoster creates a new poster card for a tv-show season.
-func NewSeasonPoster(metadata *sources.Metadata, coverURL, serverID string) schwifty.Button {
+func NewSeasonPoster(metadata *sources.Item, coverURL, serverID string) schwifty.Button {
 	var progress float64
 	if metadata.LeafCount > 0 {
 		progress = float64(metadata.ViewedLeafCount) / float64(metadata.LeafCount)
@@ -21,5 +21,5 @@ func NewSeasonPoster(metadata *sources.Metadata, coverURL, serverID string) schw
 		progress,
 	).
 		ActionName("win.route.season").
-		ActionTargetValue(glib.NewVariantString(serverID + "/" + metadata.RatingKey))
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
 }
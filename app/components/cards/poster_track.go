@@ -0,0 +1,24 @@
+package cards
+
+import (
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"github.com/0skillallluck/scanline/app/sources"
+)
+
+// NewTrackPoster creates a new poster card for a music track.
+// Activating the card opens the track page.
+func NewTrackPoster(metadata *sources.Item, coverURL, serverID string) schwifty.Button {
+	artist := metadata.OriginalTitle
+	if artist == "" {
+		artist = metadata.GrandparentTitle
+	}
+
+	return squarePoster(
+		metadata.Title,
+		subTitle(artist+" · "+metadata.ParentTitle),
+		coverURL,
+	).
+		ActionName("win.route.track").
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
+}
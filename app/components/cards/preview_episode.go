@@ -10,7 +10,7 @@ import (
 )
 
 // NewEpisodePreviewCard creates a new 16:9 preview card for a tv-show episode.
-func NewEpisodePreviewCard(metadata *sources.Metadata, artURL, serverID string) schwifty.Button {
+func NewEpisodePreviewCard(metadata *sources.Item, artURL, serverID string) schwifty.Button {
 	var progress float64
 	if metadata.Duration > 0 && metadata.ViewOffset > 0 {
 		progress = float64(metadata.ViewOffset) / float64(metadata.Duration)
@@ -26,5 +26,5 @@ func NewEpisodePreviewCard(metadata *sources.Metadata, artURL, serverID string)
 		progress,
 	).
 		ActionName("win.route.episode").
-		ActionTargetValue(glib.NewVariantString(serverID + "/" + metadata.RatingKey))
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
 }
@@ -9,7 +9,7 @@ import (
 )
 
 // NewMoviePreviewCard creates a new 16:9 preview card for a movie.
-func NewMoviePreviewCard(metadata *sources.Metadata, artURL, serverID string) schwifty.Button {
+func NewMoviePreviewCard(metadata *sources.Item, artURL, serverID string) schwifty.Button {
 	var progress float64
 	if metadata.Duration > 0 && metadata.ViewOffset > 0 {
 		progress = float64(metadata.ViewOffset) / float64(metadata.Duration)
@@ -22,5 +22,5 @@ func NewMoviePreviewCard(metadata *sources.Metadata, artURL, serverID string) sc
 		progress,
 	).
 		ActionName("win.route.movie").
-		ActionTargetValue(glib.NewVariantString(serverID + "/" + metadata.RatingKey))
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
 }
@@ -13,7 +13,7 @@ import (
 	"github.com/0skillallluck/scanline/utils/imageutils"
 )
 
-func NewSeasonEpisode(metadata *sources.Metadata, coverURL, serverID string) schwifty.Button {
+func NewSeasonEpisode(metadata *sources.Item, coverURL, serverID string) schwifty.Button {
 	var progress float64
 	if metadata.ViewCount > 0 && metadata.ViewOffset == 0 {
 		progress = 1.0
@@ -64,5 +64,5 @@ func NewSeasonEpisode(metadata *sources.Metadata, coverURL, serverID string) sch
 		VExpand(false).
 		WithCSSClass("flat").
 		ActionName("win.route.episode").
-		ActionTargetValue(glib.NewVariantString(serverID + "/" + metadata.RatingKey))
+		ActionTargetValue(glib.NewVariantString(serverID + "/" + string(metadata.ID)))
 }
@@ -10,7 +10,7 @@ import (
 
 // MetadataCard creates the appropriate card widget for the given metadata type.
 // Returns the card widget and true if successful, nil and false for unsupported types.
-func MetadataCard(meta *sources.Metadata, coverURL func(string) string, context, serverID string) (schwifty.BaseWidgetable, bool) {
+func MetadataCard(meta *sources.Item, coverURL func(string) string, context, serverID string) (schwifty.BaseWidgetable, bool) {
 	switch meta.Type {
 	case "movie":
 		return cards.NewMoviePoster(meta, coverURL(meta.Thumb), serverID), true
@@ -20,6 +20,18 @@ func MetadataCard(meta *sources.Metadata, coverURL func(string) string, context,
 		return cards.NewSeasonPoster(meta, coverURL(meta.Thumb), serverID), true
 	case "episode":
 		return cards.NewEpisodePoster(meta, coverURL(meta.GrandparentThumb), serverID), true
+	case "artist":
+		return cards.NewArtistPoster(meta, coverURL(meta.Thumb), serverID), true
+	case "album":
+		return cards.NewAlbumPoster(meta, coverURL(meta.Thumb), serverID, true), true
+	case "track":
+		return cards.NewTrackPoster(meta, coverURL(meta.ParentThumb), serverID), true
+	case "photo", "photoalbum":
+		// Photo albums share the "photo" type with photos but carry no media.
+		if meta.Type == "photoalbum" || len(meta.Media) == 0 {
+			return cards.NewPhotoAlbumPoster(meta, coverURL(meta.Thumb), serverID), true
+		}
+		return cards.NewPhotoPoster(meta, coverURL(meta.Thumb), serverID), true
 	default:
 		slog.Debug("unsupported metadata type", "type", meta.Type, "context", context)
 		return nil, false
@@ -28,7 +40,7 @@ func MetadataCard(meta *sources.Metadata, coverURL func(string) string, context,
 
 // RenderHubMetadata appends the appropriate card type to the list based on metadata type.
 // Returns true if an item was added, false otherwise.
-func RenderHubMetadata(list *HorizontalList, meta *sources.Metadata, coverURL func(string) string, hubTitle, serverID string) bool {
+func RenderHubMetadata(list *HorizontalList, meta *sources.Item, coverURL func(string) string, hubTitle, serverID string) bool {
 	if card, ok := MetadataCard(meta, coverURL, hubTitle, serverID); ok {
 		list.Append(card)
 		return true
@@ -40,11 +52,28 @@ func RenderHubMetadata(list *HorizontalList, meta *sources.Metadata, coverURL fu
 // Returns true if at least one item was added.
 func RenderHub(list *HorizontalList, hub *sources.Hub, coverURL func(string) string, serverID string) bool {
 	hasItems := false
-	for i := range hub.Metadata {
-		meta := &hub.Metadata[i]
+	for i := range hub.Items {
+		meta := &hub.Items[i]
 		if RenderHubMetadata(list, meta, coverURL, hub.Title, serverID) {
 			hasItems = true
 		}
 	}
 	return hasItems
 }
+
+// RenderMergedHub renders the best copy of each item in a merged hub to the
+// given list. coverURL resolves image paths on the server of a copy.
+// Returns true if at least one item was added.
+func RenderMergedHub(list *HorizontalList, hub *sources.MergedHub, coverURL func(serverID, thumb string) string) bool {
+	hasItems := false
+	for i := range hub.Items {
+		best := hub.Items[i].Best()
+		serverCoverURL := func(thumb string) string {
+			return coverURL(best.ServerID, thumb)
+		}
+		if RenderHubMetadata(list, &best.Item, serverCoverURL, hub.Title, best.ServerID) {
+			hasItems = true
+		}
+	}
+	return hasItems
+}
@@ -10,13 +10,13 @@ import (
 // ResolveNextEpisode finds the episode after currentEp.
 // It checks the same season first, then the next season of the same show.
 // Returns nil if there is no next episode (last episode of the show).
-func ResolveNextEpisode(ctx context.Context, src sources.Source, currentEp *sources.Metadata) *NextEpisodeInfo {
+func ResolveNextEpisode(ctx context.Context, src sources.Source, currentEp *sources.Item) *NextEpisodeInfo {
 	if currentEp == nil || currentEp.Type != "episode" {
 		return nil
 	}
 
 	// Try to find the next episode in the same season.
-	siblings, err := src.GetChildren(ctx, currentEp.ParentRatingKey)
+	siblings, err := src.GetChildren(ctx, currentEp.ParentID)
 	if err != nil {
 		slog.Debug("next episode: failed to fetch siblings", "error", err)
 		return nil
@@ -30,11 +30,11 @@ func ResolveNextEpisode(ctx context.Context, src sources.Source, currentEp *sour
 	}
 
 	// Last episode of the season — try the next season.
-	if currentEp.GrandparentRatingKey == "" {
+	if currentEp.GrandparentID == "" {
 		return nil
 	}
 
-	seasons, err := src.GetChildren(ctx, currentEp.GrandparentRatingKey)
+	seasons, err := src.GetChildren(ctx, currentEp.GrandparentID)
 	if err != nil {
 		slog.Debug("next episode: failed to fetch seasons", "error", err)
 		return nil
@@ -42,7 +42,7 @@ func ResolveNextEpisode(ctx context.Context, src sources.Source, currentEp *sour
 
 	for i := range seasons {
 		if seasons[i].Index == currentEp.ParentIndex+1 && seasons[i].Index != 0 {
-			episodes, err := src.GetChildren(ctx, seasons[i].RatingKey)
+			episodes, err := src.GetChildren(ctx, seasons[i].ID)
 			if err != nil || len(episodes) == 0 {
 				return nil
 			}
@@ -54,14 +54,14 @@ func ResolveNextEpisode(ctx context.Context, src sources.Source, currentEp *sour
 	return nil
 }
 
-func metadataToNextInfo(ep *sources.Metadata) *NextEpisodeInfo {
+func metadataToNextInfo(ep *sources.Item) *NextEpisodeInfo {
 	if len(ep.Media) == 0 || len(ep.Media[0].Part) == 0 {
 		return nil
 	}
 	return &NextEpisodeInfo{
 		Title:      ep.Title,
 		PartKey:    ep.Media[0].Part[0].Key,
-		RatingKey:  ep.RatingKey,
+		ItemID:     ep.ID,
 		Media:      ep.Media,
 		ViewOffset: ep.ViewOffset,
 		Metadata:   ep,
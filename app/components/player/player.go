@@ -17,6 +17,7 @@ import (
 	"github.com/0skillallluck/scanline/app/preference"
 	"github.com/0skillallluck/scanline/app/router"
 	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/signals"
 	"github.com/google/uuid"
 )
 
@@ -24,27 +25,41 @@ import (
 type NextEpisodeInfo struct {
 	Title      string
 	PartKey    string
-	RatingKey  string
-	Media      []sources.Media
+	ItemID     sources.ItemID
+	Media      []sources.MediaVersion
 	ViewOffset int
 	// Metadata is the full metadata of this next episode, used to resolve
 	// the episode after it for chaining.
-	Metadata *sources.Metadata
+	Metadata *sources.Item
 }
 
+// Stop identifies an item whose playback stopped.
+type Stop struct {
+	ServerID string
+	ItemID   sources.ItemID
+}
+
+// Stopped is notified after playback of an item stopped and its final
+// progress was reported.
+var Stopped = signals.NewStatelessSignal[Stop]()
+
 // PlayerParams configures a new player window.
 type PlayerParams struct {
 	Ctx        context.Context
 	Title      string
 	PartKey    string // raw media part key (e.g. "/library/parts/12345/file.mkv")
 	Window     *gtk.Window
-	RatingKey  string          // metadata ratingKey
-	Media      []sources.Media // full Media array from Metadata
-	Source     sources.Source  // the source for this playback
-	ViewOffset int             // resume position in milliseconds
+	ItemID     sources.ItemID
+	Media      []sources.MediaVersion // full Media array from Metadata
+	Source     sources.Source         // the source for this playback
+	ViewOffset int                    // resume position in milliseconds
 
 	// NextEpisode is the pre-resolved next episode (nil for movies or last episode).
 	NextEpisode *NextEpisodeInfo
+
+	// StreamURL is a pre-resolved stream URL, used for live TV. It skips
+	// playback resolution, and no progress is reported for the stream.
+	StreamURL string
 }
 
 // NewPlayer creates a video player with overlay controls.
@@ -56,6 +71,7 @@ func NewPlayer(params PlayerParams) {
 	ctx, ctxCancel := context.WithCancel(params.Ctx)
 
 	windowed := preference.Experimental().EnableWindowedPlayer()
+	live := params.StreamURL != ""
 
 	// In fullscreen mode we create a separate modal window.
 	// In windowed mode we reuse the existing main window.
@@ -101,7 +117,7 @@ func NewPlayer(params PlayerParams) {
 	var lastProgressUpdate atomic.Int64 // monotonic ms of last progress report
 
 	sendProgress := func(state sources.PlaybackState) {
-		if media == nil {
+		if media == nil || live {
 			return
 		}
 		ts := media.GetTimestamp()
@@ -112,7 +128,7 @@ func NewPlayer(params PlayerParams) {
 		// GTK uses microseconds; Plex uses milliseconds
 		timeMs := int(ts / 1000)
 		durationMs := int(dur / 1000)
-		rk := params.RatingKey
+		rk := params.ItemID
 		go func() {
 			if err := src.UpdateProgress(ctx, rk, state, timeMs, durationMs); err != nil {
 				slog.Error("failed to update progress", "error", err)
@@ -180,7 +196,7 @@ func NewPlayer(params PlayerParams) {
 	// --- Center playback controls ---
 	var playing atomic.Bool
 	var playPauseBtn *gtk.Button
-	var currentTranscodeParams *sources.TranscodeParams // nil = direct play
+	var currentRequest *sources.PlaybackRequest // nil = direct play
 
 	// doSeek handles seeking in both direct play and transcoded modes
 	doSeek := func(targetMicroseconds int64) {
@@ -188,24 +204,23 @@ func NewPlayer(params PlayerParams) {
 			return
 		}
 
-		if currentTranscodeParams == nil {
+		if currentRequest == nil {
 			// Direct play - simple seek
 			media.Seek(targetMicroseconds)
 			return
 		}
 
 		// Transcoded mode - restart stream at new position
-		offsetSeconds := int(targetMicroseconds / 1000000)
-		params := *currentTranscodeParams
-		params.Offset = offsetSeconds
+		req := *currentRequest
+		req.Offset = int(targetMicroseconds / 1000000)
 
 		go func() {
-			q := src.BuildTranscodeQuery(params)
-			if err := src.MakeTranscodeDecision(ctx, q); err != nil {
+			decision, err := src.DecidePlayback(ctx, req)
+			if err != nil {
 				slog.Error("player: seek decision failed", "error", err)
 				return
 			}
-			newURL := src.TranscodeStartURL(q)
+			newURL := decision.URL
 			schwifty.OnMainThreadOncePure(func() {
 				vol := media.GetVolume()
 				media.Pause()
@@ -346,9 +361,9 @@ func NewPlayer(params PlayerParams) {
 	// --- Settings popover (quality, audio, subtitles) ---
 	var settingsPopover *gtk.Popover
 	if len(params.Media) > 0 && len(params.Media[0].Part) > 0 {
-		settingsPopover = buildSettingsPopover(params, src, sessionID, func(newURL string, transcodeParams *sources.TranscodeParams) {
-			currentTranscodeParams = transcodeParams // Track current transcode state
-			slog.Debug("player: switching stream", "url", newURL, "transcoding", transcodeParams != nil)
+		settingsPopover = buildSettingsPopover(params, src, sessionID, func(newURL string, req *sources.PlaybackRequest) {
+			currentRequest = req // Track current transcode state
+			slog.Debug("player: switching stream", "url", newURL, "transcoding", req != nil)
 
 			var seekPos int64
 			var vol float64
@@ -375,7 +390,7 @@ func NewPlayer(params PlayerParams) {
 			}
 
 			// Poll until stream is prepared, then seek to saved position (only for direct play)
-			if seekPos > 0 && transcodeParams == nil {
+			if seekPos > 0 && req == nil {
 				seekCb := glib.SourceFunc(func(uintptr) bool {
 					if err := media.GetError(); err != nil {
 						slog.Error("player: stream error after switch", "error", err.Error())
@@ -487,7 +502,7 @@ func NewPlayer(params PlayerParams) {
 				Title:       nextInfo.Title,
 				PartKey:     nextInfo.PartKey,
 				Window:      params.Window,
-				RatingKey:   nextInfo.RatingKey,
+				ItemID:      nextInfo.ItemID,
 				Media:       nextInfo.Media,
 				Source:      src,
 				ViewOffset:  nextInfo.ViewOffset,
@@ -706,13 +721,13 @@ func NewPlayer(params PlayerParams) {
 			remainingTimeLabel.SetText("-" + formatMicroseconds(remaining))
 		}
 		// Periodic progress reporting (every 10 seconds while playing)
-		if playing.Load() && dur > 0 {
+		if playing.Load() && dur > 0 && !live {
 			nowMs := glib.GetMonotonicTime() / 1000
 			if nowMs-lastProgressUpdate.Load() >= 10000 {
 				lastProgressUpdate.Store(nowMs)
 				timeMs := int(ts / 1000)
 				durationMs := int(dur / 1000)
-				rk := params.RatingKey
+				rk := params.ItemID
 				go func() {
 					if err := src.UpdateProgress(ctx, rk, sources.StatePlaying, timeMs, durationMs); err != nil {
 						slog.Error("failed to update progress", "error", err)
@@ -783,20 +798,25 @@ func NewPlayer(params PlayerParams) {
 		closed.Store(true)
 		if media != nil {
 			media.Pause()
+		}
+		if media != nil && !live {
 			dur := media.GetDuration()
 			ts := media.GetTimestamp()
 			if dur > 0 {
 				timeMs := int(ts / 1000)
 				durationMs := int(dur / 1000)
-				if err := src.UpdateProgress(ctx, params.RatingKey, sources.StateStopped, timeMs, durationMs); err != nil {
+				if err := src.UpdateProgress(ctx, params.ItemID, sources.StateStopped, timeMs, durationMs); err != nil {
 					slog.Error("failed to send final progress", "error", err)
 				}
 			}
 			if dur > 0 && ts > 0 && float64(ts)/float64(dur) > 0.9 {
-				if err := src.Scrobble(ctx, params.RatingKey); err != nil {
+				if err := src.Scrobble(ctx, params.ItemID); err != nil {
 					slog.Error("failed to scrobble", "error", err)
 				}
 			}
+			if dur > 0 {
+				Stopped.Notify(Stop{ServerID: src.ID(), ItemID: params.ItemID})
+			}
 		}
 		ctxCancel()
 		if id := tickerID.Load(); id != 0 {
@@ -855,7 +875,7 @@ func NewPlayer(params PlayerParams) {
 	// Resolve credits markers in the background for next-episode button timing
 	if params.NextEpisode != nil {
 		go func() {
-			markers, err := src.GetMarkers(ctx, params.RatingKey)
+			markers, err := src.GetMarkers(ctx, params.ItemID)
 			if err != nil {
 				slog.Debug("player: failed to fetch markers", "error", err)
 				creditsStartMs.Store(-1) // fallback to 90%
@@ -873,7 +893,20 @@ func NewPlayer(params PlayerParams) {
 
 	// Resolve playback URL via decision endpoint, then start playback
 	go func() {
-		streamURL := src.ResolvePlaybackURL(ctx, params.PartKey, params.RatingKey, sessionID)
+		streamURL := params.StreamURL
+		if !live {
+			decision, err := src.DecidePlayback(ctx, sources.PlaybackRequest{
+				ItemID:    params.ItemID,
+				PartKey:   params.PartKey,
+				SessionID: sessionID,
+			})
+			if err != nil {
+				slog.Error("player: playback decision failed", "error", err)
+				streamURL = src.StreamURL(params.PartKey)
+			} else {
+				streamURL = decision.URL
+			}
+		}
 		schwifty.OnMainThreadOncePure(func() {
 			if closed.Load() {
 				return
@@ -37,9 +37,9 @@ type settingsState struct {
 	subtitleStreamIDs []int // index 0 = "None" (ID 0), rest from metadata
 }
 
-// transcodeParams builds TranscodeParams from UI selections.
+// playbackRequest builds a transcode request from UI selections.
 // Returns nil if direct play should be used instead.
-func (s *settingsState) transcodeParams(qualityIdx, audioIdx, subtitleIdx int) *sources.TranscodeParams {
+func (s *settingsState) playbackRequest(qualityIdx, audioIdx, subtitleIdx int) *sources.PlaybackRequest {
 	preset := qualityPresets[qualityIdx]
 	audioID := 0
 	if audioIdx >= 0 && audioIdx < len(s.audioStreamIDs) {
@@ -55,9 +55,11 @@ func (s *settingsState) transcodeParams(qualityIdx, audioIdx, subtitleIdx int) *
 		return nil
 	}
 
-	return &sources.TranscodeParams{
-		RatingKey:         s.params.RatingKey,
+	return &sources.PlaybackRequest{
+		ItemID:            s.params.ItemID,
+		PartKey:           s.params.PartKey,
 		SessionID:         s.sessionID,
+		Transcode:         true,
 		DirectStreamAudio: preset.DirectPlay,
 		MaxBitrate:        preset.MaxBitrate,
 		MaxResolution:     preset.MaxResolution,
@@ -80,7 +82,7 @@ func buildSettingsPopover(
 	params PlayerParams,
 	src sources.Source,
 	sessionID string,
-	onChanged func(newURL string, transcodeParams *sources.TranscodeParams),
+	onChanged func(newURL string, req *sources.PlaybackRequest),
 ) *gtk.Popover {
 	streams := params.Media[0].Part[0].Stream
 
@@ -96,7 +98,7 @@ func buildSettingsPopover(
 	var selectedAudio uint32
 	var audioIdx uint32
 	for i, s := range streams {
-		if s.StreamType == 2 { // audio
+		if s.StreamType == sources.StreamAudio {
 			audioLabels = append(audioLabels, streamLabel(s, i))
 			audioStreamIDs = append(audioStreamIDs, s.ID)
 			if s.Selected {
@@ -112,7 +114,7 @@ func buildSettingsPopover(
 	var selectedSubtitle uint32
 	var subtitleIdx uint32
 	for i, s := range streams {
-		if s.StreamType == 3 { // subtitle
+		if s.StreamType == sources.StreamSubtitle {
 			subtitleLabels = append(subtitleLabels, streamLabel(s, i))
 			subtitleStreamIDs = append(subtitleStreamIDs, s.ID)
 			subtitleIdx++
@@ -166,23 +168,22 @@ func buildSettingsPopover(
 			"subtitleIdx", si,
 		)
 
-		params := state.transcodeParams(qi, ai, si)
-		if params == nil {
+		req := state.playbackRequest(qi, ai, si)
+		if req == nil {
 			// Direct play
 			onChanged(state.source.StreamURL(state.params.PartKey), nil)
 			return
 		}
 
 		// Transcode: call decision endpoint first (in background), then switch stream
-		q := state.source.BuildTranscodeQuery(*params)
-		startURL := state.source.TranscodeStartURL(q)
 		go func() {
-			if err := state.source.MakeTranscodeDecision(context.Background(), q); err != nil {
+			decision, err := state.source.DecidePlayback(context.Background(), *req)
+			if err != nil {
 				slog.Error("player: decision call failed", "error", err)
 				return
 			}
 			schwifty.OnMainThreadOncePure(func() {
-				onChanged(startURL, params)
+				onChanged(decision.URL, req)
 			})
 		}()
 	}
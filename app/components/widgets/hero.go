@@ -2,7 +2,6 @@ package widgets
 
 import (
 	"fmt"
-	"strconv"
 
 	"codeberg.org/dergs/tonearm/pkg/schwifty"
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
@@ -249,12 +248,12 @@ func HeroContent(params HeroContentParams) schwifty.Box {
 				actionName = "win.route.cast"
 			}
 			for j, tag := range row.Tags {
-				name := tag.Tag
+				name := tag.Name
 				if j < len(row.Tags)-1 {
 					name += ","
 				}
 				tagLabel := Label(name)
-				rowBox = rowBox.Append(linkButton(tagLabel, actionName, row.ServerID+"/"+strconv.Itoa(tag.ID)))
+				rowBox = rowBox.Append(linkButton(tagLabel, actionName, row.ServerID+"/"+tag.ID))
 			}
 		} else {
 			rowBox = rowBox.Append(Label(row.Value))
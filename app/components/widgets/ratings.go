@@ -2,7 +2,6 @@ package widgets
 
 import (
 	"fmt"
-	"strings"
 
 	"codeberg.org/dergs/tonearm/pkg/schwifty"
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
@@ -16,18 +15,18 @@ type RatingsParams struct {
 	UserRating float64         // User's personal rating
 }
 
-// ratingIconPath returns the resource path for a rating image string.
-func ratingIconPath(ratingImage string) string {
-	switch {
-	case strings.Contains(ratingImage, "rottentomatoes://"):
-		// Check if it's an audience rating (popcorn) or critic rating (tomato)
-		if strings.Contains(ratingImage, "upright") || strings.Contains(ratingImage, "spilled") {
+// ratingIconPath returns the resource path for the icon of a rating source.
+func ratingIconPath(r sources.Rating) string {
+	switch r.Source {
+	case sources.RatingRottenTomatoes:
+		// Audience ratings use the popcorn, critic ratings the tomato
+		if r.Type == "audience" {
 			return "/dev/skillless/Scanline/icons/scalable/ratings/rt-popcorn.svg"
 		}
 		return "/dev/skillless/Scanline/icons/scalable/ratings/rt-tomato.svg"
-	case strings.Contains(ratingImage, "imdb://"):
+	case sources.RatingIMDb:
 		return "/dev/skillless/Scanline/icons/scalable/ratings/imdb.svg"
-	case strings.Contains(ratingImage, "themoviedb://"):
+	case sources.RatingTMDb:
 		return "/dev/skillless/Scanline/icons/scalable/ratings/tmdb.svg"
 	default:
 		return ""
@@ -46,7 +45,7 @@ func Ratings(params RatingsParams) schwifty.Box {
 			continue
 		}
 
-		iconPath := ratingIconPath(r.Image)
+		iconPath := ratingIconPath(r)
 		var icon schwifty.Image
 		if iconPath != "" {
 			texture := gdk.NewTextureFromResource(iconPath)
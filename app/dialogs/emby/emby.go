@@ -0,0 +1,184 @@
+package emby
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	appauth "github.com/0skillallluck/scanline/app/auth"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	httperrors "github.com/0skillallluck/scanline/utils/httputils/errors"
+	"github.com/0skillallluck/scanline/utils/notifications"
+)
+
+// NewSignIn creates a dialog for signing in to an Emby server with a
+// username and password, or to Emby Connect. onSuccess is called after the
+// account is added.
+func NewSignIn(ctx context.Context, mgr *sources.Manager, onSuccess func()) *adw.Dialog {
+	ctx, cancel := context.WithCancel(ctx)
+
+	dialog := adw.NewDialog()
+	dialog.SetTitle(gettext.Get("Sign In to Emby"))
+	dialog.SetContentWidth(420)
+
+	toolbarView := adw.NewToolbarView()
+	headerBar := adw.NewHeaderBar()
+	toolbarView.AddTopBar(&headerBar.Widget)
+	dialog.SetChild(&toolbarView.Widget)
+
+	serverRow := adw.NewEntryRow()
+	serverRow.SetTitle(gettext.Get("Server Address"))
+	serverRow.SetInputPurpose(gtk.InputPurposeUrlValue)
+
+	usernameRow := adw.NewEntryRow()
+	usernameRow.SetTitle(gettext.Get("Username"))
+
+	passwordRow := adw.NewPasswordEntryRow()
+	passwordRow.SetTitle(gettext.Get("Password"))
+
+	connectUserRow := adw.NewEntryRow()
+	connectUserRow.SetTitle(gettext.Get("Username or Email"))
+	connectUserRow.SetInputPurpose(gtk.InputPurposeEmailValue)
+
+	connectPasswordRow := adw.NewPasswordEntryRow()
+	connectPasswordRow.SetTitle(gettext.Get("Password"))
+
+	// current is the form shown when returning from the loading state.
+	var current *gtk.Widget
+	showForm := func() {
+		toolbarView.SetContent(current)
+	}
+
+	showLoading := func(message string) {
+		toolbarView.SetContent(VStack(
+			Spinner().SizeRequest(32, 32),
+			Label(message).WithCSSClass("dim-label"),
+		).Spacing(20).VAlign(gtk.AlignCenterValue).VExpand(true).VMargin(40).ToGTK())
+	}
+
+	// finish closes the dialog after a successful sign-in, or returns to
+	// the form with a toast describing err.
+	finish := func(err error) {
+		schwifty.OnMainThreadOncePure(func() {
+			if err != nil {
+				if ctx.Err() == nil {
+					notifications.OnToast.Notify(signInErrorText(err))
+				}
+				showForm()
+				return
+			}
+			notifications.OnToast.Notify(gettext.Get("Signed in to Emby"))
+			dialog.ForceClose()
+			if onSuccess != nil {
+				onSuccess()
+			}
+		})
+	}
+
+	signIn := func() {
+		url := appauth.JellyfinServerURL(serverRow.GetText())
+		if url == "" {
+			notifications.OnToast.Notify(gettext.Get("Enter the address of your Emby server"))
+			return
+		}
+		username, password := usernameRow.GetText(), passwordRow.GetText()
+		showLoading(gettext.Get("Signing in…"))
+		go func() {
+			finish(appauth.EmbySignIn(ctx, mgr, url, username, password))
+		}()
+	}
+
+	connectSignIn := func() {
+		nameOrEmail, password := connectUserRow.GetText(), connectPasswordRow.GetText()
+		if nameOrEmail == "" {
+			notifications.OnToast.Notify(gettext.Get("Enter your Emby Connect username or email"))
+			return
+		}
+		showLoading(gettext.Get("Signing in…"))
+		go func() {
+			finish(appauth.EmbyConnectSignIn(ctx, mgr, nameOrEmail, password))
+		}()
+	}
+
+	// Both forms are kept alive while other content is shown, so that the
+	// entered values survive a failed sign-in.
+	var serverForm, connectForm *gtk.Widget
+	serverForm = VStack(
+		PreferencesGroup(
+			Widget(&serverRow.Widget),
+		).Description(gettext.Get("For example, http://192.168.1.2:8096")),
+		PreferencesGroup(
+			Widget(&usernameRow.Widget),
+			Widget(&passwordRow.Widget),
+		),
+		VStack(
+			Button().
+				Label(gettext.Get("Sign In")).
+				WithCSSClass("pill").
+				WithCSSClass("suggested-action").
+				ConnectClicked(func(b gtk.Button) {
+					signIn()
+				}),
+			Button().
+				Label(gettext.Get("Use Emby Connect")).
+				WithCSSClass("pill").
+				ConnectClicked(func(b gtk.Button) {
+					current = connectForm
+					showForm()
+				}),
+		).Spacing(10).HAlign(gtk.AlignCenterValue),
+	).Spacing(18).HMargin(12).VMargin(12).ToGTK()
+	serverForm.Ref()
+
+	connectForm = VStack(
+		PreferencesGroup(
+			Widget(&connectUserRow.Widget),
+			Widget(&connectPasswordRow.Widget),
+		).Description(gettext.Get("Servers linked to your Emby Connect account are added.")),
+		VStack(
+			Button().
+				Label(gettext.Get("Sign In")).
+				WithCSSClass("pill").
+				WithCSSClass("suggested-action").
+				ConnectClicked(func(b gtk.Button) {
+					connectSignIn()
+				}),
+			Button().
+				Label(gettext.Get("Use Server Address")).
+				WithCSSClass("pill").
+				ConnectClicked(func(b gtk.Button) {
+					current = serverForm
+					showForm()
+				}),
+		).Spacing(10).HAlign(gtk.AlignCenterValue),
+	).Spacing(18).HMargin(12).VMargin(12).ToGTK()
+	connectForm.Ref()
+
+	dialog.ConnectClosed(new(func(adw.Dialog) {
+		cancel()
+		serverForm.Unref()
+		connectForm.Unref()
+	}))
+
+	current = serverForm
+	showForm()
+	return dialog
+}
+
+// signInErrorText describes a failed sign-in.
+func signInErrorText(err error) string {
+	slog.Warn("emby: sign-in failed", "error", err)
+	switch {
+	case errors.Is(err, appauth.ErrNoEmbyServers):
+		return gettext.Get("No servers linked to your Emby Connect account could be reached")
+	case errors.Is(err, httperrors.ErrAuthentication):
+		return gettext.Get("Incorrect username or password")
+	default:
+		return gettext.Get("Could not connect to the Emby server")
+	}
+}
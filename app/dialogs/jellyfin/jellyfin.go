@@ -0,0 +1,179 @@
+package jellyfin
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	appauth "github.com/0skillallluck/scanline/app/auth"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/provider/jellyfin"
+	httperrors "github.com/0skillallluck/scanline/utils/httputils/errors"
+	"github.com/0skillallluck/scanline/utils/notifications"
+)
+
+// NewSignIn creates a dialog for signing in to a Jellyfin server with a
+// username and password, or with Quick Connect. onSuccess is called after
+// the account is added.
+func NewSignIn(ctx context.Context, mgr *sources.Manager, onSuccess func()) *adw.Dialog {
+	ctx, cancel := context.WithCancel(ctx)
+
+	dialog := adw.NewDialog()
+	dialog.SetTitle(gettext.Get("Sign In to Jellyfin"))
+	dialog.SetContentWidth(420)
+
+	toolbarView := adw.NewToolbarView()
+	headerBar := adw.NewHeaderBar()
+	toolbarView.AddTopBar(&headerBar.Widget)
+	dialog.SetChild(&toolbarView.Widget)
+
+	serverRow := adw.NewEntryRow()
+	serverRow.SetTitle(gettext.Get("Server Address"))
+	serverRow.SetInputPurpose(gtk.InputPurposeUrlValue)
+
+	usernameRow := adw.NewEntryRow()
+	usernameRow.SetTitle(gettext.Get("Username"))
+
+	passwordRow := adw.NewPasswordEntryRow()
+	passwordRow.SetTitle(gettext.Get("Password"))
+
+	var showForm func()
+
+	showLoading := func(message string) {
+		toolbarView.SetContent(VStack(
+			Spinner().SizeRequest(32, 32),
+			Label(message).WithCSSClass("dim-label"),
+		).Spacing(20).VAlign(gtk.AlignCenterValue).VExpand(true).VMargin(40).ToGTK())
+	}
+
+	// finish closes the dialog after a successful sign-in, or returns to
+	// the form with a toast describing err.
+	finish := func(err error) {
+		schwifty.OnMainThreadOncePure(func() {
+			if err != nil {
+				if ctx.Err() == nil {
+					notifications.OnToast.Notify(signInErrorText(err))
+				}
+				showForm()
+				return
+			}
+			notifications.OnToast.Notify(gettext.Get("Signed in to Jellyfin"))
+			dialog.ForceClose()
+			if onSuccess != nil {
+				onSuccess()
+			}
+		})
+	}
+
+	serverURL := func() (string, bool) {
+		url := appauth.JellyfinServerURL(serverRow.GetText())
+		if url == "" {
+			notifications.OnToast.Notify(gettext.Get("Enter the address of your Jellyfin server"))
+		}
+		return url, url != ""
+	}
+
+	signIn := func() {
+		url, ok := serverURL()
+		if !ok {
+			return
+		}
+		username, password := usernameRow.GetText(), passwordRow.GetText()
+		showLoading(gettext.Get("Signing in…"))
+		go func() {
+			finish(appauth.JellyfinSignIn(ctx, mgr, url, username, password))
+		}()
+	}
+
+	quickConnect := func() {
+		url, ok := serverURL()
+		if !ok {
+			return
+		}
+		showLoading(gettext.Get("Connecting…"))
+
+		qcCtx, qcCancel := context.WithCancel(ctx)
+		go func() {
+			defer qcCancel()
+			err := appauth.JellyfinQuickConnect(qcCtx, mgr, url, func(code string) {
+				schwifty.OnMainThreadOncePure(func() {
+					status := adw.NewStatusPage()
+					status.SetTitle(code)
+					status.SetDescription(gettext.Get("Enter this code in Quick Connect in another Jellyfin app where you are signed in."))
+					status.SetChild(Button().
+						Label(gettext.Get("Cancel")).
+						WithCSSClass("pill").
+						HAlign(gtk.AlignCenterValue).
+						ConnectClicked(func(b gtk.Button) {
+							qcCancel()
+						}).ToGTK())
+					toolbarView.SetContent(&status.Widget)
+				})
+			})
+			if errors.Is(err, context.Canceled) && ctx.Err() == nil {
+				// Canceled by the user; back to the form without a toast.
+				schwifty.OnMainThreadOncePure(showForm)
+				return
+			}
+			finish(err)
+		}()
+	}
+
+	// The form is kept alive while other content is shown, so that the
+	// entered values survive a failed sign-in.
+	form := VStack(
+		PreferencesGroup(
+			Widget(&serverRow.Widget),
+		).Description(gettext.Get("For example, http://192.168.1.2:8096")),
+		PreferencesGroup(
+			Widget(&usernameRow.Widget),
+			Widget(&passwordRow.Widget),
+		),
+		VStack(
+			Button().
+				Label(gettext.Get("Sign In")).
+				WithCSSClass("pill").
+				WithCSSClass("suggested-action").
+				ConnectClicked(func(b gtk.Button) {
+					signIn()
+				}),
+			Button().
+				Label(gettext.Get("Use Quick Connect")).
+				WithCSSClass("pill").
+				ConnectClicked(func(b gtk.Button) {
+					quickConnect()
+				}),
+		).Spacing(10).HAlign(gtk.AlignCenterValue),
+	).Spacing(18).HMargin(12).VMargin(12).ToGTK()
+	form.Ref()
+
+	showForm = func() {
+		toolbarView.SetContent(form)
+	}
+
+	dialog.ConnectClosed(new(func(adw.Dialog) {
+		cancel()
+		form.Unref()
+	}))
+
+	showForm()
+	return dialog
+}
+
+// signInErrorText describes a failed sign-in.
+func signInErrorText(err error) string {
+	slog.Warn("jellyfin: sign-in failed", "error", err)
+	switch {
+	case errors.Is(err, jellyfin.ErrQuickConnectDisabled):
+		return gettext.Get("Quick Connect is disabled on this server")
+	case errors.Is(err, httperrors.ErrAuthentication):
+		return gettext.Get("Incorrect username or password")
+	default:
+		return gettext.Get("Could not connect to the Jellyfin server")
+	}
+}
@@ -0,0 +1,155 @@
+package plexserver
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	appauth "github.com/0skillallluck/scanline/app/auth"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/provider/plex/gdm"
+	httperrors "github.com/0skillallluck/scanline/utils/httputils/errors"
+	"github.com/0skillallluck/scanline/utils/notifications"
+)
+
+// NewAddServer creates a dialog for adding a Plex server by its address,
+// without signing in to plex.tv. Servers found on the local network are
+// offered to pick the address from. onSuccess is called after the server
+// is added.
+func NewAddServer(ctx context.Context, mgr *sources.Manager, onSuccess func()) *adw.Dialog {
+	ctx, cancel := context.WithCancel(ctx)
+
+	dialog := adw.NewDialog()
+	dialog.SetTitle(gettext.Get("Add Plex Server"))
+	dialog.SetContentWidth(420)
+
+	toolbarView := adw.NewToolbarView()
+	headerBar := adw.NewHeaderBar()
+	toolbarView.AddTopBar(&headerBar.Widget)
+	dialog.SetChild(&toolbarView.Widget)
+
+	serverRow := adw.NewEntryRow()
+	serverRow.SetTitle(gettext.Get("Server Address"))
+	serverRow.SetInputPurpose(gtk.InputPurposeUrlValue)
+
+	tokenRow := adw.NewPasswordEntryRow()
+	tokenRow.SetTitle(gettext.Get("Token (optional)"))
+
+	// Servers on the local network, shown once discovery finds any
+	lanGroup := adw.NewPreferencesGroup()
+	lanGroup.SetTitle(gettext.Get("Servers on Your Network"))
+	lanGroup.SetVisible(false)
+	go func() {
+		resources := appauth.DiscoverPlexServers(ctx, mgr)
+		if len(resources) == 0 {
+			return
+		}
+		schwifty.OnMainThreadOncePure(func() {
+			for _, r := range resources {
+				lanGroup.Add(&lanServerRow(r, func() {
+					serverRow.SetText(r.URI())
+					tokenRow.GrabFocus()
+				}).Widget)
+			}
+			lanGroup.SetVisible(true)
+		})
+	}()
+
+	// The form is kept alive while other content is shown, so that the
+	// entered values survive a failed attempt.
+	var form *gtk.Widget
+	showForm := func() {
+		toolbarView.SetContent(form)
+	}
+
+	addServer := func() {
+		url := appauth.PlexServerURL(serverRow.GetText())
+		if url == "" {
+			notifications.OnToast.Notify(gettext.Get("Enter the address of your Plex server"))
+			return
+		}
+		token := tokenRow.GetText()
+		toolbarView.SetContent(VStack(
+			Spinner().SizeRequest(32, 32),
+			Label(gettext.Get("Connecting…")).WithCSSClass("dim-label"),
+		).Spacing(20).VAlign(gtk.AlignCenterValue).VExpand(true).VMargin(40).ToGTK())
+
+		go func() {
+			err := appauth.AddPlexServer(ctx, mgr, url, token)
+			schwifty.OnMainThreadOncePure(func() {
+				if err != nil {
+					if ctx.Err() == nil {
+						notifications.OnToast.Notify(addServerErrorText(err))
+					}
+					showForm()
+					return
+				}
+				notifications.OnToast.Notify(gettext.Get("Server added"))
+				dialog.ForceClose()
+				if onSuccess != nil {
+					onSuccess()
+				}
+			})
+		}()
+	}
+
+	form = VStack(
+		Widget(&lanGroup.Widget),
+		PreferencesGroup(
+			Widget(&serverRow.Widget),
+		).Description(gettext.Get("For example, http://192.168.1.2:32400")),
+		PreferencesGroup(
+			Widget(&tokenRow.Widget),
+		).Description(gettext.Get("Servers that are not claimed to plex.tv may allow access without a token.")),
+		Button().
+			Label(gettext.Get("Add Server")).
+			WithCSSClass("pill").
+			WithCSSClass("suggested-action").
+			HAlign(gtk.AlignCenterValue).
+			ConnectClicked(func(b gtk.Button) {
+				addServer()
+			}),
+	).Spacing(18).HMargin(12).VMargin(12).ToGTK()
+	form.Ref()
+
+	dialog.ConnectClosed(new(func(adw.Dialog) {
+		cancel()
+		form.Unref()
+	}))
+
+	showForm()
+	return dialog
+}
+
+// lanServerRow builds an activatable row for a server found on the local
+// network.
+func lanServerRow(r gdm.Resource, onActivate func()) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(r.Name)
+	row.SetSubtitle(r.Address())
+	row.AddSuffix(&gtk.NewImageFromIconName("go-next-symbolic").Widget)
+	row.SetActivatable(true)
+	row.ConnectActivated(new(func(adw.ActionRow) {
+		onActivate()
+	}))
+	return row
+}
+
+// addServerErrorText describes a failure to add a server.
+func addServerErrorText(err error) string {
+	slog.Warn("plex: adding server failed", "error", err)
+	switch {
+	case errors.Is(err, sources.ErrServerExists):
+		return gettext.Get("This server is already added")
+	case errors.Is(err, httperrors.ErrAuthentication):
+		return gettext.Get("The server requires a valid token")
+	default:
+		return gettext.Get("Could not connect to the Plex server")
+	}
+}
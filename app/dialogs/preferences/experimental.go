@@ -26,9 +26,15 @@ var experimentalPreferences = PreferencesPage(
 			Subtitle(gettext.Get("Enable support for picture-in-picture mode.")),
 		SwitchRow().
 			Title(gettext.Get("Enable Jellyfin support")).
-			Subtitle(gettext.Get("Enable support for Jellyfin servers.")),
+			Subtitle(gettext.Get("Enable support for Jellyfin servers.")).
+			ConnectConstruct(func(sr *adw.SwitchRow) {
+				preference.Experimental().BindEnableJellyfin(&sr.Object, "active")
+			}),
 		SwitchRow().
-			Title(gettext.Get("Enable EMBY support")).
-			Subtitle(gettext.Get("Enable support for EMBY servers.")),
+			Title(gettext.Get("Enable Emby support")).
+			Subtitle(gettext.Get("Enable support for Emby servers.")).
+			ConnectConstruct(func(sr *adw.SwitchRow) {
+				preference.Experimental().BindEnableEmby(&sr.Object, "active")
+			}),
 	).Title(gettext.Get("Features")).Description(gettext.Get("Toggle experimental features. These may be incomplete or unstable.")),
 ).Title(gettext.Get("Experimental")).IconName("science-symbolic")
@@ -22,4 +22,43 @@ var generalPreferences = PreferencesPage(
 	).
 		Title(gettext.Get("Navigation Behaviour")).
 		Description(gettext.Get("Configure the behaviour of Scanline when navigating between pages.")),
+	PreferencesGroup(
+		SpinRow(
+			gtk.NewAdjustment(5, 2, 60, 1, 5, 0),
+			1,
+			0,
+		).Title(gettext.Get("Slideshow Interval")).
+			Subtitle(gettext.Get("Seconds each photo is shown during a slideshow.")).
+			ConnectConstruct(func(sr *adw.SpinRow) {
+				preference.General().BindSlideshowInterval(&sr.Object, "value")
+			}),
+		SwitchRow().
+			Title(gettext.Get("Shuffle Slideshow")).
+			Subtitle(gettext.Get("Show photos in random order.")).
+			ConnectConstruct(func(sr *adw.SwitchRow) {
+				preference.General().BindSlideshowShuffle(&sr.Object, "active")
+			}),
+	).Title(gettext.Get("Photos")),
+	PreferencesGroup(
+		SwitchRow().
+			Title(gettext.Get("Unify Libraries")).
+			Subtitle(gettext.Get("Merge the movies and shows of all servers, showing items available on several servers once.")).
+			ConnectConstruct(func(sr *adw.SwitchRow) {
+				preference.General().BindUnifyLibraries(&sr.Object, "active")
+			}),
+		SwitchRow().
+			Title(gettext.Get("Sync Watch State")).
+			Subtitle(gettext.Get("After playback or marking an item, copy its watch state to the other servers that have it.")).
+			ConnectConstruct(func(sr *adw.SwitchRow) {
+				preference.General().BindSyncWatchState(&sr.Object, "active")
+			}),
+	).Title(gettext.Get("Libraries")),
+	PreferencesGroup(
+		SwitchRow().
+			Title(gettext.Get("Pick Profile at Startup")).
+			Subtitle(gettext.Get("Ask which Plex Home user to use when Scanline starts.")).
+			ConnectConstruct(func(sr *adw.SwitchRow) {
+				preference.General().BindPickProfileAtStartup(&sr.Object, "active")
+			}),
+	).Title(gettext.Get("Profiles")),
 ).Title(gettext.Get("General")).IconName("settings-symbolic")
@@ -32,13 +32,13 @@ var performancePreferences = PreferencesPage(
 			}),
 		SwitchRow().
 			Title(gettext.Get("Cache Libraries")).
-			Subtitle(gettext.Get("Cache library content locally to improve performance and reduce network traffic.")).
+			Subtitle(gettext.Get("Save library content locally, so it can be browsed while servers are offline.")).
 			ConnectConstruct(func(sr *adw.SwitchRow) {
 				preference.Performance().BindCacheLibraries(&sr.Object, "active")
 			}),
 		SwitchRow().
 			Title(gettext.Get("Cache Metadata")).
-			Subtitle(gettext.Get("Cache metadata locally to improve performance and reduce network traffic.")).
+			Subtitle(gettext.Get("Save item details locally, so they can be browsed while servers are offline.")).
 			ConnectConstruct(func(sr *adw.SwitchRow) {
 				preference.Performance().BindCacheMetadata(&sr.Object, "active")
 			}),
@@ -20,4 +20,12 @@ var playerPreferences = PreferencesPage(
 				})
 			}),
 	).Title(gettext.Get("Windowed Player")),
+	PreferencesGroup(
+		SwitchRow().
+			Title(gettext.Get("Normalize Volume")).
+			Subtitle(gettext.Get("Play music at a consistent volume using the server's loudness analysis.")).
+			ConnectConstruct(func(sr *adw.SwitchRow) {
+				preference.General().BindNormalizeVolume(&sr.Object, "active")
+			}),
+	).Title(gettext.Get("Music")),
 ).Title(gettext.Get("Player")).IconName("play")
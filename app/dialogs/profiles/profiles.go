@@ -0,0 +1,219 @@
+package profiles
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/utils/imageutils"
+	"github.com/0skillallluck/scanline/utils/notifications"
+)
+
+// accountUsers is the Plex Home of one account.
+type accountUsers struct {
+	account *sources.Account
+	users   []sources.HomeUser
+}
+
+// fetchHomes lists the Home users of every Plex account. Accounts whose
+// Home cannot be fetched are skipped.
+func fetchHomes(ctx context.Context, mgr *sources.Manager) []accountUsers {
+	var homes []accountUsers
+	for _, acct := range mgr.Accounts() {
+		if acct.Type != sources.ProviderPlex {
+			continue
+		}
+		users, err := mgr.HomeUsers(ctx, acct.ID)
+		if err != nil {
+			slog.Warn("failed to fetch home users", "account", acct.Username, "error", err)
+			continue
+		}
+		homes = append(homes, accountUsers{account: acct, users: users})
+	}
+	return homes
+}
+
+// PresentAtStartup shows the profile picker if any account belongs to a
+// Plex Home with more than one user.
+func PresentAtStartup(ctx context.Context, parent *gtk.Widget, mgr *sources.Manager, onDone func()) {
+	go func() {
+		homes := fetchHomes(ctx, mgr)
+		for _, home := range homes {
+			if len(home.users) > 1 {
+				schwifty.OnMainThreadOncePure(func() {
+					newPicker(ctx, mgr, homes, onDone).Present(parent)
+				})
+				return
+			}
+		}
+	}()
+}
+
+// NewProfilePicker creates a dialog for switching between Plex Home users.
+// onDone is called after a switch.
+func NewProfilePicker(ctx context.Context, mgr *sources.Manager, onDone func()) *adw.Dialog {
+	return newPicker(ctx, mgr, nil, onDone)
+}
+
+func newPicker(ctx context.Context, mgr *sources.Manager, homes []accountUsers, onDone func()) *adw.Dialog {
+	dialog := adw.NewDialog()
+	dialog.SetTitle(gettext.Get("Switch Profile"))
+	dialog.SetContentWidth(420)
+
+	toolbarView := adw.NewToolbarView()
+	headerBar := adw.NewHeaderBar()
+	toolbarView.AddTopBar(&headerBar.Widget)
+	dialog.SetChild(&toolbarView.Widget)
+
+	showMessage := func(message string) {
+		toolbarView.SetContent(VStack(
+			Label(message).WithCSSClass("dim-label").Wrap(true),
+		).VAlign(gtk.AlignCenterValue).VExpand(true).VMargin(40).HMargin(20).ToGTK())
+	}
+
+	showLoading := func(message string) {
+		toolbarView.SetContent(VStack(
+			Spinner().SizeRequest(32, 32),
+			Label(message).WithCSSClass("dim-label"),
+		).Spacing(20).VAlign(gtk.AlignCenterValue).VExpand(true).VMargin(40).ToGTK())
+	}
+
+	var showHomes func([]accountUsers)
+	switchTo := func(acct *sources.Account, user sources.HomeUser, pin string) {
+		showLoading(gettext.Get("Switching profile…"))
+		go func() {
+			err := mgr.SwitchHomeUser(ctx, acct.ID, user, pin)
+			schwifty.OnMainThreadOncePure(func() {
+				if err != nil {
+					slog.Error("failed to switch home user", "user", user.Title, "error", err)
+					if errors.Is(err, sources.ErrInvalidPIN) {
+						notifications.OnToast.Notify(gettext.Get("Incorrect PIN"))
+					} else {
+						notifications.OnToast.Notify(gettext.Get("Failed to switch profile"))
+					}
+					showHomes(homes)
+					return
+				}
+				dialog.ForceClose()
+				onDone()
+			})
+		}()
+	}
+
+	askPIN := func(acct *sources.Account, user sources.HomeUser) {
+		entry := gtk.NewPasswordEntry()
+		entry.SetShowPeekIcon(true)
+		entry.SetPropertyActivatesDefault(true)
+		entry.SetPropertyPlaceholderText(gettext.Get("PIN"))
+
+		alert := adw.NewAlertDialog(user.Title, gettext.Get("Enter the PIN for this profile."))
+		alert.AddResponse("cancel", gettext.Get("Cancel"))
+		alert.AddResponse("switch", gettext.Get("Switch"))
+		alert.SetResponseAppearance("switch", adw.ResponseSuggestedValue)
+		alert.SetDefaultResponse("switch")
+		alert.SetCloseResponse("cancel")
+		alert.SetExtraChild(&entry.Widget)
+		alert.ConnectResponse(new(func(_ adw.AlertDialog, response string) {
+			if response == "switch" {
+				switchTo(acct, user, entry.GetText())
+			}
+		}))
+		alert.Present(&dialog.Widget)
+	}
+
+	showHomes = func(homes []accountUsers) {
+		var groups []any
+		for _, home := range homes {
+			acct := home.account
+			var rows []any
+			for _, user := range home.users {
+				rows = append(rows, Widget(&userRow(acct, user, func() {
+					if user.Protected {
+						askPIN(acct, user)
+					} else {
+						switchTo(acct, user, "")
+					}
+				}).Widget))
+			}
+			groups = append(groups, PreferencesGroup(rows...).Title(acct.Username))
+		}
+		if len(groups) == 0 {
+			showMessage(gettext.Get("No Plex Home profiles are available."))
+			return
+		}
+		toolbarView.SetContent(ScrolledWindow().
+			Child(VStack(groups...).Spacing(12).HMargin(12).VMargin(12)).
+			PropagateNaturalHeight(true).
+			ToGTK())
+	}
+
+	if homes != nil {
+		showHomes(homes)
+	} else {
+		showLoading(gettext.Get("Loading profiles…"))
+		go func() {
+			fetched := fetchHomes(ctx, mgr)
+			schwifty.OnMainThreadOncePure(func() {
+				homes = fetched
+				showHomes(homes)
+			})
+		}()
+	}
+
+	return dialog
+}
+
+// userRow builds an activatable row for a Home user, marking the active
+// profile.
+func userRow(acct *sources.Account, user sources.HomeUser, onActivate func()) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(user.Title)
+	switch {
+	case user.Admin:
+		row.SetSubtitle(gettext.Get("Owner"))
+	case user.Restricted:
+		row.SetSubtitle(gettext.Get("Managed"))
+	case user.Guest:
+		row.SetSubtitle(gettext.Get("Guest"))
+	}
+
+	avatar := adw.NewAvatar(40, user.Title, true)
+	if user.Thumb != "" {
+		go func() {
+			texture, err := imageutils.LoadCropped(user.Thumb)
+			if err != nil {
+				return
+			}
+			schwifty.OnMainThreadOncePure(func() {
+				avatar.SetCustomImage(texture)
+				texture.Unref()
+			})
+		}()
+	}
+	row.AddPrefix(&avatar.Widget)
+
+	if user.Protected {
+		lock := gtk.NewImageFromIconName("system-lock-screen-symbolic")
+		lock.SetTooltipText(gettext.Get("Protected with a PIN"))
+		row.AddSuffix(&lock.Widget)
+	}
+	active := acct.ProfileUUID == user.UUID || (acct.ProfileUUID == "" && user.Admin)
+	if active {
+		check := gtk.NewImageFromIconName("object-select-symbolic")
+		check.SetTooltipText(gettext.Get("Current profile"))
+		row.AddSuffix(&check.Widget)
+	}
+
+	row.SetActivatable(true)
+	row.ConnectActivated(new(func(adw.ActionRow) {
+		onActivate()
+	}))
+	return row
+}
@@ -0,0 +1,173 @@
+package sources
+
+import (
+	"slices"
+
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	appauth "github.com/0skillallluck/scanline/app/auth"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+// newConnectionDialog creates a dialog for choosing how a Plex server is
+// connected to: custom addresses, a preferred connection, and which kinds
+// of connections are allowed. onChanged is called with the new
+// preferences when the dialog is closed after changes.
+func newConnectionDialog(srv *sources.Server, onChanged func(sources.ConnectionPrefs)) *adw.Dialog {
+	prefs := srv.ConnectionPrefs
+	prefs.CustomURLs = slices.Clone(prefs.CustomURLs)
+	changed := false
+
+	dialog := adw.NewDialog()
+	dialog.SetTitle(gettext.Get("Connection"))
+	dialog.SetContentWidth(480)
+	dialog.SetContentHeight(600)
+
+	toolbarView := adw.NewToolbarView()
+	headerBar := adw.NewHeaderBar()
+	toolbarView.AddTopBar(&headerBar.Widget)
+	dialog.SetChild(&toolbarView.Widget)
+
+	var rebuild func()
+	update := func(apply func()) {
+		apply()
+		changed = true
+		rebuild()
+	}
+
+	rebuild = func() {
+		// Preferred connection, chosen among the known and custom addresses
+		var preferredRows []any
+		var group *gtk.CheckButton
+		addChoice := func(title, subtitle, uri string) {
+			check := gtk.NewCheckButton()
+			check.SetValign(gtk.AlignCenterValue)
+			if group == nil {
+				group = check
+			} else {
+				check.SetGroup(group)
+			}
+			check.SetActive(prefs.PreferredURL == uri)
+			check.ConnectToggled(new(func(c gtk.CheckButton) {
+				if check.GetActive() && prefs.PreferredURL != uri {
+					prefs.PreferredURL = uri
+					changed = true
+				}
+			}))
+
+			row := adw.NewActionRow()
+			row.SetUseMarkup(false)
+			row.SetTitle(title)
+			row.SetSubtitle(subtitle)
+			row.AddPrefix(&check.Widget)
+			row.SetActivatableWidget(&check.Widget)
+			preferredRows = append(preferredRows, Widget(&row.Widget))
+		}
+		addChoice(gettext.Get("Automatic"), gettext.Get("Local connections first, then remote ones"), "")
+		for _, uri := range connectionURIs(srv, prefs) {
+			addChoice(uri, "", uri)
+		}
+
+		// Custom addresses
+		var customRows []any
+		for _, uri := range prefs.CustomURLs {
+			remove := gtk.NewButtonFromIconName("user-trash-symbolic")
+			remove.SetValign(gtk.AlignCenterValue)
+			remove.SetTooltipText(gettext.Get("Remove Address"))
+			remove.AddCssClass("flat")
+			remove.ConnectClicked(new(func(b gtk.Button) {
+				update(func() {
+					prefs.CustomURLs = slices.DeleteFunc(prefs.CustomURLs, func(u string) bool { return u == uri })
+					if prefs.PreferredURL == uri {
+						prefs.PreferredURL = ""
+					}
+				})
+			}))
+
+			row := adw.NewActionRow()
+			row.SetUseMarkup(false)
+			row.SetTitle(uri)
+			row.AddSuffix(&remove.Widget)
+			customRows = append(customRows, Widget(&row.Widget))
+		}
+		addRow := adw.NewEntryRow()
+		addRow.SetTitle(gettext.Get("Add Address"))
+		addRow.SetInputPurpose(gtk.InputPurposeUrlValue)
+		addRow.SetShowApplyButton(true)
+		addRow.ConnectApply(new(func(adw.EntryRow) {
+			uri := appauth.PlexServerURL(addRow.GetText())
+			if uri == "" || slices.Contains(prefs.CustomURLs, uri) {
+				return
+			}
+			update(func() {
+				prefs.CustomURLs = append(prefs.CustomURLs, uri)
+			})
+		}))
+		customRows = append(customRows, Widget(&addRow.Widget))
+
+		relayRow := adw.NewSwitchRow()
+		relayRow.SetTitle(gettext.Get("Allow Relay"))
+		relayRow.SetSubtitle(gettext.Get("Connect through the bandwidth-limited Plex relay when nothing else works."))
+		relayRow.SetActive(!prefs.DisableRelay)
+		relayRow.ConnectSignal("notify::active", new(func() {
+			prefs.DisableRelay = !relayRow.GetActive()
+			changed = true
+		}))
+
+		httpsRow := adw.NewSwitchRow()
+		httpsRow.SetTitle(gettext.Get("Require HTTPS"))
+		httpsRow.SetSubtitle(gettext.Get("Only use encrypted connections."))
+		httpsRow.SetActive(prefs.RequireHTTPS)
+		httpsRow.ConnectSignal("notify::active", new(func() {
+			prefs.RequireHTTPS = httpsRow.GetActive()
+			changed = true
+		}))
+
+		content := VStack(
+			PreferencesGroup(preferredRows...).
+				Title(gettext.Get("Preferred Connection")).
+				Description(gettext.Get("The preferred connection is used whenever it is reachable.")),
+			PreferencesGroup(customRows...).
+				Title(gettext.Get("Custom Addresses")).
+				Description(gettext.Get("Addresses plex.tv does not know about, such as a reverse proxy or a VPN host name.")),
+			PreferencesGroup(
+				Widget(&relayRow.Widget),
+				Widget(&httpsRow.Widget),
+			).Title(gettext.Get("Allowed Connections")),
+		).Spacing(12).HMargin(12).VMargin(12).ToGTK()
+		toolbarView.SetContent(ScrolledWindow().
+			Child(content).
+			PropagateNaturalHeight(true).
+			ToGTK())
+	}
+
+	dialog.ConnectClosed(new(func(adw.Dialog) {
+		if changed {
+			onChanged(prefs)
+		}
+	}))
+
+	rebuild()
+	return dialog
+}
+
+// connectionURIs returns the addresses the server can be reached at, as
+// last ranked, followed by custom addresses not probed yet. Custom
+// addresses removed in prefs are left out.
+func connectionURIs(srv *sources.Server, prefs sources.ConnectionPrefs) []string {
+	var uris []string
+	for _, uri := range srv.Connections {
+		if slices.Contains(srv.CustomURLs, uri) && !slices.Contains(prefs.CustomURLs, uri) {
+			continue
+		}
+		uris = append(uris, uri)
+	}
+	for _, uri := range prefs.CustomURLs {
+		if !slices.Contains(uris, uri) {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
@@ -2,14 +2,23 @@ package sources
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
+	"strings"
 
 	"codeberg.org/dergs/tonearm/pkg/schwifty"
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
 	"codeberg.org/puregotk/puregotk/v4/adw"
 	"codeberg.org/puregotk/puregotk/v4/gtk"
 	appauth "github.com/0skillallluck/scanline/app/auth"
+	"github.com/0skillallluck/scanline/app/dialogs/emby"
+	"github.com/0skillallluck/scanline/app/dialogs/jellyfin"
+	"github.com/0skillallluck/scanline/app/dialogs/plexserver"
+	"github.com/0skillallluck/scanline/app/preference"
+	"github.com/0skillallluck/scanline/app/router"
 	"github.com/0skillallluck/scanline/app/sources"
 	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/utils/notifications"
 )
 
 func NewSourceSelection(ctx context.Context, window *gtk.Window, mgr *sources.Manager, onDone func()) *adw.Dialog {
@@ -35,11 +44,21 @@ func NewSourceSelection(ctx context.Context, window *gtk.Window, mgr *sources.Ma
 
 	dialog.SetChild(&toolbarView.Widget)
 
-	dialog.ConnectCloseAttempt(new(func(d adw.Dialog) {
+	// closeDialog stops the dialog's pending work before handing back to
+	// the caller. Every way of leaving the dialog goes through it.
+	closeDialog := func() {
 		cancel()
 		onDone()
+	}
+
+	dialog.ConnectCloseAttempt(new(func(d adw.Dialog) {
+		closeDialog()
 	}))
 
+	// applyConnectionPrefs saves the connection preferences of a server and
+	// resolves its connections again.
+	var applyConnectionPrefs func(serverID string, prefs sources.ConnectionPrefs)
+
 	// refreshContent rebuilds the dialog content from current manager state.
 	// We use a variable so it can be called recursively from sign-out handlers.
 	var refreshContent func()
@@ -68,29 +87,22 @@ func NewSourceSelection(ctx context.Context, window *gtk.Window, mgr *sources.Ma
 			var rows []any
 
 			for _, srv := range acct.Servers {
-				srvID := srv.ID
-				srvEnabled := srv.Enabled
-				acctIDCopy := acctID
-
-				rows = append(rows,
-					SwitchRow().
-						Title(srv.Name).
-						Subtitle(serverStatusText(srv)).
-						ConnectConstruct(func(row *adw.SwitchRow) {
-							row.SetActive(srvEnabled)
-							cb := func() {
-								mgr.SetServerEnabled(acctIDCopy, srvID, row.GetActive())
-							}
-							row.ConnectSignal("notify::active", &cb)
-						}),
-				)
+				var onConnectionPrefs func(string, sources.ConnectionPrefs)
+				if acct.Type == sources.ProviderPlex || acct.Type == sources.ProviderPlexServer {
+					onConnectionPrefs = applyConnectionPrefs
+				}
+				rows = append(rows, Widget(&serverRow(ctx, mgr, acctID, srv, closeDialog, onConnectionPrefs).Widget))
 			}
 
-			// Sign Out button
+			// Sign Out button; servers added by address are just removed
 			signOutAcctID := acctID
+			signOutTitle := gettext.Get("Sign Out")
+			if acct.Type == sources.ProviderPlexServer {
+				signOutTitle = gettext.Get("Remove Server")
+			}
 			rows = append(rows,
 				ButtonRow().
-					Title(gettext.Get("Sign Out")).
+					Title(signOutTitle).
 					StartIconName("system-log-out-symbolic").
 					ConnectConstruct(func(br *adw.ButtonRow) {
 						cb := func(adw.ButtonRow) {
@@ -98,14 +110,14 @@ func NewSourceSelection(ctx context.Context, window *gtk.Window, mgr *sources.Ma
 							if mgr.HasAccounts() {
 								schwifty.OnMainThreadOncePure(refreshContent)
 							} else {
-								schwifty.OnMainThreadOncePure(onDone)
+								schwifty.OnMainThreadOncePure(closeDialog)
 							}
 						}
 						br.ConnectActivated(&cb)
 					}),
 			)
 
-			groupTitle := acct.Username + " (Plex)"
+			groupTitle := acct.Username + " (" + providerName(acct.Type) + ")"
 			groupWidgets = append(groupWidgets,
 				PreferencesGroup(rows...).Title(groupTitle),
 			)
@@ -126,13 +138,48 @@ func NewSourceSelection(ctx context.Context, window *gtk.Window, mgr *sources.Ma
 		toolbarView.SetContent(loadingContent)
 	}
 
-	// "+" button: add new Plex account
+	applyConnectionPrefs = func(serverID string, prefs sources.ConnectionPrefs) {
+		showLoading()
+		go func() {
+			mgr.SetConnectionPrefs(ctx, serverID, prefs)
+			schwifty.OnMainThreadOncePure(refreshContent)
+		}()
+	}
+
+	// "+" button: add a new account, asking for the provider
 	addButton.ConnectClicked(new(func(b gtk.Button) {
 		onSuccess := refreshContent
 		if !mgr.HasAccounts() {
-			onSuccess = onDone
+			onSuccess = closeDialog
+		}
+		enableJellyfin := preference.Experimental().EnableJellyfin()
+		enableEmby := preference.Experimental().EnableEmby()
+
+		alert := adw.NewAlertDialog(gettext.Get("Add Account"), gettext.Get("Choose the type of server to sign in to."))
+		alert.AddResponse("cancel", gettext.Get("Cancel"))
+		if enableEmby {
+			alert.AddResponse("emby", "Emby")
+		}
+		if enableJellyfin {
+			alert.AddResponse("jellyfin", "Jellyfin")
 		}
-		appauth.PerformSignIn(ctx, window, &dialog.Widget, mgr, showLoading, refreshContent, onSuccess)
+		alert.AddResponse("plex_server", gettext.Get("Plex Server by Address"))
+		alert.AddResponse("plex", "Plex")
+		alert.SetDefaultResponse("plex")
+		alert.SetCloseResponse("cancel")
+		alert.ConnectResponse(new(func(_ adw.AlertDialog, response string) {
+			switch response {
+			case "plex":
+				appauth.PerformSignIn(ctx, window, &dialog.Widget, mgr, showLoading, refreshContent, onSuccess)
+			case "plex_server":
+				plexserver.NewAddServer(ctx, mgr, onSuccess).Present(&dialog.Widget)
+			case "jellyfin":
+				jellyfin.NewSignIn(ctx, mgr, onSuccess).Present(&dialog.Widget)
+			case "emby":
+				emby.NewSignIn(ctx, mgr, onSuccess).Present(&dialog.Widget)
+			}
+		}))
+		alert.Present(&dialog.Widget)
 	}))
 
 	// Refresh button: re-discover servers and re-resolve URLs
@@ -167,7 +214,199 @@ func NewSourceSelection(ctx context.Context, window *gtk.Window, mgr *sources.Ma
 	return dialog
 }
 
-func serverStatusText(srv *sources.Server) string {
+// serverRow builds an expandable row for a server. Its switch enables the
+// server; expanding it shows the server's restrictions and lets individual
+// libraries be enabled. Servers whose connections can be configured are
+// given onConnectionPrefs to apply changes with.
+func serverRow(ctx context.Context, mgr *sources.Manager, accountID string, srv *sources.Server, closeDialog func(), onConnectionPrefs func(string, sources.ConnectionPrefs)) *adw.ExpanderRow {
+	serverID := srv.ID
+
+	row := adw.NewExpanderRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(srv.Name)
+	row.SetSubtitle(serverSubtitle(srv, mgr.IsOffline(serverID)))
+	row.SetShowEnableSwitch(true)
+	row.SetEnableExpansion(srv.Enabled)
+	row.ConnectSignal("notify::enable-expansion", new(func() {
+		mgr.SetServerEnabled(accountID, serverID, row.GetEnableExpansion())
+	}))
+
+	details := gtk.NewButtonFromIconName("info-outline-symbolic")
+	details.SetValign(gtk.AlignCenterValue)
+	details.SetTooltipText(gettext.Get("Server Details"))
+	details.AddCssClass("flat")
+	details.ConnectClicked(new(func(b gtk.Button) {
+		if mgr.SourceForServer(serverID) == nil {
+			notifications.OnToast.Notify(gettext.Get("Enable the server to view its details"))
+			return
+		}
+		closeDialog()
+		router.Navigate("server/" + serverID)
+	}))
+	row.AddSuffix(&details.Widget)
+
+	if onConnectionPrefs != nil {
+		connection := gtk.NewButtonFromIconName("network-wired-symbolic")
+		connection.SetValign(gtk.AlignCenterValue)
+		connection.SetTooltipText(gettext.Get("Connection"))
+		connection.AddCssClass("flat")
+		connection.ConnectClicked(new(func(b gtk.Button) {
+			newConnectionDialog(srv, func(prefs sources.ConnectionPrefs) {
+				onConnectionPrefs(serverID, prefs)
+			}).Present(&row.Widget)
+		}))
+		row.AddSuffix(&connection.Widget)
+	}
+
+	// Libraries and restrictions are fetched from the server the first time
+	// the row is expanded.
+	loaded := false
+	row.ConnectSignal("notify::expanded", new(func() {
+		if loaded || !row.GetExpanded() {
+			return
+		}
+		loaded = true
+		loadServerDetails(ctx, mgr, row, srv)
+	}))
+
+	return row
+}
+
+func loadServerDetails(ctx context.Context, mgr *sources.Manager, row *adw.ExpanderRow, srv *sources.Server) {
+	serverID := srv.ID
+	owned := srv.Owned
+
+	loading := adw.NewActionRow()
+	loading.SetTitle(gettext.Get("Loading libraries…"))
+	loading.AddCssClass("dim-label")
+	row.AddRow(&loading.Widget)
+
+	go func() {
+		src := mgr.SourceForServer(serverID)
+		var info *sources.ServerInfo
+		var sections []sources.LibrarySection
+		var err error
+		if src == nil {
+			err = fmt.Errorf("server %s is not connected", serverID)
+		} else {
+			sections, err = mgr.ServerLibraries(ctx, serverID)
+			if admin, ok := sources.AsAdmin(src); ok && err == nil && !owned {
+				if info, err = admin.ServerInfo(ctx); err != nil {
+					slog.Debug("failed to fetch server restrictions", "server", serverID, "error", err)
+					err = nil
+				}
+			}
+		}
+
+		schwifty.OnMainThreadOncePure(func() {
+			row.Remove(&loading.Widget)
+			if err != nil {
+				slog.Warn("failed to fetch server libraries", "server", serverID, "error", err)
+				message := adw.NewActionRow()
+				message.SetTitle(gettext.Get("Libraries are unavailable while the server is unreachable."))
+				message.AddCssClass("dim-label")
+				row.AddRow(&message.Widget)
+				return
+			}
+
+			if info != nil {
+				restrictions := adw.NewActionRow()
+				restrictions.SetTitle(gettext.Get("Permissions"))
+				restrictions.SetSubtitle(restrictionsText(info))
+				row.AddRow(&restrictions.Widget)
+			}
+
+			keys := make([]string, len(sections))
+			for i, sec := range sections {
+				keys[i] = sec.Key
+			}
+			for _, sec := range sections {
+				sectionKey := sec.Key
+				library := adw.NewSwitchRow()
+				library.SetUseMarkup(false)
+				library.SetTitle(sec.Title)
+				library.SetSubtitle(sectionTypeText(sec.Type))
+				library.SetActive(mgr.IsLibraryEnabled(serverID, sectionKey))
+				library.ConnectSignal("notify::active", new(func() {
+					mgr.SetLibraryEnabled(serverID, sectionKey, library.GetActive(), keys)
+				}))
+				row.AddRow(&library.Widget)
+			}
+		})
+	}()
+}
+
+// serverSubtitle describes how the server is shared and its connection.
+func serverSubtitle(srv *sources.Server, offline bool) string {
+	status := serverStatusText(srv, offline)
+	switch {
+	case srv.Owned:
+		return status
+	case srv.Home:
+		return fmt.Sprintf(gettext.Get("Shared in your Plex Home · %s"), status)
+	case srv.SharedBy != "":
+		return fmt.Sprintf(gettext.Get("Shared by %s · %s"), srv.SharedBy, status)
+	default:
+		return fmt.Sprintf(gettext.Get("Shared · %s"), status)
+	}
+}
+
+// restrictionsText summarizes what the owner of a shared server allows.
+func restrictionsText(info *sources.ServerInfo) string {
+	var allowed, denied []string
+	add := func(ok bool, name string) {
+		if ok {
+			allowed = append(allowed, name)
+		} else {
+			denied = append(denied, name)
+		}
+	}
+	add(info.AllowSync, gettext.Get("downloads"))
+	add(info.AllowChannelAccess, gettext.Get("channels"))
+	add(info.AllowCameraUpload, gettext.Get("camera upload"))
+	add(info.AllowMediaDeletion, gettext.Get("deleting media"))
+
+	var parts []string
+	if len(allowed) > 0 {
+		parts = append(parts, fmt.Sprintf(gettext.Get("Allowed: %s"), strings.Join(allowed, ", ")))
+	}
+	if len(denied) > 0 {
+		parts = append(parts, fmt.Sprintf(gettext.Get("Not allowed: %s"), strings.Join(denied, ", ")))
+	}
+	return strings.Join(parts, " · ")
+}
+
+func sectionTypeText(sectionType string) string {
+	switch sectionType {
+	case "movie":
+		return gettext.Get("Movies")
+	case "show":
+		return gettext.Get("TV Shows")
+	case "artist":
+		return gettext.Get("Music")
+	case "photo":
+		return gettext.Get("Photos")
+	default:
+		return sectionType
+	}
+}
+
+// providerName returns the display name of a provider type.
+func providerName(providerType sources.ProviderType) string {
+	switch providerType {
+	case sources.ProviderJellyfin:
+		return "Jellyfin"
+	case sources.ProviderEmby:
+		return "Emby"
+	default:
+		return "Plex"
+	}
+}
+
+func serverStatusText(srv *sources.Server, offline bool) string {
+	if offline {
+		return gettext.Get("Offline, showing saved content")
+	}
 	if !srv.Reachable {
 		return gettext.Get("Unreachable")
 	}
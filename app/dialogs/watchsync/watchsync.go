@@ -0,0 +1,151 @@
+package watchsync
+
+import (
+	"context"
+	"log/slog"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/utils/notifications"
+)
+
+// NewWatchSync creates a dialog that compares the watch state of items
+// available on several servers, lists the changes a sync would make, and
+// makes them once confirmed.
+func NewWatchSync(ctx context.Context, mgr *sources.Manager) *adw.Dialog {
+	ctx, cancel := context.WithCancel(ctx)
+
+	dialog := adw.NewDialog()
+	dialog.SetTitle(gettext.Get("Sync Watch State"))
+	dialog.SetContentWidth(520)
+	dialog.SetContentHeight(600)
+
+	toolbarView := adw.NewToolbarView()
+	headerBar := adw.NewHeaderBar()
+	toolbarView.AddTopBar(&headerBar.Widget)
+	dialog.SetChild(&toolbarView.Widget)
+
+	showMessage := func(message string) {
+		toolbarView.SetContent(VStack(
+			Label(message).WithCSSClass("dim-label").Wrap(true),
+		).VAlign(gtk.AlignCenterValue).VExpand(true).VMargin(40).HMargin(20).ToGTK())
+	}
+
+	showLoading := func(message string) {
+		toolbarView.SetContent(VStack(
+			Spinner().SizeRequest(32, 32),
+			Label(message).WithCSSClass("dim-label"),
+		).Spacing(20).VAlign(gtk.AlignCenterValue).VExpand(true).VMargin(40).ToGTK())
+	}
+
+	serverName := func(serverID string) string {
+		if src := mgr.SourceForServer(serverID); src != nil {
+			return src.Name()
+		}
+		return serverID
+	}
+
+	var showReport func(report *sources.WatchSyncReport)
+	apply := func(report *sources.WatchSyncReport) {
+		showLoading(gettext.Get("Syncing watch state…"))
+		go func() {
+			mgr.ApplyWatchSync(ctx, report)
+			schwifty.OnMainThreadOncePure(func() {
+				if ctx.Err() != nil {
+					return
+				}
+				if failed := report.Failed(); failed > 0 {
+					notifications.OnToast.Notify(gettext.GetN("%d change failed", "%d changes failed", failed, failed))
+				} else {
+					notifications.OnToast.Notify(gettext.Get("Watch state synced"))
+				}
+				showReport(report)
+			})
+		}()
+	}
+
+	showReport = func(report *sources.WatchSyncReport) {
+		if len(report.Changes) == 0 {
+			showMessage(gettext.Get("The watch state of your servers is in sync."))
+			return
+		}
+
+		var rows []any
+		for _, change := range report.Changes {
+			row := adw.NewActionRow()
+			row.SetUseMarkup(false)
+			row.SetTitle(change.Title)
+			row.SetSubtitle(changeText(change, serverName(change.ServerID), serverName(change.FromServerID)))
+			if change.Err != nil {
+				icon := gtk.NewImageFromIconName("dialog-warning-symbolic")
+				icon.SetTooltipText(change.Err.Error())
+				icon.AddCssClass("error")
+				row.AddSuffix(&icon.Widget)
+			}
+			rows = append(rows, Widget(&row.Widget))
+		}
+
+		group := PreferencesGroup(rows...).
+			Title(gettext.GetN("%d Change", "%d Changes", len(report.Changes), len(report.Changes)))
+		var content any
+		if report.Applied {
+			content = VStack(group).Spacing(18).HMargin(12).VMargin(12)
+		} else {
+			group = group.Description(gettext.Get("Where servers disagree, the most recently watched copy wins."))
+			content = VStack(
+				group,
+				Button().
+					Label(gettext.Get("Sync")).
+					WithCSSClass("pill").
+					WithCSSClass("suggested-action").
+					HAlign(gtk.AlignCenterValue).
+					ConnectClicked(func(b gtk.Button) {
+						apply(report)
+					}),
+			).Spacing(18).HMargin(12).VMargin(12)
+		}
+		toolbarView.SetContent(ScrolledWindow().
+			Child(content).
+			PropagateNaturalHeight(true).
+			ToGTK())
+	}
+
+	dialog.ConnectClosed(new(func(adw.Dialog) {
+		cancel()
+	}))
+
+	showLoading(gettext.Get("Comparing watch state…"))
+	go func() {
+		report, err := mgr.PlanWatchSync(ctx)
+		schwifty.OnMainThreadOncePure(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				slog.Error("failed to compare watch state", "error", err)
+				showMessage(gettext.Get("The watch state could not be compared."))
+				return
+			}
+			showReport(report)
+		})
+	}()
+
+	return dialog
+}
+
+// changeText describes a change of a sync report.
+func changeText(change sources.WatchChange, server, from string) string {
+	switch change.Action {
+	case sources.WatchMarkWatched:
+		return gettext.Getf("Mark watched on %s, as on %s", server, from)
+	case sources.WatchMarkUnwatched:
+		return gettext.Getf("Mark unwatched on %s, as on %s", server, from)
+	default:
+		minutes := change.Offset / 60000
+		return gettext.Getf("Resume at %d:%02d on %s, as on %s", minutes/60, minutes%60, server, from)
+	}
+}
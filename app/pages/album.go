@@ -0,0 +1,227 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/audio"
+	"github.com/0skillallluck/scanline/app/components/widgets"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+var AlbumRoute = router.NewRoute("album/:server/:ratingKey", Album)
+
+func Album(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey string) *router.Response {
+	mgr := appCtx.Manager
+	src := mgr.SourceForServer(serverID)
+	if src == nil {
+		return router.FromError(gettext.Get("Album"), errSourceNotFound(serverID))
+	}
+
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
+	if err != nil {
+		return router.FromError(gettext.Get("Album"), err)
+	}
+
+	tracks, err := src.GetChildren(ctx, id)
+	if err != nil {
+		slog.Warn("failed to fetch tracks", "ratingKey", ratingKey, "error", err)
+	}
+
+	body := VStack().Spacing(25).VMargin(20).HMargin(40)
+
+	var totalDuration int
+	for _, t := range tracks {
+		totalDuration += t.Duration
+	}
+	var badges []string
+	if meta.Year > 0 {
+		badges = append(badges, strconv.Itoa(meta.Year))
+	}
+	badges = append(badges, gettext.GetN("%d Track", "%d Tracks", len(tracks), len(tracks)))
+	if totalDuration > 0 {
+		badges = append(badges, widgets.FormatDuration(totalDuration))
+	}
+
+	var queue []audio.Track
+	queuePos := make(map[sources.ItemID]int)
+	for i := range tracks {
+		if track, ok := audio.TrackFromMetadata(src, &tracks[i]); ok {
+			queuePos[track.ItemID] = len(queue)
+			queue = append(queue, track)
+		}
+	}
+
+	heroContent := widgets.HeroContent(widgets.HeroContentParams{
+		Title:               meta.Title,
+		Subtitle:            meta.ParentTitle,
+		SubtitleClass:       "title-2 dimmed",
+		SubtitleActionName:  "win.route.artist",
+		SubtitleActionValue: serverID + "/" + string(meta.ParentID),
+		Badges:              badges,
+		BuildButtonRow: func() schwifty.Box {
+			return albumPlayButtons(queue)
+		},
+		Summary: meta.Summary,
+		MetadataRows: []widgets.MetadataRow{
+			{Label: "Genres", Value: joinTags(meta.Genre)},
+			{Label: "Label", Value: meta.Studio},
+		},
+	})
+
+	hero := widgets.HeroSection(
+		widgets.HeroPosterParams{
+			ImageURL: src.PhotoTranscodeURL(meta.Thumb, 300, 300),
+			Width:    300,
+			Height:   300,
+		},
+		heroContent,
+	)
+
+	body = body.Append(hero)
+
+	// Track listing, split by disc when the album has more than one
+	discs := make(map[int]bool)
+	for _, t := range tracks {
+		discs[t.ParentIndex] = true
+	}
+	multiDisc := len(discs) > 1
+
+	var (
+		rows        []any
+		currentDisc = -1
+	)
+	flush := func() {
+		if len(rows) == 0 {
+			return
+		}
+		group := PreferencesGroup(rows...)
+		if multiDisc {
+			group = group.Title(gettext.Getf("Disc %d", currentDisc))
+		}
+		body = body.Append(group)
+		rows = nil
+	}
+	for i := range tracks {
+		track := &tracks[i]
+		if track.ParentIndex != currentDisc {
+			flush()
+			currentDisc = track.ParentIndex
+		}
+		var onPlay func()
+		if pos, ok := queuePos[track.ID]; ok {
+			onPlay = func() { audio.Play(queue, pos) }
+		}
+		rows = append(rows, Widget(&albumTrackRow(track, meta.ParentTitle, serverID, onPlay).Widget))
+	}
+	flush()
+
+	pageTitle := meta.Title
+	if meta.ParentTitle != "" {
+		pageTitle = meta.ParentTitle + " - " + meta.Title
+	}
+
+	return &router.Response{
+		PageTitle: pageTitle,
+		View: ScrolledWindow().
+			Child(Clamp().MaximumSize(1200).Child(body)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+	}
+}
+
+// albumTrackRow builds a track listing row with number, artist credit,
+// audio details and duration. onPlay starts album playback from this track
+// and is nil when the track cannot be played.
+func albumTrackRow(track *sources.Item, albumArtist, serverID string, onPlay func()) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(track.Title)
+
+	subtitle := audioDetails(track.Media)
+	// Featured or compilation artists are credited per track.
+	if track.OriginalTitle != "" && track.OriginalTitle != albumArtist {
+		if subtitle != "" {
+			subtitle = track.OriginalTitle + " · " + subtitle
+		} else {
+			subtitle = track.OriginalTitle
+		}
+	}
+	row.SetSubtitle(subtitle)
+
+	number := gtk.NewLabel(fmt.Sprintf("%d", track.Index))
+	number.AddCssClass("dim-label")
+	number.AddCssClass("numeric")
+	number.SetWidthChars(3)
+	row.AddPrefix(&number.Widget)
+
+	if track.Duration > 0 {
+		duration := gtk.NewLabel(widgets.FormatTimestamp(track.Duration))
+		duration.AddCssClass("dim-label")
+		duration.AddCssClass("numeric")
+		row.AddSuffix(&duration.Widget)
+	}
+
+	if onPlay != nil {
+		play := gtk.NewButtonFromIconName("media-playback-start-symbolic")
+		play.SetValign(gtk.AlignCenterValue)
+		play.SetTooltipText(gettext.Get("Play from Here"))
+		play.AddCssClass("flat")
+		play.ConnectClicked(new(func(gtk.Button) {
+			onPlay()
+		}))
+		row.AddSuffix(&play.Widget)
+	}
+
+	row.SetActivatable(true)
+	row.SetActionName("win.route.track")
+	row.SetActionTargetValue(glib.NewVariantString(serverID + "/" + string(track.ID)))
+
+	return row
+}
+
+// albumPlayButtons builds the Play and Shuffle buttons for an album.
+func albumPlayButtons(queue []audio.Track) schwifty.Box {
+	return HStack().Spacing(10).
+		Append(
+			Button().
+				Child(
+					HStack(
+						Image().FromIconName("media-playback-start-symbolic"),
+						Label(gettext.Get("Play")),
+					).Spacing(6),
+				).
+				TooltipText(gettext.Get("Play this album")).
+				WithCSSClass("suggested-action").
+				WithCSSClass("pill").
+				Sensitive(len(queue) > 0).
+				ConnectClicked(func(b gtk.Button) {
+					audio.Play(queue, 0)
+				}),
+		).
+		Append(
+			Button().
+				Child(
+					HStack(
+						Image().FromIconName("media-playlist-shuffle-symbolic"),
+						Label(gettext.Get("Shuffle")),
+					).Spacing(6),
+				).
+				TooltipText(gettext.Get("Play this album in random order")).
+				WithCSSClass("pill").
+				Sensitive(len(queue) > 0).
+				ConnectClicked(func(b gtk.Button) {
+					audio.PlayShuffled(queue)
+				}),
+		)
+}
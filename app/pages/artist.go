@@ -0,0 +1,114 @@
+package pages
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/cards"
+	"github.com/0skillallluck/scanline/app/components/lists"
+	"github.com/0skillallluck/scanline/app/components/widgets"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+var ArtistRoute = router.NewRoute("artist/:server/:ratingKey", Artist)
+
+func Artist(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey string) *router.Response {
+	mgr := appCtx.Manager
+	src := mgr.SourceForServer(serverID)
+	if src == nil {
+		return router.FromError(gettext.Get("Artist"), errSourceNotFound(serverID))
+	}
+
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
+	if err != nil {
+		return router.FromError(gettext.Get("Artist"), err)
+	}
+
+	albums, err := src.GetChildren(ctx, id)
+	if err != nil {
+		slog.Warn("failed to fetch albums", "ratingKey", ratingKey, "error", err)
+	}
+	relatedHubs, err := src.RelatedHubs(ctx, id)
+	if err != nil {
+		slog.Debug("failed to fetch related hubs", "ratingKey", ratingKey, "error", err)
+	}
+
+	// Discography, newest first
+	slices.SortStableFunc(albums, func(a, b sources.Item) int {
+		return cmp.Compare(b.Year, a.Year)
+	})
+
+	body := VStack().Spacing(25).VMargin(20).HMargin(40)
+
+	heroContent := widgets.HeroContent(widgets.HeroContentParams{
+		Title:   meta.Title,
+		Badges:  []string{gettext.GetN("%d Album", "%d Albums", len(albums), len(albums))},
+		Summary: meta.Summary,
+		MetadataRows: []widgets.MetadataRow{
+			{Label: "Genres", Value: joinTags(meta.Genre)},
+		},
+	})
+
+	hero := widgets.HeroSection(
+		widgets.HeroPosterParams{
+			ImageURL: src.PhotoTranscodeURL(meta.Thumb, 300, 300),
+			Width:    300,
+			Height:   300,
+		},
+		heroContent,
+	)
+
+	body = body.Append(hero)
+
+	// Discography section
+	if len(albums) > 0 {
+		body = body.Append(
+			Label(gettext.Get("Discography")).
+				WithCSSClass("title-4").
+				HAlign(gtk.AlignStartValue),
+		)
+
+		albumGrid := WrapBox().
+			ConnectConstruct(func(w *adw.WrapBox) {
+				w.SetChildSpacing(20)
+				w.SetLineSpacing(20)
+				w.SetLineHomogeneous(true)
+			})
+		for i := range albums {
+			album := &albums[i]
+			albumGrid = albumGrid.Append(cards.NewAlbumPoster(album, src.PhotoTranscodeURL(album.Thumb, 240, 240), serverID, false))
+		}
+		body = body.Append(albumGrid)
+	}
+
+	// Related hubs (similar artists, ...)
+	coverURL := func(thumb string) string {
+		return src.PhotoTranscodeURL(thumb, 240, 240)
+	}
+	for i := range relatedHubs {
+		hub := &relatedHubs[i]
+		list := lists.NewHorizontalList(hub.Title)
+		if lists.RenderHub(list, hub, coverURL, serverID) {
+			if hub.More && hub.Key != "" {
+				list.SetViewAllRoute(hubPath(serverID, hub))
+			}
+			body = body.Append(list.SetPageMargin(0))
+		}
+	}
+
+	return &router.Response{
+		PageTitle: meta.Title,
+		View: ScrolledWindow().
+			Child(Clamp().MaximumSize(1200).Child(body)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+	}
+}
@@ -3,7 +3,6 @@ package pages
 import (
 	"context"
 	"log/slog"
-	"strconv"
 
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
 	"codeberg.org/puregotk/puregotk/v4/adw"
@@ -36,9 +35,9 @@ func Cast(ctx context.Context, appCtx *appctx.AppContext, serverID, tagID string
 		return src.PhotoTranscodeURL(thumb, 240, 360)
 	}
 
-	var allContent []sources.Metadata
+	var allContent []sources.Item
 	var personName, personThumb string
-	seen := make(map[string]bool)
+	seen := make(map[sources.ItemID]bool)
 
 	// Search across actor, director, and writer credits.
 	filters := []sources.ContentOptions{
@@ -59,8 +58,8 @@ func Cast(ctx context.Context, appCtx *appctx.AppContext, serverID, tagID string
 				continue
 			}
 			for _, item := range content {
-				if !seen[item.RatingKey] {
-					seen[item.RatingKey] = true
+				if !seen[item.ID] {
+					seen[item.ID] = true
 					allContent = append(allContent, item)
 				}
 			}
@@ -70,14 +69,13 @@ func Cast(ctx context.Context, appCtx *appctx.AppContext, serverID, tagID string
 	// The listing endpoint doesn't include tag data, so fetch full metadata
 	// for the first result to extract the person's name and thumbnail.
 	if len(allContent) > 0 {
-		tagIDInt, _ := strconv.Atoi(tagID)
-		meta, err := src.GetMetadata(ctx, allContent[0].RatingKey)
+		meta, err := src.GetMetadata(ctx, allContent[0].ID)
 		if err == nil {
 			// Search across all credit types for the matching tag ID.
-			for _, tags := range [][]sources.Tag{meta.Role, meta.Director, meta.Writer} {
+			for _, tags := range [][]sources.Tag{meta.Cast, meta.Director, meta.Writer} {
 				for _, tag := range tags {
-					if tag.ID == tagIDInt {
-						personName = tag.Tag
+					if tag.ID == tagID {
+						personName = tag.Name
 						personThumb = tag.Thumb
 						break
 					}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"strconv"
 
 	"codeberg.org/dergs/tonearm/pkg/schwifty"
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
@@ -15,6 +14,7 @@ import (
 	"github.com/0skillallluck/scanline/app/components/player"
 	"github.com/0skillallluck/scanline/app/components/widgets"
 	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
 	"github.com/0skillallluck/scanline/internal/gettext"
 	"github.com/0skillallluck/scanline/utils/notifications"
 )
@@ -28,7 +28,8 @@ func Episode(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 		return router.FromError(gettext.Get("Episode"), errSourceNotFound(serverID))
 	}
 
-	meta, err := src.GetMetadata(ctx, ratingKey)
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
 	if err != nil {
 		return router.FromError(gettext.Get("Episode"), err)
 	}
@@ -52,13 +53,13 @@ func Episode(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 		Title:               meta.Title,
 		Subtitle:            meta.GrandparentTitle,
 		SubtitleActionName:  "win.route.show",
-		SubtitleActionValue: serverID + "/" + meta.GrandparentRatingKey,
+		SubtitleActionValue: serverID + "/" + string(meta.GrandparentID),
 		BadgeLinks: []widgets.BadgeLink{{
 			Label:       widgets.FormatSeasonLabel(meta.ParentIndex),
 			ActionName:  "win.route.season",
-			ActionValue: serverID + "/" + meta.ParentRatingKey,
+			ActionValue: serverID + "/" + string(meta.ParentID),
 		}},
-		Badges: []string{widgets.FormatEpisodeOnlyLabel(meta.Index), widgets.FormatDuration(meta.Duration), meta.ContentRating},
+		Badges:     []string{widgets.FormatEpisodeOnlyLabel(meta.Index), widgets.FormatDuration(meta.Duration), meta.ContentRating},
 		Ratings:    meta.Ratings,
 		UserRating: meta.UserRating,
 		BuildButtonRow: func() schwifty.Box {
@@ -82,7 +83,7 @@ func Episode(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 									Title:       meta.Title,
 									PartKey:     meta.Media[0].Part[0].Key,
 									Window:      appCtx.Window,
-									RatingKey:   ratingKey,
+									ItemID:      id,
 									Media:       meta.Media,
 									Source:      src,
 									ViewOffset:  meta.ViewOffset,
@@ -107,9 +108,9 @@ func Episode(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 							go func() {
 								var err error
 								if watched {
-									err = src.Unscrobble(ctx, ratingKey)
+									err = src.Unscrobble(ctx, id)
 								} else {
-									err = src.Scrobble(ctx, ratingKey)
+									err = src.Scrobble(ctx, id)
 								}
 								if err != nil {
 									slog.Error("failed to update watch status", "ratingKey", ratingKey, "error", err)
@@ -121,6 +122,7 @@ func Episode(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 								}
 								schwifty.OnMainThreadOncePure(func() {
 									b.SetSensitive(true)
+									syncMarkedWatchState(appCtx, serverID, id, !watched)
 									if watched {
 										meta.ViewCount = 0
 										b.SetTooltipText(gettext.Get("Mark this episode as watched"))
@@ -168,10 +170,10 @@ func Episode(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 	}
 
 	// Cast section
-	if len(meta.Role) > 0 {
+	if len(meta.Cast) > 0 {
 		castList := lists.NewHorizontalList(gettext.Get("Cast"))
-		for _, role := range meta.Role {
-			castList.Append(cards.NewCastMember(role.Tag, role.Role, src.PhotoTranscodeURL(role.Thumb, 140, 140), serverID, strconv.Itoa(role.ID)))
+		for _, role := range meta.Cast {
+			castList.Append(cards.NewCastMember(role.Name, role.Role, src.PhotoTranscodeURL(role.Thumb, 140, 140), serverID, role.ID))
 		}
 		body = body.Append(castList.SetPageMargin(0))
 	}
@@ -3,7 +3,6 @@ package pages
 import (
 	"context"
 	"log/slog"
-	"strconv"
 
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
 	"codeberg.org/puregotk/puregotk/v4/adw"
@@ -34,8 +33,8 @@ func Genre(ctx context.Context, appCtx *appctx.AppContext, serverID, genreID str
 		return src.PhotoTranscodeURL(thumb, 240, 360)
 	}
 
-	var allContent []sources.Metadata
-	seen := make(map[string]bool)
+	var allContent []sources.Item
+	seen := make(map[sources.ItemID]bool)
 
 	filter := sources.ContentOptions{Genre: genreID}
 
@@ -50,8 +49,8 @@ func Genre(ctx context.Context, appCtx *appctx.AppContext, serverID, genreID str
 			continue
 		}
 		for _, item := range content {
-			if !seen[item.RatingKey] {
-				seen[item.RatingKey] = true
+			if !seen[item.ID] {
+				seen[item.ID] = true
 				allContent = append(allContent, item)
 			}
 		}
@@ -61,12 +60,11 @@ func Genre(ctx context.Context, appCtx *appctx.AppContext, serverID, genreID str
 	// metadata for the first result to extract the genre name.
 	genreName := gettext.Get("Genre")
 	if len(allContent) > 0 {
-		genreIDInt, _ := strconv.Atoi(genreID)
-		meta, err := src.GetMetadata(ctx, allContent[0].RatingKey)
+		meta, err := src.GetMetadata(ctx, allContent[0].ID)
 		if err == nil {
 			for _, g := range meta.Genre {
-				if g.ID == genreIDInt {
-					genreName = g.Tag
+				if g.ID == genreID {
+					genreName = g.Name
 					break
 				}
 			}
@@ -1,7 +1,84 @@
 package pages
 
-import "fmt"
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/preference"
+	"github.com/0skillallluck/scanline/app/sources"
+)
 
 func errSourceNotFound(serverID string) error {
 	return fmt.Errorf("source not found: %s", serverID)
 }
+
+// errNotSupported is returned when a source lacks an optional capability.
+func errNotSupported(src sources.Source, feature string) error {
+	return fmt.Errorf("%s does not support %s", src.Name(), feature)
+}
+
+// syncMarkedWatchState copies a watch state the user just set to the
+// item's copies on the other servers in the background, if watch state
+// sync is enabled.
+func syncMarkedWatchState(appCtx *appctx.AppContext, serverID string, id sources.ItemID, watched bool) {
+	if !preference.General().SyncWatchState() {
+		return
+	}
+	go func() {
+		if _, err := appCtx.Manager.SyncMarkedWatchState(appCtx.Ctx, serverID, id, watched); err != nil {
+			slog.Warn("failed to sync watch state", "server", serverID, "item", id, "error", err)
+		}
+	}()
+}
+
+// joinTags joins tag names with commas, e.g. for genres without a route.
+func joinTags(tags []sources.Tag) string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// audioDetails describes the audio stream of a track, e.g. "FLAC · 24-bit/96 kHz · 2 ch".
+func audioDetails(media []sources.MediaVersion) string {
+	for _, m := range media {
+		for _, p := range m.Part {
+			for _, s := range p.Stream {
+				if s.StreamType != sources.StreamAudio {
+					continue
+				}
+				details := []string{strings.ToUpper(s.Codec)}
+				switch {
+				case s.BitDepth > 0 && s.SamplingRate > 0:
+					details = append(details, fmt.Sprintf("%d-bit/%g kHz", s.BitDepth, float64(s.SamplingRate)/1000))
+				case s.SamplingRate > 0:
+					details = append(details, fmt.Sprintf("%g kHz", float64(s.SamplingRate)/1000))
+				}
+				if s.Bitrate > 0 {
+					details = append(details, fmt.Sprintf("%d kbps", s.Bitrate))
+				}
+				if s.Channels > 0 {
+					details = append(details, fmt.Sprintf("%d ch", s.Channels))
+				}
+				return strings.Join(details, " · ")
+			}
+		}
+	}
+	return ""
+}
+
+// propertyRow builds a read-only row showing a labeled, selectable value.
+func propertyRow(title, value string) any {
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(title)
+	row.SetSubtitle(value)
+	row.SetSubtitleSelectable(true)
+	row.AddCssClass("property")
+	return Widget(&row.Widget)
+}
@@ -0,0 +1,374 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/dergs/tonearm/pkg/schwifty/state"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+var HistoryRoute = router.NewRoute("history", watchHistory)
+
+// historyPageSize is the number of entries fetched per server and page.
+const historyPageSize = 100
+
+var (
+	historyPeriodLabels = []string{
+		gettext.Get("Any Time"),
+		gettext.Get("Past Week"),
+		gettext.Get("Past Month"),
+		gettext.Get("Past Year"),
+	}
+	historyPeriods = []time.Duration{
+		0,
+		7 * 24 * time.Hour,
+		30 * 24 * time.Hour,
+		365 * 24 * time.Hour,
+	}
+)
+
+// historyItem pairs a history entry with the server it came from.
+type historyItem struct {
+	entry      sources.HistoryEntry
+	serverID   string
+	serverName string
+	account    string
+}
+
+// historyServer is a server that keeps a watch history, along with its
+// accounts.
+type historyServer struct {
+	src      sources.Source
+	hist     sources.HistorySource
+	accounts []sources.ServerAccount
+}
+
+// historyCursor pages through the watch history of one server.
+type historyCursor struct {
+	server  *historyServer
+	opts    sources.HistoryOptions
+	pending []historyItem
+	done    bool
+}
+
+// fetch appends the next page of the server's history to pending.
+func (c *historyCursor) fetch(ctx context.Context) {
+	opts := c.opts
+	entries, total, err := c.server.hist.History(ctx, &opts)
+	if err != nil {
+		slog.Error("failed to fetch watch history", "source", c.server.src.Name(), "error", err)
+		c.done = true
+		return
+	}
+	c.opts.Start += len(entries)
+	c.done = len(entries) == 0 || c.opts.Start >= total
+
+	names := make(map[int]string, len(c.server.accounts))
+	for _, acct := range c.server.accounts {
+		names[acct.ID] = acct.Name
+	}
+	for _, e := range entries {
+		c.pending = append(c.pending, historyItem{
+			entry:      e,
+			serverID:   c.server.src.ID(),
+			serverName: c.server.src.Name(),
+			account:    names[e.AccountID],
+		})
+	}
+}
+
+// historyFeed merges the watch histories of several servers, most recent
+// first, a page at a time.
+type historyFeed struct {
+	cursors []*historyCursor
+}
+
+// newHistoryFeed creates a feed of the entries viewed by an account, or by
+// every account if empty, at or after since, or at any time if zero.
+func newHistoryFeed(servers []*historyServer, account string, since int64) *historyFeed {
+	feed := &historyFeed{}
+	for _, server := range servers {
+		opts := sources.HistoryOptions{Size: historyPageSize, ViewedAfter: since}
+		if account != "" {
+			idx := slices.IndexFunc(server.accounts, func(a sources.ServerAccount) bool {
+				return a.Name == account
+			})
+			if idx < 0 {
+				continue
+			}
+			opts.AccountID = server.accounts[idx].ID
+		}
+		feed.cursors = append(feed.cursors, &historyCursor{server: server, opts: opts})
+	}
+	return feed
+}
+
+// next returns the next entries of the merged history and whether more
+// remain.
+func (f *historyFeed) next(ctx context.Context) ([]historyItem, bool) {
+	for _, c := range f.cursors {
+		if !c.done && len(c.pending) == 0 {
+			c.fetch(ctx)
+		}
+	}
+
+	// The entries a server has yet to return are older than the ones it
+	// returned, so only entries at least as recent as the oldest fetched
+	// entry of every server with more are in order.
+	var frontier int64
+	for _, c := range f.cursors {
+		if !c.done && len(c.pending) > 0 {
+			frontier = max(frontier, c.pending[len(c.pending)-1].entry.ViewedAt)
+		}
+	}
+
+	var items []historyItem
+	more := false
+	for _, c := range f.cursors {
+		n := len(c.pending)
+		if frontier > 0 {
+			n = sort.Search(len(c.pending), func(i int) bool {
+				return c.pending[i].entry.ViewedAt < frontier
+			})
+		}
+		items = append(items, c.pending[:n]...)
+		c.pending = c.pending[n:]
+		if !c.done || len(c.pending) > 0 {
+			more = true
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].entry.ViewedAt > items[j].entry.ViewedAt
+	})
+	return items, more
+}
+
+func watchHistory(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
+	var (
+		servers      []*historyServer
+		accountNames []string
+	)
+	for _, src := range appCtx.Manager.EnabledSources() {
+		hist, ok := sources.AsHistory(src)
+		if !ok {
+			continue
+		}
+		accounts, err := hist.Accounts(ctx)
+		if err != nil {
+			slog.Warn("failed to fetch server accounts", "source", src.Name(), "error", err)
+		}
+		for _, acct := range accounts {
+			if acct.Name != "" && !slices.Contains(accountNames, acct.Name) {
+				accountNames = append(accountNames, acct.Name)
+			}
+		}
+		servers = append(servers, &historyServer{src: src, hist: hist, accounts: accounts})
+	}
+	slices.Sort(accountNames)
+
+	accountDD := gtk.NewDropDownFromStrings(append([]string{gettext.Get("All Accounts")}, accountNames...))
+	accountDD.SetTooltipText(gettext.Get("Account"))
+	accountDD.SetVisible(len(accountNames) > 1)
+	periodDD := gtk.NewDropDownFromStrings(historyPeriodLabels)
+	periodDD.SetTooltipText(gettext.Get("Period"))
+
+	viewState := state.NewStateful[any](Spinner().SizeRequest(32, 32).HAlign(gtk.AlignCenterValue).VAlign(gtk.AlignCenterValue))
+
+	// generation discards results of loads that were superseded by a newer
+	// filter selection. loadNext loads the next page of the current view.
+	var (
+		generation int
+		loadNext   func()
+	)
+	load := func() {
+		generation++
+		gen := generation
+		loadNext = nil
+
+		var account string
+		if selected := int(accountDD.GetSelected()); selected > 0 {
+			account = accountNames[selected-1]
+		}
+		var since int64
+		if period := historyPeriods[periodDD.GetSelected()]; period > 0 {
+			since = time.Now().Add(-period).Unix()
+		}
+		filtered := account != "" || since > 0
+
+		viewState.SetValue(Spinner().SizeRequest(32, 32).HAlign(gtk.AlignCenterValue).VAlign(gtk.AlignCenterValue))
+		feed := newHistoryFeed(servers, account, since)
+		go func() {
+			items, more := feed.next(ctx)
+			schwifty.OnMainThreadOncePure(func() {
+				if gen != generation {
+					return
+				}
+				view, next := historyView(ctx, feed, items, more, filtered)
+				viewState.SetValue(view)
+				loadNext = next
+			})
+		}()
+	}
+	accountDD.ConnectSignal("notify::selected", new(func() { load() }))
+	periodDD.ConnectSignal("notify::selected", new(func() { load() }))
+	load()
+
+	return &router.Response{
+		PageTitle: gettext.Get("Watch History"),
+		Toolbar: HStack(
+			Widget(&accountDD.Widget),
+			Widget(&periodDD.Widget),
+		).Spacing(8),
+		View: ScrolledWindow().
+			BindChild(viewState).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue).
+			ConnectConstruct(func(sw *gtk.ScrolledWindow) {
+				sw.ConnectEdgeReached(new(func(_ gtk.ScrolledWindow, pos gtk.PositionType) {
+					if pos == gtk.PosBottomValue && loadNext != nil {
+						loadNext()
+					}
+				}))
+			}),
+	}
+}
+
+// historyView lists the first entries of a feed grouped by day. It returns
+// the view and a function that appends the next page of the feed.
+func historyView(ctx context.Context, feed *historyFeed, items []historyItem, more, filtered bool) (any, func()) {
+	if len(items) == 0 {
+		description := gettext.Get("Nothing has been watched yet.")
+		if filtered {
+			description = gettext.Get("Nothing was watched that matches these filters.")
+		}
+		return StatusPage().
+			IconName("document-open-recent-symbolic").
+			Title(gettext.Get("Watch History")).
+			Description(description), nil
+	}
+
+	days := gtk.NewBox(gtk.OrientationVerticalValue, 25)
+	var (
+		group      *adw.PreferencesGroup
+		currentDay string
+	)
+	appendItems := func(items []historyItem) {
+		for _, item := range items {
+			viewedAt := time.Unix(item.entry.ViewedAt, 0)
+			day := historyDayLabel(viewedAt)
+			if group == nil || day != currentDay {
+				group = adw.NewPreferencesGroup()
+				group.SetTitle(day)
+				days.Append(&group.Widget)
+				currentDay = day
+			}
+			group.Add(&historyRow(item, viewedAt).Widget)
+		}
+	}
+	appendItems(items)
+
+	var (
+		loadMoreButton *gtk.Button
+		loading        bool
+	)
+	loadNext := func() {
+		if loading || !more {
+			return
+		}
+		loading = true
+		loadMoreButton.SetSensitive(false)
+		go func() {
+			page, hasMore := feed.next(ctx)
+			schwifty.OnMainThreadOncePure(func() {
+				loading = false
+				loadMoreButton.SetSensitive(true)
+				appendItems(page)
+				more = hasMore
+				loadMoreButton.SetVisible(more)
+			})
+		}()
+	}
+
+	loadMore := Button().
+		Label(gettext.Get("Load More")).
+		WithCSSClass("pill").
+		HAlign(gtk.AlignCenterValue).
+		Visible(more).
+		ConnectConstruct(func(b *gtk.Button) {
+			loadMoreButton = b
+		}).
+		ConnectClicked(func(b gtk.Button) {
+			loadNext()
+		})
+
+	body := VStack(Widget(&days.Widget), loadMore).Spacing(25).VMargin(20).HMargin(40)
+	return Clamp().MaximumSize(900).Child(body), loadNext
+}
+
+// historyRow builds a row for a history entry that links to the watched item.
+func historyRow(item historyItem, viewedAt time.Time) *adw.ActionRow {
+	e := item.entry
+
+	title := e.Title
+	if e.Type == "episode" && e.GrandparentTitle != "" {
+		title = fmt.Sprintf("%s – S%d E%d · %s", e.GrandparentTitle, e.ParentIndex, e.Index, e.Title)
+	}
+
+	subtitle := viewedAt.Format("15:04")
+	if item.account != "" {
+		subtitle = item.account + " · " + subtitle
+	}
+	subtitle += " · " + item.serverName
+
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(title)
+	row.SetSubtitle(subtitle)
+
+	var actionName string
+	switch e.Type {
+	case "movie":
+		actionName = "win.route.movie"
+	case "episode":
+		actionName = "win.route.episode"
+	}
+
+	// Entries for deleted items have no rating key and cannot be opened.
+	if actionName != "" && e.RatingKey != "" {
+		row.SetActivatable(true)
+		row.SetActionName(actionName)
+		row.SetActionTargetValue(glib.NewVariantString(item.serverID + "/" + e.RatingKey))
+		row.AddSuffix(&gtk.NewImageFromIconName("go-next-symbolic").Widget)
+	}
+
+	return row
+}
+
+// historyDayLabel returns the group heading for the day t falls on.
+func historyDayLabel(t time.Time) string {
+	now := time.Now()
+	y, m, d := t.Date()
+	ny, nm, nd := now.Date()
+	if y == ny && m == nm && d == nd {
+		return gettext.Get("Today")
+	}
+	yesterday := now.AddDate(0, 0, -1)
+	yy, ym, yd := yesterday.Date()
+	if y == yy && m == ym && d == yd {
+		return gettext.Get("Yesterday")
+	}
+	return t.Format("Monday, January 2, 2006")
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"log/slog"
 
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
 	"codeberg.org/puregotk/puregotk/v4/gtk"
 	"github.com/0skillallluck/scanline/app/appctx"
 	"github.com/0skillallluck/scanline/app/components/cards"
 	"github.com/0skillallluck/scanline/app/components/lists"
+	"github.com/0skillallluck/scanline/app/preference"
 	"github.com/0skillallluck/scanline/app/router"
 	"github.com/0skillallluck/scanline/app/sources"
 	"github.com/0skillallluck/scanline/internal/gettext"
@@ -21,6 +23,62 @@ func home(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
 
 	body := VStack().Spacing(25).VMargin(20)
 
+	// With several servers, hubs of the same kind are merged into one row
+	if preference.General().UnifyLibraries() && len(mgr.EnabledSources()) > 1 {
+		coverURL := func(serverID, thumb string) string {
+			if src := mgr.SourceForServer(serverID); src != nil {
+				return src.PhotoTranscodeURL(thumb, 240, 360)
+			}
+			return ""
+		}
+
+		hubList := mgr.MergedHomeHubs(ctx, 24)
+		for i := range hubList {
+			hub := &hubList[i]
+
+			// Skip On Deck as it largely duplicates Continue Watching
+			if hub.Kind == sources.HubOnDeck {
+				continue
+			}
+
+			list := lists.NewHorizontalList(hub.Title)
+			hasItems := false
+
+			if hub.Kind == sources.HubContinueWatching {
+				for j := range hub.Items {
+					best := hub.Items[j].Best()
+					src := mgr.SourceForServer(best.ServerID)
+					if src == nil {
+						continue
+					}
+					if card, ok := previewCard(&best.Item, src, best.ServerID); ok {
+						list.Append(card)
+						hasItems = true
+					}
+				}
+			} else {
+				hasItems = lists.RenderMergedHub(list, hub, coverURL)
+			}
+
+			// The full hub can only be listed when it comes from one server
+			if hasItems && len(hub.Sources) == 1 && hub.Sources[0].More && hub.Sources[0].Key != "" {
+				hubSrc := hub.Sources[0]
+				list.SetViewAllRoute(hubPath(hubSrc.ServerID, &sources.Hub{Key: hubSrc.Key, Title: hub.Title}))
+			}
+
+			if hasItems {
+				body = body.Append(list.SetPageMargin(40))
+			}
+		}
+
+		return &router.Response{
+			PageTitle: gettext.Get("Home"),
+			View: ScrolledWindow().
+				Child(body).
+				Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+		}
+	}
+
 	for _, src := range mgr.EnabledSources() {
 		hubList, err := src.HomeHubs(ctx, 24)
 		if err != nil {
@@ -37,7 +95,7 @@ func home(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
 			hub := &hubList[i]
 
 			// Skip On Deck as it largely duplicates Continue Watching
-			if hub.HubIdentifier == "hub.ondeck" {
+			if hub.Kind == sources.HubOnDeck {
 				continue
 			}
 
@@ -45,19 +103,11 @@ func home(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
 			hasItems := false
 
 			// Continue Watching hub uses preview cards
-			if hub.HubIdentifier == "home.continue" {
-				for j := range hub.Metadata {
-					meta := &hub.Metadata[j]
-					artURL := sources.ArtURL(meta)
-					if artURL != "" {
-						switch meta.Type {
-						case "movie":
-							list.Append(cards.NewMoviePreviewCard(meta, src.PhotoTranscodeURL(artURL, 480, 270), serverID))
-							hasItems = true
-						case "episode":
-							list.Append(cards.NewEpisodePreviewCard(meta, src.PhotoTranscodeURL(artURL, 480, 270), serverID))
-							hasItems = true
-						}
+			if hub.Kind == sources.HubContinueWatching {
+				for j := range hub.Items {
+					if card, ok := previewCard(&hub.Items[j], src, serverID); ok {
+						list.Append(card)
+						hasItems = true
 					}
 				}
 			} else {
@@ -65,6 +115,11 @@ func home(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
 				hasItems = lists.RenderHub(list, hub, coverURL, serverID)
 			}
 
+			// Home hubs are capped at the requested count; link to the full hub
+			if hasItems && hub.More && hub.Key != "" {
+				list.SetViewAllRoute(hubPath(serverID, hub))
+			}
+
 			if hasItems {
 				body = body.Append(list.SetPageMargin(40))
 			}
@@ -78,3 +133,20 @@ func home(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
 			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
 	}
 }
+
+// previewCard creates the Continue Watching card for a movie or episode.
+// Items without art are skipped.
+func previewCard(meta *sources.Item, src sources.Source, serverID string) (schwifty.BaseWidgetable, bool) {
+	artURL := sources.ArtURL(meta)
+	if artURL == "" {
+		return nil, false
+	}
+	switch meta.Type {
+	case "movie":
+		return cards.NewMoviePreviewCard(meta, src.PhotoTranscodeURL(artURL, 480, 270), serverID), true
+	case "episode":
+		return cards.NewEpisodePreviewCard(meta, src.PhotoTranscodeURL(artURL, 480, 270), serverID), true
+	default:
+		return nil, false
+	}
+}
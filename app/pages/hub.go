@@ -0,0 +1,143 @@
+package pages
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"sync"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/lists"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+var HubRoute = router.NewRoute("hub/:server/:key", Hub)
+
+// hubPageSize is the number of items fetched per page.
+const hubPageSize = 60
+
+// hubTitles remembers hub titles by hub key, since the key alone
+// does not carry a display title.
+var hubTitles sync.Map
+
+// hubPath returns the "View All" route path for a hub.
+func hubPath(serverID string, hub *sources.Hub) string {
+	hubTitles.Store(hub.Key, hub.Title)
+	return HubRoute.Path(serverID, url.PathEscape(hub.Key))
+}
+
+func Hub(ctx context.Context, appCtx *appctx.AppContext, serverID, escapedKey string) *router.Response {
+	src := appCtx.Manager.SourceForServer(serverID)
+	if src == nil {
+		return router.FromError(gettext.Get("View All"), errSourceNotFound(serverID))
+	}
+
+	hubKey, err := url.PathUnescape(escapedKey)
+	if err != nil {
+		return router.FromError(gettext.Get("View All"), err)
+	}
+
+	title := gettext.Get("View All")
+	if t, ok := hubTitles.Load(hubKey); ok && t.(string) != "" {
+		title = t.(string)
+	}
+
+	// offset is the server's offset of the next page, which counts the
+	// items of disabled libraries that are not shown.
+	items, offset, total, err := sources.HubPage(ctx, src, hubKey, 0, hubPageSize)
+	if err != nil {
+		return router.FromError(title, err)
+	}
+
+	coverURL := func(thumb string) string {
+		return src.PhotoTranscodeURL(thumb, 240, 360)
+	}
+
+	var grid *adw.WrapBox
+	gridBuilder := WrapBox().
+		ConnectConstruct(func(w *adw.WrapBox) {
+			w.SetChildSpacing(20)
+			w.SetLineSpacing(20)
+			w.SetLineHomogeneous(true)
+			w.SetJustify(adw.JustifyFillValue)
+			grid = w
+		})
+	for i := range items {
+		meta := &items[i]
+		if card, ok := lists.MetadataCard(meta, coverURL, title, serverID); ok {
+			gridBuilder = gridBuilder.Append(card)
+		}
+	}
+
+	var (
+		loadMoreButton *gtk.Button
+		loading        bool
+	)
+
+	// loadNext fetches the next page from the server and appends it to the
+	// grid. It is triggered by the button and by scrolling to the bottom.
+	loadNext := func() {
+		if loading || offset >= total {
+			return
+		}
+		loading = true
+		loadMoreButton.SetSensitive(false)
+		go func() {
+			page, next, newTotal, err := sources.HubPage(ctx, src, hubKey, offset, hubPageSize)
+			schwifty.OnMainThreadOncePure(func() {
+				loading = false
+				loadMoreButton.SetSensitive(true)
+				if err != nil {
+					slog.Error("failed to load hub items", "hub", hubKey, "error", err)
+					return
+				}
+				for i := range page {
+					meta := &page[i]
+					if card, ok := lists.MetadataCard(meta, coverURL, title, serverID); ok {
+						grid.Append(card.ToGTK())
+					}
+				}
+				total = newTotal
+				if next == offset {
+					total = offset
+				}
+				offset = next
+				loadMoreButton.SetVisible(offset < total)
+			})
+		}()
+	}
+
+	loadMore := Button().
+		Label(gettext.Get("Load More")).
+		WithCSSClass("pill").
+		HAlign(gtk.AlignCenterValue).
+		Visible(offset < total).
+		ConnectConstruct(func(b *gtk.Button) {
+			loadMoreButton = b
+		}).
+		ConnectClicked(func(b gtk.Button) {
+			loadNext()
+		})
+
+	body := VStack(gridBuilder, loadMore).Spacing(20).VMargin(20).HMargin(20)
+
+	return &router.Response{
+		PageTitle: title,
+		View: ScrolledWindow().
+			Child(Clamp().MaximumSize(1200).Child(body)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue).
+			ConnectConstruct(func(sw *gtk.ScrolledWindow) {
+				sw.ConnectEdgeReached(new(func(_ gtk.ScrolledWindow, pos gtk.PositionType) {
+					if pos == gtk.PosBottomValue {
+						loadNext()
+					}
+				}))
+			}),
+	}
+}
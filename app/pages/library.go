@@ -2,6 +2,7 @@ package pages
 
 import (
 	"context"
+	"log/slog"
 
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
 	"codeberg.org/puregotk/puregotk/v4/adw"
@@ -27,7 +28,7 @@ func Library(ctx context.Context, appCtx *appctx.AppContext, serverID, sectionID
 		return router.FromError(gettext.Get("Library"), err)
 	}
 
-	content, _, err := src.LibraryContent(ctx, sectionID, &sources.ContentOptions{Sort: "titleSort"})
+	content, _, err := src.LibraryContent(ctx, sectionID, &sources.ContentOptions{Sort: sources.SortTitle})
 	if err != nil {
 		return router.FromError(section.Title, err)
 	}
@@ -35,6 +36,11 @@ func Library(ctx context.Context, appCtx *appctx.AppContext, serverID, sectionID
 	coverURL := func(thumb string) string {
 		return src.PhotoTranscodeURL(thumb, 240, 360)
 	}
+	if section.Type == "photo" {
+		coverURL = func(thumb string) string {
+			return src.PhotoTranscodeURL(thumb, 360, 360)
+		}
+	}
 
 	body := WrapBox().
 		ConnectConstruct(func(w *adw.WrapBox) {
@@ -51,10 +57,60 @@ func Library(ctx context.Context, appCtx *appctx.AppContext, serverID, sectionID
 		}
 	}
 
+	stack := adw.NewViewStack()
+	stack.AddTitledWithIcon(
+		ScrolledWindow().
+			Child(body.VMargin(20).HMargin(20)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue).
+			ToGTK(),
+		"library", gettext.Get("Library"), "view-grid-symbolic",
+	)
+
+	// Recommended tab: section hubs such as recently added and top unwatched
+	sectionHubs, err := src.SectionHubs(ctx, sectionID)
+	if err != nil {
+		slog.Warn("failed to fetch section hubs", "section", sectionID, "error", err)
+	}
+	recommended := VStack().Spacing(25).VMargin(20)
+	hasHubs := false
+	for i := range sectionHubs {
+		hub := &sectionHubs[i]
+		list := lists.NewHorizontalList(hub.Title)
+		if !lists.RenderHub(list, hub, coverURL, serverID) {
+			continue
+		}
+		if hub.More && hub.Key != "" {
+			list.SetViewAllRoute(hubPath(serverID, hub))
+		}
+		recommended = recommended.Append(list.SetPageMargin(40))
+		hasHubs = true
+	}
+	if hasHubs {
+		stack.AddTitledWithIcon(
+			ScrolledWindow().
+				Child(recommended).
+				Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue).
+				ToGTK(),
+			"recommended", gettext.Get("Recommended"), "starred-symbolic",
+		)
+	}
+
+	stack.SetVexpand(true)
+
+	// The switcher lives in the page rather than the header bar so the
+	// library navigation buttons stay visible.
+	switcher := adw.NewViewSwitcher()
+	switcher.SetStack(stack)
+	switcher.SetPolicy(adw.ViewSwitcherPolicyWideValue)
+	switcher.SetHalign(gtk.AlignCenterValue)
+	switcher.SetMarginTop(10)
+	switcher.SetVisible(hasHubs)
+
 	return &router.Response{
 		PageTitle: section.Title,
-		View: ScrolledWindow().
-			Child(body.VMargin(20).HMargin(20)).
-			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+		View: VStack(
+			Widget(&switcher.Widget),
+			Widget(&stack.Widget),
+		),
 	}
 }
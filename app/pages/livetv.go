@@ -0,0 +1,718 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/dergs/tonearm/pkg/schwifty/state"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"codeberg.org/puregotk/puregotk/v4/pango"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/player"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/utils/imageutils"
+	"github.com/0skillallluck/scanline/utils/notifications"
+	"github.com/google/uuid"
+)
+
+var (
+	LiveTVRoute = router.NewRoute("livetv", liveTV)
+
+	// LiveTVDVRRoute is registered in init, as its page links to itself
+	// through the DVR switcher.
+	LiveTVDVRRoute *router.Route
+)
+
+func init() {
+	LiveTVDVRRoute = router.NewRoute("livetv/:server/:dvr", liveTVDVR)
+}
+
+const (
+	// guideSlot is the spacing of the guide's time ruler.
+	guideSlot = 30 * time.Minute
+
+	// guideSpan is how much of the schedule the guide shows at once.
+	guideSpan = 4 * time.Hour
+
+	// guidePixelsPerMinute sets the guide's horizontal scale.
+	guidePixelsPerMinute = 6
+
+	// guideChannelWidth is the width of the channel column in pixels.
+	guideChannelWidth = 160
+)
+
+// sourceDVR is a DVR together with the source it belongs to.
+type sourceDVR struct {
+	src  sources.Source
+	live sources.LiveTVSource
+	dvr  sources.DVR
+}
+
+func liveTV(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
+	dvrs := fetchDVRs(ctx, appCtx.Manager.EnabledSources())
+	if len(dvrs) == 0 {
+		return &router.Response{
+			PageTitle: gettext.Get("Live TV"),
+			View: StatusPage().
+				IconName("tv-symbolic").
+				Title(gettext.Get("No DVR Available")).
+				Description(gettext.Get("Set up a tuner and DVR in Plex to watch and record live TV.")),
+		}
+	}
+	return liveTVView(ctx, appCtx, dvrs, 0)
+}
+
+func liveTVDVR(ctx context.Context, appCtx *appctx.AppContext, serverID, dvrKey string) *router.Response {
+	dvrs := fetchDVRs(ctx, appCtx.Manager.EnabledSources())
+	for i, d := range dvrs {
+		if d.src.ID() == serverID && d.dvr.Key == dvrKey {
+			return liveTVView(ctx, appCtx, dvrs, i)
+		}
+	}
+	return router.FromError(gettext.Get("Live TV"), fmt.Errorf("DVR %q not found on server %q", dvrKey, serverID))
+}
+
+func fetchDVRs(ctx context.Context, srcs []sources.Source) []sourceDVR {
+	var result []sourceDVR
+	for _, src := range srcs {
+		live, ok := sources.AsLiveTV(src)
+		if !ok {
+			continue
+		}
+		dvrs, err := live.DVRs(ctx)
+		if err != nil {
+			slog.Warn("failed to fetch DVRs", "source", src.Name(), "error", err)
+			continue
+		}
+		for _, dvr := range dvrs {
+			result = append(result, sourceDVR{src: src, live: live, dvr: dvr})
+		}
+	}
+	return result
+}
+
+// liveTVView shows the guide and recordings of dvrs[current], with a
+// switcher when several DVRs are available.
+func liveTVView(ctx context.Context, appCtx *appctx.AppContext, dvrs []sourceDVR, current int) *router.Response {
+	src, live, dvr := dvrs[current].src, dvrs[current].live, &dvrs[current].dvr
+
+	channels, err := live.Channels(ctx, dvr)
+	if err != nil {
+		return router.FromError(gettext.Get("Live TV"), err)
+	}
+
+	guide := newLiveGuide(ctx, appCtx, src, live, dvr, channels)
+	recordingsState := state.NewStateful[any](Label(""))
+	var refreshRecordings func()
+	refreshRecordings = func() {
+		go func() {
+			subs, err := live.Subscriptions(ctx)
+			if err != nil {
+				slog.Error("failed to fetch recordings", "source", src.Name(), "error", err)
+			}
+			recordingsState.SetValue(recordingsView(ctx, src, live, subs, err, refreshRecordings))
+		}()
+	}
+	guide.onScheduled = refreshRecordings
+	refreshRecordings()
+
+	stack := adw.NewViewStack()
+	stack.AddTitledWithIcon(guide.widget(), "guide", gettext.Get("Guide"), "view-grid-symbolic")
+	stack.AddTitledWithIcon(
+		ScrolledWindow().
+			BindChild(recordingsState).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue).
+			ToGTK(),
+		"recordings", gettext.Get("Recordings"), "media-record-symbolic",
+	)
+	stack.SetVexpand(true)
+
+	switcher := adw.NewViewSwitcher()
+	switcher.SetStack(stack)
+	switcher.SetPolicy(adw.ViewSwitcherPolicyWideValue)
+	switcher.SetHalign(gtk.AlignCenterValue)
+
+	header := HStack(Widget(&switcher.Widget)).HAlign(gtk.AlignCenterValue).Spacing(12).VMargin(10)
+	if len(dvrs) > 1 {
+		header = header.Append(dvrSwitcher(dvrs, current))
+	}
+
+	title := gettext.Get("Live TV")
+	if len(dvrs) > 1 {
+		title += " – " + src.Name()
+	}
+	return &router.Response{
+		PageTitle: title,
+		View: VStack(
+			header,
+			Widget(&stack.Widget),
+		),
+	}
+}
+
+// dvrSwitcher offers the other DVRs when several servers have one.
+func dvrSwitcher(dvrs []sourceDVR, current int) any {
+	names := make([]string, len(dvrs))
+	for i, d := range dvrs {
+		names[i] = d.src.Name()
+		if d.dvr.LineupTitle != "" {
+			names[i] += " · " + d.dvr.LineupTitle
+		}
+	}
+	dropdown := gtk.NewDropDownFromStrings(names)
+	dropdown.SetSelected(uint32(current))
+	dropdown.SetValign(gtk.AlignCenterValue)
+	dropdown.ConnectSignal("notify::selected", new(func() {
+		selected := int(dropdown.GetSelected())
+		if selected != current && selected < len(dvrs) {
+			d := dvrs[selected]
+			router.Navigate(LiveTVDVRRoute.Path(d.src.ID(), d.dvr.Key))
+		}
+	}))
+	return Widget(&dropdown.Widget)
+}
+
+// guideEntry is one airing placed in the guide.
+type guideEntry struct {
+	program *sources.Program
+	airing  sources.Airing
+}
+
+// liveGuide is a channel-by-time grid of what airs on a DVR's channels.
+type liveGuide struct {
+	ctx      context.Context
+	appCtx   *appctx.AppContext
+	src      sources.Source
+	live     sources.LiveTVSource
+	dvr      *sources.DVR
+	channels []sources.Channel
+
+	start      time.Time
+	rangeLabel *gtk.Label
+	gridState  *state.State[any]
+	loadGen    int
+
+	// onScheduled is called after a recording was scheduled.
+	onScheduled func()
+}
+
+func newLiveGuide(ctx context.Context, appCtx *appctx.AppContext, src sources.Source, live sources.LiveTVSource, dvr *sources.DVR, channels []sources.Channel) *liveGuide {
+	return &liveGuide{
+		ctx:        ctx,
+		appCtx:     appCtx,
+		src:        src,
+		live:       live,
+		dvr:        dvr,
+		channels:   channels,
+		start:      time.Now().Truncate(guideSlot),
+		rangeLabel: gtk.NewLabel(""),
+		gridState:  state.NewStateful[any](Spinner().SizeRequest(32, 32).HAlign(gtk.AlignCenterValue).VAlign(gtk.AlignCenterValue)),
+	}
+}
+
+func (g *liveGuide) widget() *gtk.Widget {
+	g.rangeLabel.AddCssClass("heading")
+
+	earlier := gtk.NewButtonFromIconName("go-previous-symbolic")
+	earlier.SetTooltipText(gettext.Get("Earlier"))
+	earlier.AddCssClass("flat")
+	earlier.ConnectClicked(new(func(gtk.Button) {
+		g.show(g.start.Add(-guideSpan / 2))
+	}))
+
+	later := gtk.NewButtonFromIconName("go-next-symbolic")
+	later.SetTooltipText(gettext.Get("Later"))
+	later.AddCssClass("flat")
+	later.ConnectClicked(new(func(gtk.Button) {
+		g.show(g.start.Add(guideSpan / 2))
+	}))
+
+	now := gtk.NewButtonWithLabel(gettext.Get("Now"))
+	now.AddCssClass("flat")
+	now.ConnectClicked(new(func(gtk.Button) {
+		g.show(time.Now().Truncate(guideSlot))
+	}))
+
+	g.show(g.start)
+
+	return VStack(
+		HStack(
+			Widget(&earlier.Widget),
+			Widget(&g.rangeLabel.Widget),
+			Widget(&later.Widget),
+			Widget(&now.Widget),
+		).Spacing(6).HMargin(20),
+		ScrolledWindow().
+			BindChild(g.gridState).
+			Policy(gtk.PolicyAutomaticValue, gtk.PolicyAutomaticValue).
+			VExpand(true),
+	).Spacing(6).ToGTK()
+}
+
+// show loads and displays the guide window starting at start.
+func (g *liveGuide) show(start time.Time) {
+	g.start = start
+	end := start.Add(guideSpan)
+	g.rangeLabel.SetText(start.Format("Mon Jan 2, 15:04") + " – " + end.Format("15:04"))
+
+	g.loadGen++
+	gen := g.loadGen
+	go func() {
+		programs, err := g.live.Guide(g.ctx, g.dvr, start, end)
+		schwifty.OnMainThreadOncePure(func() {
+			if gen != g.loadGen {
+				return
+			}
+			if err != nil {
+				slog.Error("failed to load guide", "source", g.src.Name(), "error", err)
+				g.gridState.SetValue(StatusPage().
+					IconName("dialog-error-symbolic").
+					Title(gettext.Get("Could not load the guide")).
+					Description(err.Error()))
+				return
+			}
+			g.gridState.SetValue(Widget(g.grid(programs, start, end)))
+		})
+	}()
+}
+
+// grid lays out one row per channel with airings sized by duration.
+func (g *liveGuide) grid(programs []sources.Program, start, end time.Time) *gtk.Widget {
+	byChannel := make(map[string][]guideEntry)
+	for i := range programs {
+		for _, airing := range programs[i].Media {
+			byChannel[airing.ChannelIdentifier] = append(byChannel[airing.ChannelIdentifier], guideEntry{program: &programs[i], airing: airing})
+		}
+	}
+
+	rows := gtk.NewBox(gtk.OrientationVerticalValue, 4)
+	rows.SetMarginStart(20)
+	rows.SetMarginEnd(20)
+	rows.SetMarginBottom(20)
+	rows.Append(g.ruler(start, end))
+
+	for _, channel := range g.channels {
+		entries := byChannel[channel.Identifier]
+		slices.SortFunc(entries, func(a, b guideEntry) int {
+			return int(a.airing.BeginsAt - b.airing.BeginsAt)
+		})
+		rows.Append(g.channelRow(channel, entries, start, end))
+	}
+	return &rows.Widget
+}
+
+// ruler is the time header above the channel rows.
+func (g *liveGuide) ruler(start, end time.Time) *gtk.Widget {
+	row := gtk.NewBox(gtk.OrientationHorizontalValue, 0)
+	spacer := gtk.NewBox(gtk.OrientationHorizontalValue, 0)
+	spacer.SetSizeRequest(guideChannelWidth, -1)
+	row.Append(&spacer.Widget)
+	for t := start; t.Before(end); t = t.Add(guideSlot) {
+		label := gtk.NewLabel(t.Format("15:04"))
+		label.SetXalign(0)
+		label.AddCssClass("dim-label")
+		label.AddCssClass("caption")
+		label.SetSizeRequest(int32(guideSlot.Minutes()*guidePixelsPerMinute), -1)
+		row.Append(&label.Widget)
+	}
+	return &row.Widget
+}
+
+func (g *liveGuide) channelRow(channel sources.Channel, entries []guideEntry, start, end time.Time) *gtk.Widget {
+	row := gtk.NewBox(gtk.OrientationHorizontalValue, 0)
+
+	logo := gtk.NewPicture()
+	logo.SetSizeRequest(32, 32)
+	logo.SetContentFit(gtk.ContentFitContainValue)
+	if channel.Thumb != "" {
+		imageutils.LoadIntoPictureScaled(g.src.PhotoTranscodeURL(channel.Thumb, 64, 64), 32, 32, logo)
+	}
+	name := gtk.NewLabel(channel.Identifier + "  " + channelName(channel))
+	name.SetXalign(0)
+	name.SetEllipsize(pango.EllipsizeEndValue)
+	name.SetHexpand(true)
+	header := gtk.NewBox(gtk.OrientationHorizontalValue, 8)
+	header.SetSizeRequest(guideChannelWidth, 48)
+	header.Append(&logo.Widget)
+	header.Append(&name.Widget)
+	row.Append(&header.Widget)
+
+	// Place airings along the time axis, filling gaps with empty space.
+	cursor := start
+	for _, entry := range entries {
+		begins := time.Unix(entry.airing.BeginsAt, 0)
+		ends := time.Unix(entry.airing.EndsAt, 0)
+		if !ends.After(cursor) || !begins.Before(end) {
+			continue
+		}
+		begins = maxTime(begins, cursor)
+		ends = minTime(ends, end)
+		if gap := begins.Sub(cursor); gap > 0 {
+			filler := gtk.NewBox(gtk.OrientationHorizontalValue, 0)
+			filler.SetSizeRequest(guideWidth(gap), -1)
+			row.Append(&filler.Widget)
+		}
+		row.Append(g.programButton(channel, entry, guideWidth(ends.Sub(begins))))
+		cursor = ends
+	}
+	return &row.Widget
+}
+
+func (g *liveGuide) programButton(channel sources.Channel, entry guideEntry, width int32) *gtk.Widget {
+	program := entry.program
+	now := time.Now().Unix()
+	onNow := entry.airing.BeginsAt <= now && now < entry.airing.EndsAt
+
+	title := gtk.NewLabel(program.DisplayTitle())
+	title.SetXalign(0)
+	title.SetEllipsize(pango.EllipsizeEndValue)
+	subtitle := gtk.NewLabel(airingTime(entry.airing))
+	subtitle.SetXalign(0)
+	subtitle.SetEllipsize(pango.EllipsizeEndValue)
+	subtitle.AddCssClass("caption")
+	subtitle.AddCssClass("dim-label")
+	content := gtk.NewBox(gtk.OrientationVerticalValue, 2)
+	content.Append(&title.Widget)
+	content.Append(&subtitle.Widget)
+
+	button := gtk.NewMenuButton()
+	button.SetChild(&content.Widget)
+	button.SetAlwaysShowArrow(false)
+	button.SetSizeRequest(max(width-4, 8), 48)
+	button.SetMarginEnd(4)
+	button.SetTooltipText(program.DisplayTitle())
+	if onNow {
+		button.AddCssClass("suggested-action")
+	} else if entry.airing.EndsAt <= now {
+		button.AddCssClass("dim-label")
+	}
+	button.SetPopover(g.programPopover(channel, entry, onNow))
+	return &button.Widget
+}
+
+// programPopover shows an airing's details with actions to watch the
+// channel or record the program.
+func (g *liveGuide) programPopover(channel sources.Channel, entry guideEntry, onNow bool) *gtk.Popover {
+	program := entry.program
+	popover := gtk.NewPopover()
+
+	details := []string{airingTime(entry.airing), channel.Identifier + " " + channelName(channel)}
+	if program.Type == "episode" && program.ParentIndex > 0 {
+		details = append(details, fmt.Sprintf("S%d E%d · %s", program.ParentIndex, program.Index, program.Title))
+	}
+	if entry.airing.Premiere {
+		details = append(details, gettext.Get("New"))
+	}
+
+	var actions []any
+	if onNow {
+		actions = append(actions, Button().
+			Label(gettext.Get("Watch")).
+			WithCSSClass("suggested-action").
+			ConnectClicked(func(b gtk.Button) {
+				popover.Popdown()
+				g.watch(channel)
+			}))
+	}
+	if time.Now().Unix() < entry.airing.EndsAt {
+		switch program.Type {
+		case "episode":
+			actions = append(actions,
+				g.recordButton(popover, entry, sources.SubscriptionEpisode, gettext.Get("Record")),
+				g.recordButton(popover, entry, sources.SubscriptionSeries, gettext.Get("Record Series")),
+			)
+		case "movie":
+			actions = append(actions, g.recordButton(popover, entry, sources.SubscriptionMovie, gettext.Get("Record")))
+		}
+	}
+
+	summary := Label(program.Summary).
+		Wrap(true).
+		HAlign(gtk.AlignStartValue).
+		Visible(program.Summary != "")
+
+	popover.SetChild(VStack(
+		Label(program.DisplayTitle()).WithCSSClass("title-4").HAlign(gtk.AlignStartValue),
+		Label(strings.Join(details, " · ")).WithCSSClass("dim-label").HAlign(gtk.AlignStartValue),
+		summary,
+		HStack(actions...).Spacing(6).HAlign(gtk.AlignEndValue),
+	).Spacing(8).VMargin(6).HMargin(6).SizeRequest(320, -1).ToGTK())
+	return popover
+}
+
+func (g *liveGuide) recordButton(popover *gtk.Popover, entry guideEntry, kind sources.SubscriptionType, label string) any {
+	return Button().
+		Label(label).
+		ConnectClicked(func(b gtk.Button) {
+			popover.Popdown()
+			// Pin single airings to the one that was picked.
+			program := *entry.program
+			program.Media = []sources.Airing{entry.airing}
+			go g.record(&program, kind)
+		})
+}
+
+// record schedules a recording into the first library matching the
+// program type.
+func (g *liveGuide) record(program *sources.Program, kind sources.SubscriptionType) {
+	sectionType := "show"
+	if program.Type == "movie" {
+		sectionType = "movie"
+	}
+	sections, err := g.src.LibrarySections(g.ctx)
+	if err != nil {
+		slog.Error("failed to fetch library sections", "source", g.src.Name(), "error", err)
+		notifications.OnToast.Notify(gettext.Get("Failed to schedule recording"))
+		return
+	}
+	var sectionID string
+	for _, section := range sections {
+		if section.Type == sectionType {
+			sectionID = section.Key
+			break
+		}
+	}
+	if sectionID == "" {
+		notifications.OnToast.Notify(gettext.Get("No library to record into"))
+		return
+	}
+
+	_, err = g.live.ScheduleRecording(g.ctx, sources.ScheduleOptions{
+		Program:   program,
+		Type:      kind,
+		SectionID: sectionID,
+	})
+	if err != nil {
+		slog.Error("failed to schedule recording", "source", g.src.Name(), "program", program.Title, "error", err)
+		notifications.OnToast.Notify(gettext.Get("Failed to schedule recording"))
+		return
+	}
+	notifications.OnToast.Notify(fmt.Sprintf(gettext.Get("Recording scheduled: %s"), program.DisplayTitle()))
+	if g.onScheduled != nil {
+		g.onScheduled()
+	}
+}
+
+// watch tunes the channel and plays it through a transcode session.
+func (g *liveGuide) watch(channel sources.Channel) {
+	notifications.OnToast.Notify(fmt.Sprintf(gettext.Get("Tuning %s…"), channelName(channel)))
+	go func() {
+		session, err := g.live.TuneChannel(g.ctx, g.dvr.Key, channel.Identifier)
+		if err != nil {
+			slog.Error("failed to tune channel", "source", g.src.Name(), "channel", channel.Identifier, "error", err)
+			notifications.OnToast.Notify(gettext.Get("Failed to tune channel"))
+			return
+		}
+
+		decision, err := g.src.DecidePlayback(g.ctx, sources.PlaybackRequest{
+			Path:              session.Key,
+			SessionID:         uuid.NewString(),
+			Transcode:         true,
+			DirectStreamAudio: true,
+		})
+		if err != nil {
+			slog.Error("live TV decision failed", "source", g.src.Name(), "channel", channel.Identifier, "error", err)
+			notifications.OnToast.Notify(gettext.Get("Failed to tune channel"))
+			return
+		}
+
+		title := channelName(channel)
+		if session.Program != nil {
+			title += " – " + session.Program.DisplayTitle()
+		}
+		schwifty.OnMainThreadOncePure(func() {
+			player.NewPlayer(player.PlayerParams{
+				Ctx:       g.ctx,
+				Title:     title,
+				Window:    g.appCtx.Window,
+				Source:    g.src,
+				StreamURL: decision.URL,
+			})
+		})
+	}()
+}
+
+// recordingsView lists recordings in progress, upcoming recordings and the
+// recording rules, which can be cancelled.
+func recordingsView(ctx context.Context, src sources.Source, live sources.LiveTVSource, subs []sources.Subscription, err error, onChanged func()) any {
+	if err != nil {
+		return StatusPage().
+			IconName("dialog-error-symbolic").
+			Title(gettext.Get("Could not load recordings")).
+			Description(err.Error())
+	}
+	if len(subs) == 0 {
+		return StatusPage().
+			IconName("media-record-symbolic").
+			Title(gettext.Get("No Recordings")).
+			Description(gettext.Get("Schedule recordings from the guide."))
+	}
+
+	var inProgress, upcoming []sources.Recording
+	for _, sub := range subs {
+		for _, rec := range sub.MediaGrabOperation {
+			switch rec.Status {
+			case sources.RecordingInProgress:
+				inProgress = append(inProgress, rec)
+			case sources.RecordingScheduled:
+				upcoming = append(upcoming, rec)
+			}
+		}
+	}
+	byStart := func(a, b sources.Recording) int {
+		return int(recordingBegins(a) - recordingBegins(b))
+	}
+	slices.SortFunc(upcoming, byStart)
+
+	body := VStack().Spacing(25).VMargin(20).HMargin(40)
+	if len(inProgress) > 0 {
+		var rows []any
+		for _, rec := range inProgress {
+			rows = append(rows, Widget(&recordingRow(rec).Widget))
+		}
+		body = body.Append(PreferencesGroup(rows...).Title(gettext.Get("Recording Now")))
+	}
+	if len(upcoming) > 0 {
+		var rows []any
+		for _, rec := range upcoming {
+			rows = append(rows, Widget(&recordingRow(rec).Widget))
+		}
+		body = body.Append(PreferencesGroup(rows...).Title(gettext.Get("Upcoming")))
+	}
+
+	var rules []any
+	for _, sub := range subs {
+		rules = append(rules, Widget(&subscriptionRow(ctx, src, live, sub, onChanged).Widget))
+	}
+	body = body.Append(PreferencesGroup(rules...).Title(gettext.Get("Recording Rules")))
+
+	return Clamp().MaximumSize(900).Child(body.VAlign(gtk.AlignStartValue))
+}
+
+func recordingBegins(rec sources.Recording) int64 {
+	if len(rec.Metadata.Media) == 0 {
+		return 0
+	}
+	return rec.Metadata.Media[0].BeginsAt
+}
+
+func recordingRow(rec sources.Recording) *adw.ActionRow {
+	program := &rec.Metadata
+	title := program.DisplayTitle()
+	if program.Type == "episode" && program.Title != "" && program.Title != title {
+		title += " – " + program.Title
+	}
+
+	var details []string
+	if len(program.Media) > 0 {
+		airing := program.Media[0]
+		details = append(details, time.Unix(airing.BeginsAt, 0).Format("Mon Jan 2, ")+airingTime(airing))
+		if airing.ChannelIdentifier != "" {
+			details = append(details, strings.TrimSpace(airing.ChannelIdentifier+" "+airing.ChannelCallSign))
+		}
+	}
+
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(title)
+	row.SetSubtitle(strings.Join(details, " · "))
+
+	if rec.Status == sources.RecordingInProgress {
+		progress := gtk.NewProgressBar()
+		progress.SetValign(gtk.AlignCenterValue)
+		progress.SetSizeRequest(160, -1)
+		progress.SetFraction(rec.Percent / 100)
+		row.AddSuffix(&progress.Widget)
+	}
+	return row
+}
+
+func subscriptionRow(ctx context.Context, src sources.Source, live sources.LiveTVSource, sub sources.Subscription, onChanged func()) *adw.ActionRow {
+	var kind string
+	switch sub.Type {
+	case sources.SubscriptionSeries:
+		kind = gettext.Get("All episodes")
+	case sources.SubscriptionMovie:
+		kind = gettext.Get("Movie")
+	default:
+		kind = gettext.Get("Single airing")
+	}
+	scheduled := 0
+	for _, rec := range sub.MediaGrabOperation {
+		if rec.Status == sources.RecordingScheduled {
+			scheduled++
+		}
+	}
+	subtitle := kind
+	if scheduled > 0 {
+		subtitle += " · " + gettext.GetN("%d upcoming", "%d upcoming", scheduled, scheduled)
+	}
+
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(sub.Title)
+	row.SetSubtitle(subtitle)
+
+	cancel := gtk.NewButtonFromIconName("user-trash-symbolic")
+	cancel.SetValign(gtk.AlignCenterValue)
+	cancel.SetTooltipText(gettext.Get("Cancel Recording"))
+	cancel.AddCssClass("flat")
+	key := sub.Key
+	cancel.ConnectClicked(new(func(b gtk.Button) {
+		b.SetSensitive(false)
+		go func() {
+			if err := live.CancelSubscription(ctx, key); err != nil {
+				slog.Error("failed to cancel recording", "source", src.Name(), "subscription", key, "error", err)
+				notifications.OnToast.Notify(gettext.Get("Failed to cancel recording"))
+				return
+			}
+			notifications.OnToast.Notify(gettext.Get("Recording cancelled"))
+			if onChanged != nil {
+				onChanged()
+			}
+		}()
+	}))
+	row.AddSuffix(&cancel.Widget)
+	return row
+}
+
+// channelName returns the channel's call sign, falling back to its title.
+func channelName(channel sources.Channel) string {
+	if channel.CallSign != "" {
+		return channel.CallSign
+	}
+	return channel.Title
+}
+
+// airingTime formats an airing as "20:00 – 20:30".
+func airingTime(airing sources.Airing) string {
+	return time.Unix(airing.BeginsAt, 0).Format("15:04") + " – " + time.Unix(airing.EndsAt, 0).Format("15:04")
+}
+
+// guideWidth converts a duration to a width on the guide's time axis.
+func guideWidth(d time.Duration) int32 {
+	return int32(d.Minutes() * guidePixelsPerMinute)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,69 @@
+package pages
+
+import (
+	"context"
+
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/lists"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+var MergedLibraryRoute = router.NewRoute("libraries/:type", MergedLibrary)
+
+// mergedLibraryTitle returns the title of the merged library of a section
+// type.
+func mergedLibraryTitle(sectionType string) string {
+	switch sectionType {
+	case "movie":
+		return gettext.Get("All Movies")
+	case "show":
+		return gettext.Get("All Shows")
+	default:
+		return gettext.Get("Library")
+	}
+}
+
+// MergedLibrary lists the libraries of a section type on all servers, with
+// items available on several servers shown once.
+func MergedLibrary(ctx context.Context, appCtx *appctx.AppContext, sectionType string) *router.Response {
+	mgr := appCtx.Manager
+	title := mergedLibraryTitle(sectionType)
+
+	items, err := mgr.MergedLibrary(ctx, sectionType)
+	if err != nil {
+		return router.FromError(title, err)
+	}
+
+	body := WrapBox().
+		ConnectConstruct(func(w *adw.WrapBox) {
+			w.SetChildSpacing(20)
+			w.SetLineSpacing(20)
+			w.SetLineHomogeneous(true)
+			w.SetJustify(adw.JustifyFillValue)
+		})
+
+	for i := range items {
+		best := items[i].Best()
+		src := mgr.SourceForServer(best.ServerID)
+		if src == nil {
+			continue
+		}
+		coverURL := func(thumb string) string {
+			return src.PhotoTranscodeURL(thumb, 240, 360)
+		}
+		if card, ok := lists.MetadataCard(&best.Item, coverURL, title, best.ServerID); ok {
+			body = body.Append(card)
+		}
+	}
+
+	return &router.Response{
+		PageTitle: title,
+		View: ScrolledWindow().
+			Child(body.VMargin(20).HMargin(20)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"strconv"
 	"strings"
 
 	"codeberg.org/dergs/tonearm/pkg/schwifty"
@@ -30,12 +29,13 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 		return router.FromError(gettext.Get("Movie"), errSourceNotFound(serverID))
 	}
 
-	meta, err := src.GetMetadata(ctx, ratingKey)
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
 	if err != nil {
 		return router.FromError(gettext.Get("Movie"), err)
 	}
 
-	relatedHubs, err := src.RelatedHubs(ctx, ratingKey)
+	relatedHubs, err := src.RelatedHubs(ctx, id)
 	if err != nil {
 		slog.Debug("failed to fetch related hubs", "ratingKey", ratingKey, "error", err)
 	}
@@ -48,7 +48,7 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 		directorSubtitle = gettext.Get("Directed by") + " " + tagNames(meta.Director)
 		if len(meta.Director) == 1 {
 			directorActionName = "win.route.cast"
-			directorActionValue = serverID + "/" + strconv.Itoa(meta.Director[0].ID)
+			directorActionValue = serverID + "/" + meta.Director[0].ID
 		}
 	}
 
@@ -70,8 +70,8 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 		SubtitleActionName:  directorActionName,
 		SubtitleActionValue: directorActionValue,
 		Badges:              []string{fmt.Sprint(meta.Year), widgets.FormatDuration(meta.Duration), meta.ContentRating},
-		Ratings:    meta.Ratings,
-		UserRating: meta.UserRating,
+		Ratings:             meta.Ratings,
+		UserRating:          meta.UserRating,
 		BuildButtonRow: func() schwifty.Box {
 			return HStack().Spacing(10).
 				Append(
@@ -92,7 +92,7 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 									Title:      meta.Title,
 									PartKey:    meta.Media[0].Part[0].Key,
 									Window:     appCtx.Window,
-									RatingKey:  ratingKey,
+									ItemID:     id,
 									Media:      meta.Media,
 									Source:     src,
 									ViewOffset: meta.ViewOffset,
@@ -116,9 +116,9 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 							go func() {
 								var err error
 								if watched {
-									err = src.Unscrobble(ctx, ratingKey)
+									err = src.Unscrobble(ctx, id)
 								} else {
-									err = src.Scrobble(ctx, ratingKey)
+									err = src.Scrobble(ctx, id)
 								}
 								if err != nil {
 									slog.Error("failed to update watch status", "ratingKey", ratingKey, "error", err)
@@ -130,6 +130,7 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 								}
 								schwifty.OnMainThreadOncePure(func() {
 									b.SetSensitive(true)
+									syncMarkedWatchState(appCtx, serverID, id, !watched)
 									if watched {
 										meta.ViewCount = 0
 										b.SetTooltipText(gettext.Get("Mark this movie as watched"))
@@ -150,7 +151,8 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 								})
 							}()
 						}),
-				)
+				).
+				Append(watchlistToggle(ctx, appCtx, serverID, meta.GUID))
 		},
 		Tagline: meta.Tagline,
 		Summary: meta.Summary,
@@ -179,10 +181,10 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 	}
 
 	// Cast section
-	if len(meta.Role) > 0 {
+	if len(meta.Cast) > 0 {
 		castList := lists.NewHorizontalList(gettext.Get("Cast"))
-		for _, role := range meta.Role {
-			castList.Append(cards.NewCastMember(role.Tag, role.Role, src.PhotoTranscodeURL(role.Thumb, 140, 140), serverID, strconv.Itoa(role.ID)))
+		for _, role := range meta.Cast {
+			castList.Append(cards.NewCastMember(role.Name, role.Role, src.PhotoTranscodeURL(role.Thumb, 140, 140), serverID, role.ID))
 		}
 		body = body.Append(castList.SetPageMargin(0))
 	}
@@ -195,6 +197,9 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 		hub := &relatedHubs[i]
 		list := lists.NewHorizontalList(hub.Title)
 		if lists.RenderHub(list, hub, coverURL, serverID) {
+			if hub.More && hub.Key != "" {
+				list.SetViewAllRoute(hubPath(serverID, hub))
+			}
 			body = body.Append(list.SetPageMargin(0))
 		}
 	}
@@ -210,7 +215,7 @@ func Movie(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey s
 func tagNames(tags []sources.Tag) string {
 	names := make([]string, len(tags))
 	for i, t := range tags {
-		names[i] = t.Tag
+		names[i] = t.Name
 	}
 	return strings.Join(names, ", ")
 }
@@ -0,0 +1,570 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"path"
+	"strings"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/dergs/tonearm/pkg/schwifty/state"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gdk"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/cards"
+	"github.com/0skillallluck/scanline/app/components/lists"
+	"github.com/0skillallluck/scanline/app/preference"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/utils/imageutils"
+)
+
+var (
+	PhotoAlbumRoute = router.NewRoute("photos/:server/:ratingKey", PhotoAlbum)
+	PhotoRoute      = router.NewRoute("photo/:server/:ratingKey", Photo)
+	SlideshowRoute  = router.NewRoute("slideshow/:server/:ratingKey", Slideshow)
+)
+
+const (
+	// photoViewerSize is the maximum edge length requested for full-size
+	// photos. The server downscales larger originals.
+	photoViewerSize = 2560
+
+	// photoMaxZoom is the largest zoom factor relative to the loaded image.
+	photoMaxZoom = 8.0
+
+	// photoZoomStep is the factor applied per zoom in/out step.
+	photoZoomStep = 1.25
+)
+
+func PhotoAlbum(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey string) *router.Response {
+	src := appCtx.Manager.SourceForServer(serverID)
+	if src == nil {
+		return router.FromError(gettext.Get("Photos"), errSourceNotFound(serverID))
+	}
+
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
+	if err != nil {
+		return router.FromError(gettext.Get("Photos"), err)
+	}
+
+	children, err := src.GetChildren(ctx, id)
+	if err != nil {
+		return router.FromError(meta.Title, err)
+	}
+
+	coverURL := func(thumb string) string {
+		return src.PhotoTranscodeURL(thumb, 360, 360)
+	}
+
+	grid := WrapBox().
+		ConnectConstruct(func(w *adw.WrapBox) {
+			w.SetChildSpacing(20)
+			w.SetLineSpacing(20)
+			w.SetLineHomogeneous(true)
+			w.SetJustify(adw.JustifyFillValue)
+		})
+	for i := range children {
+		if card, ok := lists.MetadataCard(&children[i], coverURL, meta.Title, serverID); ok {
+			grid = grid.Append(card)
+		}
+	}
+
+	photoCount := len(playablePhotos(children))
+	header := HStack(
+		VStack(
+			Label(meta.Title).
+				WithCSSClass("title-1").
+				HAlign(gtk.AlignStartValue),
+			Label(gettext.GetN("%d Photo", "%d Photos", photoCount, photoCount)).
+				WithCSSClass("dimmed").
+				HAlign(gtk.AlignStartValue),
+		).Spacing(6).HExpand(true),
+		Button().
+			Child(
+				HStack(
+					Image().FromIconName("media-playback-start-symbolic"),
+					Label(gettext.Get("Slideshow")),
+				).Spacing(6),
+			).
+			TooltipText(gettext.Get("Play a slideshow of this album")).
+			WithCSSClass("suggested-action").
+			WithCSSClass("pill").
+			VAlign(gtk.AlignCenterValue).
+			Visible(photoCount > 0).
+			ConnectClicked(func(b gtk.Button) {
+				router.Navigate(SlideshowRoute.Path(serverID, ratingKey))
+			}),
+	).Spacing(12).HMargin(15)
+
+	return &router.Response{
+		PageTitle: meta.Title,
+		View: ScrolledWindow().
+			Child(VStack(header, grid).Spacing(20).VMargin(20).HMargin(20)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+	}
+}
+
+func Photo(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey string) *router.Response {
+	src := appCtx.Manager.SourceForServer(serverID)
+	if src == nil {
+		return router.FromError(gettext.Get("Photo"), errSourceNotFound(serverID))
+	}
+
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
+	if err != nil {
+		return router.FromError(gettext.Get("Photo"), err)
+	}
+
+	// Browse the rest of the album from the viewer.
+	photos := []sources.Item{*meta}
+	start := 0
+	if meta.ParentID != "" {
+		siblings, err := src.GetChildren(ctx, meta.ParentID)
+		if err != nil {
+			slog.Warn("failed to fetch album photos", "ratingKey", meta.ParentID, "error", err)
+		}
+		if album := playablePhotos(siblings); len(album) > 0 {
+			for i := range album {
+				if album[i].ID == id {
+					photos, start = album, i
+					break
+				}
+			}
+		}
+	}
+
+	return newPhotoViewer(appCtx, src, photos, start, false).response(meta.Title)
+}
+
+func Slideshow(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey string) *router.Response {
+	src := appCtx.Manager.SourceForServer(serverID)
+	if src == nil {
+		return router.FromError(gettext.Get("Slideshow"), errSourceNotFound(serverID))
+	}
+
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
+	if err != nil {
+		return router.FromError(gettext.Get("Slideshow"), err)
+	}
+
+	children, err := src.GetChildren(ctx, id)
+	if err != nil {
+		return router.FromError(meta.Title, err)
+	}
+
+	photos := playablePhotos(children)
+	if len(photos) == 0 {
+		return &router.Response{
+			PageTitle: meta.Title,
+			View: StatusPage().
+				IconName("image-x-generic-symbolic").
+				Title(gettext.Get("No Photos")).
+				Description(gettext.Get("This album does not contain any photos.")),
+		}
+	}
+	if preference.General().SlideshowShuffle() {
+		rand.Shuffle(len(photos), func(i, j int) {
+			photos[i], photos[j] = photos[j], photos[i]
+		})
+	}
+
+	return newPhotoViewer(appCtx, src, photos, 0, true).response(meta.Title)
+}
+
+// playablePhotos returns the items that are photos rather than sub-albums.
+func playablePhotos(items []sources.Item) []sources.Item {
+	var photos []sources.Item
+	for _, item := range items {
+		if item.Type == "photo" && len(item.Media) > 0 && len(item.Media[0].Part) > 0 {
+			photos = append(photos, item)
+		}
+	}
+	return photos
+}
+
+// photoViewer shows one photo at a time with zoom, navigation through the
+// album, an EXIF info panel and an optional slideshow.
+type photoViewer struct {
+	appCtx *appctx.AppContext
+	src    sources.Source
+	photos []sources.Item
+	index  int
+
+	picture     *gtk.Picture
+	scroller    *gtk.ScrolledWindow
+	counter     *gtk.Label
+	zoomLabel   *gtk.Label
+	prevButton  *gtk.Button
+	nextButton  *gtk.Button
+	slideButton *gtk.ToggleButton
+	infoButton  *gtk.ToggleButton
+	info        *gtk.Revealer
+	infoState   *state.State[any]
+
+	texture   *gdk.Texture
+	zoom      float64 // 0 means fit to the window
+	loadGen   int     // discards results of superseded loads
+	slideshow uint32  // slideshow timer, 0 when stopped
+}
+
+func newPhotoViewer(appCtx *appctx.AppContext, src sources.Source, photos []sources.Item, start int, slideshow bool) *photoViewer {
+	v := &photoViewer{appCtx: appCtx, src: src, photos: photos, index: start}
+
+	v.picture = gtk.NewPicture()
+	v.picture.SetContentFit(gtk.ContentFitContainValue)
+	v.picture.SetCanShrink(true)
+	v.picture.SetHexpand(true)
+	v.picture.SetVexpand(true)
+
+	v.scroller = gtk.NewScrolledWindow()
+	v.scroller.SetPolicy(gtk.PolicyNeverValue, gtk.PolicyNeverValue)
+	v.scroller.SetHexpand(true)
+	v.scroller.SetVexpand(true)
+	v.scroller.SetChild(&v.picture.Widget)
+
+	// Ctrl+scroll and pinch to zoom
+	scroll := gtk.NewEventControllerScroll(gtk.EventControllerScrollVerticalValue)
+	scroll.ConnectScroll(new(func(c gtk.EventControllerScroll, _, dy float64) bool {
+		if c.GetCurrentEventState()&gdk.ControlMaskValue == 0 {
+			return false
+		}
+		if dy < 0 {
+			v.zoomIn()
+		} else {
+			v.zoomOut()
+		}
+		return true
+	}))
+	v.scroller.AddController(&scroll.EventController)
+
+	var pinchBase float64
+	pinch := gtk.NewGestureZoom()
+	pinch.ConnectBegin(new(func(gtk.Gesture, uintptr) {
+		pinchBase = v.currentScale()
+	}))
+	pinch.ConnectScaleChanged(new(func(_ gtk.GestureZoom, scale float64) {
+		v.setZoom(pinchBase * scale)
+	}))
+	v.scroller.AddController(&pinch.EventController)
+
+	v.counter = gtk.NewLabel("")
+	v.counter.AddCssClass("numeric")
+	v.counter.AddCssClass("dim-label")
+	v.zoomLabel = gtk.NewLabel("")
+	v.zoomLabel.AddCssClass("numeric")
+	v.zoomLabel.SetWidthChars(5)
+
+	v.prevButton = photoToolButton("go-previous-symbolic", gettext.Get("Previous Photo"), func() { v.show(v.index - 1) })
+	v.nextButton = photoToolButton("go-next-symbolic", gettext.Get("Next Photo"), func() { v.show(v.index + 1) })
+
+	v.slideButton = gtk.NewToggleButton()
+	v.slideButton.SetIconName("media-playback-start-symbolic")
+	v.slideButton.SetTooltipText(gettext.Get("Slideshow"))
+	v.slideButton.AddCssClass("flat")
+	v.slideButton.SetVisible(len(photos) > 1)
+	v.slideButton.ConnectToggled(new(func(b gtk.ToggleButton) {
+		if b.GetActive() {
+			v.startSlideshow()
+		} else {
+			v.stopSlideshow()
+		}
+	}))
+
+	v.infoButton = gtk.NewToggleButton()
+	v.infoButton.SetIconName("info-outline-symbolic")
+	v.infoButton.SetTooltipText(gettext.Get("Photo Details"))
+	v.infoButton.AddCssClass("flat")
+
+	v.infoState = state.NewStateful[any](Label(""))
+	infoScroller := ScrolledWindow().
+		BindChild(v.infoState).
+		Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue).
+		ToGTK()
+	infoScroller.SetSizeRequest(320, -1)
+	v.info = gtk.NewRevealer()
+	v.info.SetTransitionType(gtk.RevealerTransitionTypeSlideLeftValue)
+	v.info.SetChild(infoScroller)
+	v.infoButton.ConnectToggled(new(func(b gtk.ToggleButton) {
+		v.info.SetRevealChild(b.GetActive())
+	}))
+
+	if slideshow {
+		// Toggling starts the timer through the handler above.
+		schwifty.OnMainThreadOncePure(func() {
+			v.slideButton.SetActive(true)
+		})
+	}
+	v.show(start)
+	return v
+}
+
+func (v *photoViewer) response(title string) *router.Response {
+	toolbar := HStack(
+		Widget(&v.prevButton.Widget),
+		Widget(&v.counter.Widget),
+		Widget(&v.nextButton.Widget),
+		Spacer(),
+		Widget(&photoToolButton("zoom-out-symbolic", gettext.Get("Zoom Out"), v.zoomOut).Widget),
+		Widget(&v.zoomLabel.Widget),
+		Widget(&photoToolButton("zoom-in-symbolic", gettext.Get("Zoom In"), v.zoomIn).Widget),
+		Widget(&photoToolButton("zoom-fit-best-symbolic", gettext.Get("Fit to Window"), func() { v.setZoom(0) }).Widget),
+		Widget(&v.slideButton.Widget),
+		Widget(&v.infoButton.Widget),
+		Widget(&photoToolButton("view-fullscreen-symbolic", gettext.Get("Fullscreen"), v.toggleFullscreen).Widget),
+	).Spacing(6).HMargin(12).VMargin(6).ToGTK()
+
+	content := gtk.NewBox(gtk.OrientationHorizontalValue, 0)
+	content.SetVexpand(true)
+	content.Append(&v.scroller.Widget)
+	content.Append(&v.info.Widget)
+
+	root := gtk.NewBox(gtk.OrientationVerticalValue, 0)
+	root.Append(toolbar)
+	root.Append(&content.Widget)
+
+	keys := gtk.NewEventControllerKey()
+	keys.SetPropagationPhase(gtk.PhaseCaptureValue)
+	keyPressed := func(_ gtk.EventControllerKey, keyval uint32, _ uint32, _ gdk.ModifierType) bool {
+		switch keyval {
+		case uint32(gdk.KEY_Left):
+			v.show(v.index - 1)
+		case uint32(gdk.KEY_Right):
+			v.show(v.index + 1)
+		case uint32(gdk.KEY_plus), uint32(gdk.KEY_equal), uint32(gdk.KEY_KP_Add):
+			v.zoomIn()
+		case uint32(gdk.KEY_minus), uint32(gdk.KEY_KP_Subtract):
+			v.zoomOut()
+		case uint32(gdk.KEY_0):
+			v.setZoom(0)
+		case uint32(gdk.KEY_space):
+			v.slideButton.SetActive(!v.slideButton.GetActive())
+		case uint32(gdk.KEY_i):
+			v.infoButton.SetActive(!v.infoButton.GetActive())
+		case uint32(gdk.KEY_f), uint32(gdk.KEY_F11):
+			v.toggleFullscreen()
+		case uint32(gdk.KEY_Escape):
+			if !v.appCtx.Window.IsFullscreen() {
+				return false
+			}
+			v.appCtx.Window.Unfullscreen()
+		default:
+			return false
+		}
+		return true
+	}
+	keys.ConnectKeyPressed(&keyPressed)
+	root.AddController(&keys.EventController)
+
+	root.SetFocusable(true)
+	root.ConnectMap(new(func(w gtk.Widget) {
+		w.GrabFocus()
+	}))
+	root.ConnectUnmap(new(func(gtk.Widget) {
+		// Navigating away ends the slideshow and fullscreen mode.
+		v.slideButton.SetActive(false)
+		if v.appCtx.Window.IsFullscreen() {
+			v.appCtx.Window.Unfullscreen()
+		}
+	}))
+
+	return &router.Response{
+		PageTitle: title,
+		View:      Widget(&root.Widget),
+	}
+}
+
+// show displays the photo at index, wrapping around at either end.
+func (v *photoViewer) show(index int) {
+	n := len(v.photos)
+	v.index = (index%n + n) % n
+	photo := &v.photos[v.index]
+
+	v.counter.SetText(fmt.Sprintf("%d / %d", v.index+1, n))
+	v.prevButton.SetSensitive(n > 1)
+	v.nextButton.SetSensitive(n > 1)
+	v.infoState.SetValue(photoInfo(photo))
+
+	v.loadGen++
+	gen := v.loadGen
+	url := v.src.PhotoTranscodeURL(photo.Media[0].Part[0].Key, photoViewerSize, photoViewerSize)
+	go func() {
+		texture, err := imageutils.Load(url)
+		schwifty.OnMainThreadOncePure(func() {
+			if err != nil {
+				if gen == v.loadGen {
+					slog.Error("failed to load photo", "ratingKey", photo.ID, "error", err)
+					v.picture.SetPaintable(nil)
+				}
+				return
+			}
+			if gen != v.loadGen {
+				texture.Unref()
+				return
+			}
+			// The picture holds its own reference to the texture.
+			if v.texture != nil {
+				v.texture.Unref()
+			}
+			v.texture = texture
+			v.picture.SetPaintable(texture)
+			v.setZoom(0)
+		})
+	}()
+}
+
+// currentScale returns the displayed size relative to the loaded image.
+func (v *photoViewer) currentScale() float64 {
+	if v.zoom > 0 {
+		return v.zoom
+	}
+	return v.fitScale()
+}
+
+// fitScale is the zoom factor at which the photo fits the viewport.
+func (v *photoViewer) fitScale() float64 {
+	if v.texture == nil {
+		return 1
+	}
+	w, h := float64(v.scroller.GetWidth()), float64(v.scroller.GetHeight())
+	tw, th := float64(v.texture.GetWidth()), float64(v.texture.GetHeight())
+	if w <= 0 || h <= 0 || tw <= 0 || th <= 0 {
+		return 1
+	}
+	return min(w/tw, h/th)
+}
+
+func (v *photoViewer) zoomIn() {
+	v.setZoom(v.currentScale() * photoZoomStep)
+}
+
+func (v *photoViewer) zoomOut() {
+	v.setZoom(v.currentScale() / photoZoomStep)
+}
+
+// setZoom sets the zoom factor. Zooming to or below the fit scale returns
+// to fit mode, where the photo follows the window size.
+func (v *photoViewer) setZoom(zoom float64) {
+	if v.texture == nil || zoom <= v.fitScale() {
+		v.zoom = 0
+		v.picture.SetSizeRequest(-1, -1)
+		v.scroller.SetPolicy(gtk.PolicyNeverValue, gtk.PolicyNeverValue)
+		v.zoomLabel.SetText(gettext.Get("Fit"))
+		return
+	}
+
+	v.zoom = min(zoom, photoMaxZoom)
+	v.picture.SetSizeRequest(
+		int32(float64(v.texture.GetWidth())*v.zoom),
+		int32(float64(v.texture.GetHeight())*v.zoom),
+	)
+	v.scroller.SetPolicy(gtk.PolicyAutomaticValue, gtk.PolicyAutomaticValue)
+	v.zoomLabel.SetText(fmt.Sprintf("%d%%", int(v.zoom*100)))
+}
+
+func (v *photoViewer) startSlideshow() {
+	if v.slideshow != 0 {
+		return
+	}
+	v.slideButton.SetIconName("media-playback-pause-symbolic")
+	interval := uint32(max(preference.General().SlideshowInterval(), 1)) * 1000
+	cb := glib.SourceFunc(func(uintptr) bool {
+		if !v.scroller.GetMapped() {
+			v.slideshow = 0
+			v.slideButton.SetActive(false)
+			return false
+		}
+		v.show(v.index + 1)
+		return true
+	})
+	v.slideshow = glib.TimeoutAdd(interval, &cb, 0)
+}
+
+func (v *photoViewer) stopSlideshow() {
+	v.slideButton.SetIconName("media-playback-start-symbolic")
+	if v.slideshow != 0 {
+		glib.SourceRemove(v.slideshow)
+		v.slideshow = 0
+	}
+}
+
+func (v *photoViewer) toggleFullscreen() {
+	if v.appCtx.Window.IsFullscreen() {
+		v.appCtx.Window.Unfullscreen()
+	} else {
+		v.appCtx.Window.Fullscreen()
+	}
+}
+
+func photoToolButton(icon, tooltip string, onClick func()) *gtk.Button {
+	button := gtk.NewButtonFromIconName(icon)
+	button.SetTooltipText(tooltip)
+	button.AddCssClass("flat")
+	button.SetValign(gtk.AlignCenterValue)
+	button.ConnectClicked(new(func(gtk.Button) {
+		onClick()
+	}))
+	return button
+}
+
+// photoInfo lists the photo's capture details from its EXIF data.
+func photoInfo(photo *sources.Item) any {
+	var rows []any
+	add := func(title, value string) {
+		if value != "" {
+			rows = append(rows, propertyRow(title, value))
+		}
+	}
+
+	add(gettext.Get("Title"), photo.Title)
+	add(gettext.Get("Date Taken"), cards.FormatPhotoDate(photo.OriginallyAvailableAt))
+
+	media := photo.Media[0]
+	add(gettext.Get("Camera"), strings.TrimSpace(media.Make+" "+media.Model))
+	add(gettext.Get("Lens"), media.Lens)
+	add(gettext.Get("Aperture"), media.Aperture)
+	add(gettext.Get("Exposure"), media.Exposure)
+	if media.ISO > 0 {
+		add(gettext.Get("ISO"), fmt.Sprintf("%d", media.ISO))
+	}
+	if media.Width > 0 && media.Height > 0 {
+		add(gettext.Get("Dimensions"), fmt.Sprintf("%d × %d", media.Width, media.Height))
+	}
+	if len(media.Part) > 0 {
+		part := media.Part[0]
+		if part.Size > 0 {
+			add(gettext.Get("File Size"), formatFileSize(part.Size))
+		}
+		if part.File != "" {
+			add(gettext.Get("File Name"), path.Base(part.File))
+		}
+	}
+
+	return PreferencesGroup(rows...).
+		Title(gettext.Get("Details")).
+		VMargin(12).
+		HMargin(12)
+}
+
+// formatFileSize formats a byte count as "4.2 MB".
+func formatFileSize(size int64) string {
+	const unit = 1000
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exp := float64(size)/unit, 0
+	for value >= unit && exp < 3 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", value, "kMGT"[exp])
+}
@@ -23,7 +23,7 @@ const (
 // SortHubs sorts the Metadata slice within each hub in-place.
 func SortHubs(hubs []sources.Hub, sortOption int) {
 	for i := range hubs {
-		slices.SortStableFunc(hubs[i].Metadata, func(a, b sources.Metadata) int {
+		slices.SortStableFunc(hubs[i].Items, func(a, b sources.Item) int {
 			switch sortOption {
 			case SortTitleDesc:
 				return cmp.Compare(strings.ToLower(titleKey(b)), strings.ToLower(titleKey(a)))
@@ -40,7 +40,7 @@ func SortHubs(hubs []sources.Hub, sortOption int) {
 	}
 }
 
-func titleKey(m sources.Metadata) string {
+func titleKey(m sources.Item) string {
 	if m.TitleSort != "" {
 		return m.TitleSort
 	}
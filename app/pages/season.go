@@ -26,12 +26,13 @@ func Season(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 		return router.FromError(gettext.Get("Season"), errSourceNotFound(serverID))
 	}
 
-	meta, err := src.GetMetadata(ctx, ratingKey)
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
 	if err != nil {
 		return router.FromError(gettext.Get("Season"), err)
 	}
 
-	episodes, err := src.GetChildren(ctx, ratingKey)
+	episodes, err := src.GetChildren(ctx, id)
 	if err != nil {
 		slog.Warn("failed to fetch episodes", "ratingKey", ratingKey, "error", err)
 	}
@@ -73,7 +74,7 @@ func Season(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 								Title:       ep.Title,
 								PartKey:     ep.Media[0].Part[0].Key,
 								Window:      appCtx.Window,
-								RatingKey:   ep.RatingKey,
+								ItemID:      ep.ID,
 								Media:       ep.Media,
 								Source:      src,
 								ViewOffset:  ep.ViewOffset,
@@ -87,7 +88,7 @@ func Season(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 	heroContent := widgets.HeroContent(widgets.HeroContentParams{
 		Title:            meta.ParentTitle,
 		TitleActionName:  "win.route.show",
-		TitleActionValue: serverID + "/" + meta.ParentRatingKey,
+		TitleActionValue: serverID + "/" + string(meta.ParentID),
 		Subtitle:         meta.Title,
 		SubtitleClass:    "title-2 dimmed",
 		Badges:           []string{fmt.Sprint(meta.Year)},
@@ -137,7 +138,7 @@ func Season(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey
 // findNextSeasonEpisode finds the next episode to play within a single season.
 // It returns the first in-progress or unwatched episode.
 // If all episodes are watched, it returns the first episode.
-func findNextSeasonEpisode(episodes []sources.Metadata) *sources.Metadata {
+func findNextSeasonEpisode(episodes []sources.Item) *sources.Item {
 	if len(episodes) == 0 {
 		return nil
 	}
@@ -0,0 +1,260 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/utils/notifications"
+)
+
+var ServerRoute = router.NewRoute("server/:server", ServerDetails)
+
+// editableServerPrefs lists the preferences owners may change from the
+// details page. Everything else is shown read-only.
+var editableServerPrefs = map[string]bool{
+	"FriendlyName":                     true,
+	"FSEventLibraryUpdatesEnabled":     true,
+	"FSEventLibraryPartialScanEnabled": true,
+	"ScheduledLibraryUpdatesEnabled":   true,
+	"autoEmptyTrash":                   true,
+	"GenerateIntroMarkerBehavior":      true,
+	"GenerateCreditsMarkerBehavior":    true,
+}
+
+func ServerDetails(ctx context.Context, appCtx *appctx.AppContext, serverID string) *router.Response {
+	mgr := appCtx.Manager
+	src := mgr.SourceForServer(serverID)
+	if src == nil {
+		return router.FromError(gettext.Get("Server"), errSourceNotFound(serverID))
+	}
+
+	admin, ok := sources.AsAdmin(src)
+	if !ok {
+		return router.FromError(src.Name(), errNotSupported(src, "server management"))
+	}
+
+	info, err := admin.ServerInfo(ctx)
+	if err != nil {
+		return router.FromError(src.Name(), err)
+	}
+
+	owned := mgr.IsServerOwned(serverID)
+	body := VStack().Spacing(25).VMargin(20).HMargin(40)
+
+	// Server overview
+	transcodes := gettext.Get("Idle")
+	if info.TranscoderActiveVideoSessions > 0 {
+		transcodes = gettext.GetN("%d active video transcode", "%d active video transcodes",
+			info.TranscoderActiveVideoSessions, info.TranscoderActiveVideoSessions)
+	}
+	body = body.Append(PreferencesGroup(
+		propertyRow(gettext.Get("Version"), info.Version),
+		propertyRow(gettext.Get("Platform"), strings.TrimSpace(info.Platform+" "+info.PlatformVersion)),
+		propertyRow(gettext.Get("Owner"), info.MyPlexUsername),
+		propertyRow(gettext.Get("Transcoder"), transcodes),
+		propertyRow(gettext.Get("Machine Identifier"), info.MachineIdentifier),
+	).Title(info.FriendlyName))
+
+	// Libraries
+	sections, err := src.LibrarySections(ctx)
+	if err != nil {
+		slog.Warn("failed to fetch library sections", "source", src.Name(), "error", err)
+	}
+	if len(sections) > 0 {
+		var rows []any
+		for _, section := range sections {
+			_, total, err := src.LibraryContent(ctx, section.Key, &sources.ContentOptions{Size: 1})
+			if err != nil {
+				slog.Warn("failed to count library items", "section", section.Title, "error", err)
+			}
+			details := []string{gettext.GetN("%d item", "%d items", total, total)}
+			if section.ScannedAt > 0 {
+				scanned := time.Unix(section.ScannedAt, 0).Format("Jan 2, 2006 15:04")
+				details = append(details, gettext.Getf("Last scanned %s", scanned))
+			}
+			rows = append(rows, propertyRow(section.Title, strings.Join(details, " · ")))
+		}
+		body = body.Append(PreferencesGroup(rows...).Title(gettext.Get("Libraries")))
+	}
+
+	// Preferences, grouped by category
+	settings, err := admin.Preferences(ctx)
+	if err != nil {
+		slog.Warn("failed to fetch server preferences", "source", src.Name(), "error", err)
+	}
+	if len(settings) > 0 {
+		var (
+			groups  []string
+			byGroup = make(map[string][]sources.ServerSetting)
+		)
+		for _, setting := range settings {
+			if setting.Hidden {
+				continue
+			}
+			if _, ok := byGroup[setting.Group]; !ok {
+				groups = append(groups, setting.Group)
+			}
+			byGroup[setting.Group] = append(byGroup[setting.Group], setting)
+		}
+
+		var rows []any
+		for _, group := range groups {
+			row := adw.NewExpanderRow()
+			row.SetTitle(serverPrefGroupTitle(group))
+			row.SetSubtitle(gettext.GetN("%d setting", "%d settings", len(byGroup[group]), len(byGroup[group])))
+			for _, setting := range byGroup[group] {
+				if owned && editableServerPrefs[setting.ID] {
+					row.AddRow(serverPrefEditRow(ctx, src, admin, setting))
+				} else {
+					row.AddRow(&serverPrefRow(setting).Widget)
+				}
+			}
+			rows = append(rows, Widget(&row.Widget))
+		}
+
+		if !owned {
+			note := adw.NewActionRow()
+			note.SetTitle(gettext.Get("Only the server owner can change preferences."))
+			note.AddCssClass("dim-label")
+			rows = append([]any{Widget(&note.Widget)}, rows...)
+		}
+		body = body.Append(PreferencesGroup(rows...).Title(gettext.Get("Preferences")))
+	}
+
+	return &router.Response{
+		PageTitle: src.Name(),
+		View: ScrolledWindow().
+			Child(Clamp().MaximumSize(900).Child(body)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+	}
+}
+
+// serverPrefRow shows a preference and its current value read-only.
+func serverPrefRow(setting sources.ServerSetting) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(setting.Label)
+	row.SetSubtitle(setting.Summary)
+	row.SetSubtitleLines(2)
+
+	value := gtk.NewLabel(serverPrefValueLabel(setting))
+	value.AddCssClass("dim-label")
+	value.SetSelectable(true)
+	value.SetMaxWidthChars(24)
+	value.SetWrap(true)
+	row.AddSuffix(&value.Widget)
+	return row
+}
+
+// serverPrefEditRow builds an editable row for whitelisted preferences.
+// Changes are saved to the server immediately.
+func serverPrefEditRow(ctx context.Context, src sources.Source, admin sources.AdminSource, setting sources.ServerSetting) *gtk.Widget {
+	save := func(value string) {
+		go func() {
+			err := admin.SetPreferences(ctx, map[string]string{setting.ID: value})
+			if err != nil {
+				slog.Error("failed to update server preference", "source", src.Name(), "id", setting.ID, "error", err)
+				notifications.OnToast.Notify(gettext.Get("Failed to save preference"))
+				return
+			}
+			notifications.OnToast.Notify(gettext.Get("Preference saved"))
+		}()
+	}
+
+	switch {
+	case setting.Type == "bool":
+		row := adw.NewSwitchRow()
+		row.SetTitle(setting.Label)
+		row.SetSubtitle(setting.Summary)
+		row.SetActive(setting.Value == "true")
+		cb := func() {
+			save(strconv.FormatBool(row.GetActive()))
+		}
+		row.ConnectSignal("notify::active", &cb)
+		return &row.Widget
+	case setting.EnumValues != "":
+		values, labels := parseEnumValues(setting.EnumValues)
+		row := adw.NewComboRow()
+		row.SetTitle(setting.Label)
+		row.SetSubtitle(setting.Summary)
+		row.SetModel(gtk.NewStringList(labels))
+		for i, v := range values {
+			if v == string(setting.Value) {
+				row.SetSelected(uint32(i))
+			}
+		}
+		cb := func() {
+			if i := int(row.GetSelected()); i < len(values) {
+				save(values[i])
+			}
+		}
+		row.ConnectSignal("notify::selected", &cb)
+		return &row.Widget
+	default:
+		row := adw.NewEntryRow()
+		row.SetTitle(setting.Label)
+		row.SetText(string(setting.Value))
+		row.SetShowApplyButton(true)
+		row.ConnectApply(new(func(r adw.EntryRow) {
+			save(r.GetText())
+		}))
+		return &row.Widget
+	}
+}
+
+// serverPrefValueLabel returns the display form of a preference value,
+// resolving enum values to their labels.
+func serverPrefValueLabel(setting sources.ServerSetting) string {
+	value := string(setting.Value)
+	if setting.EnumValues != "" {
+		values, labels := parseEnumValues(setting.EnumValues)
+		for i, v := range values {
+			if v == value {
+				return labels[i]
+			}
+		}
+	}
+	switch setting.Type {
+	case "bool":
+		if value == "true" {
+			return gettext.Get("On")
+		}
+		return gettext.Get("Off")
+	}
+	if value == "" {
+		return "—"
+	}
+	return value
+}
+
+// parseEnumValues splits a Plex enum definition ("0:Never|1:Always") into
+// parallel value and label slices.
+func parseEnumValues(enum string) (values, labels []string) {
+	for _, pair := range strings.Split(enum, "|") {
+		value, label, ok := strings.Cut(pair, ":")
+		if !ok {
+			label = value
+		}
+		values = append(values, value)
+		labels = append(labels, label)
+	}
+	return values, labels
+}
+
+func serverPrefGroupTitle(group string) string {
+	if group == "" {
+		return gettext.Get("Other")
+	}
+	return fmt.Sprintf("%s%s", strings.ToUpper(group[:1]), group[1:])
+}
@@ -0,0 +1,208 @@
+package pages
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/dergs/tonearm/pkg/schwifty/state"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/glib"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/utils/notifications"
+)
+
+var SessionsRoute = router.NewRoute("sessions", activeSessions)
+
+// sessionsPollInterval is how often the dashboard refreshes, in milliseconds.
+const sessionsPollInterval = 5000
+
+// serverSessions holds the sessions fetched from a single server.
+type serverSessions struct {
+	src      sources.Source
+	admin    sources.AdminSource
+	sessions []sources.Session
+	err      error
+}
+
+func activeSessions(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
+	owned := appCtx.Manager.OwnedSources()
+	if len(owned) == 0 {
+		return &router.Response{
+			PageTitle: gettext.Get("Active Sessions"),
+			View: StatusPage().
+				IconName("network-server-symbolic").
+				Title(gettext.Get("Active Sessions")).
+				Description(gettext.Get("Sessions are only visible on servers you own.")),
+		}
+	}
+
+	var (
+		pollID  uint32
+		refresh func()
+	)
+	// The first fetch happens once the page is mapped, like every refresh.
+	contentState := state.NewStateful[any](Spinner().SizeRequest(32, 32).HAlign(gtk.AlignCenterValue).VAlign(gtk.AlignCenterValue))
+	refresh = func() {
+		go func() {
+			servers := fetchSessions(ctx, owned)
+			schwifty.OnMainThreadOncePure(func() {
+				contentState.SetValue(sessionsView(ctx, servers, refresh))
+			})
+		}()
+	}
+
+	return &router.Response{
+		PageTitle: gettext.Get("Active Sessions"),
+		View: ScrolledWindow().
+			BindChild(contentState).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue).
+			ConnectMap(func(w gtk.Widget) {
+				refresh()
+				if pollID != 0 {
+					return
+				}
+				// Poll only while the page is on screen; the callback stops
+				// itself once the page is unmapped by navigation.
+				pollCb := glib.SourceFunc(func(uintptr) bool {
+					if !w.GetMapped() {
+						pollID = 0
+						return false
+					}
+					refresh()
+					return true
+				})
+				pollID = glib.TimeoutAdd(sessionsPollInterval, &pollCb, 0)
+			}),
+	}
+}
+
+func fetchSessions(ctx context.Context, srcs []sources.Source) []serverSessions {
+	result := make([]serverSessions, 0, len(srcs))
+	for _, src := range srcs {
+		admin, ok := sources.AsAdmin(src)
+		if !ok {
+			continue
+		}
+		sessions, err := admin.Sessions(ctx)
+		if err != nil {
+			slog.Warn("failed to fetch sessions", "source", src.Name(), "error", err)
+		}
+		result = append(result, serverSessions{src: src, admin: admin, sessions: sessions, err: err})
+	}
+	return result
+}
+
+// sessionsView renders one group per server. onTerminated is called after a
+// session was stopped so the dashboard can refresh immediately.
+func sessionsView(ctx context.Context, servers []serverSessions, onTerminated func()) any {
+	body := VStack().Spacing(25).VMargin(20).HMargin(40)
+
+	for _, s := range servers {
+		var rows []any
+		switch {
+		case s.err != nil:
+			rows = append(rows, Widget(&sessionsMessageRow(gettext.Get("Could not load sessions")).Widget))
+		case len(s.sessions) == 0:
+			rows = append(rows, Widget(&sessionsMessageRow(gettext.Get("Nothing is playing")).Widget))
+		default:
+			for _, sess := range s.sessions {
+				rows = append(rows, Widget(&sessionRow(ctx, s.src, s.admin, sess, onTerminated).Widget))
+			}
+		}
+		body = body.Append(PreferencesGroup(rows...).Title(s.src.Name()))
+	}
+
+	return Clamp().MaximumSize(900).Child(body.VAlign(gtk.AlignStartValue))
+}
+
+func sessionsMessageRow(message string) *adw.ActionRow {
+	row := adw.NewActionRow()
+	row.SetTitle(message)
+	row.AddCssClass("dim-label")
+	return row
+}
+
+// sessionRow builds a row showing who is playing what, how it is delivered
+// and how far along playback is.
+func sessionRow(ctx context.Context, src sources.Source, admin sources.AdminSource, sess sources.Session, onTerminated func()) *adw.ActionRow {
+	title := sess.Title
+	switch sess.Type {
+	case "episode":
+		title = fmt.Sprintf("%s – S%d E%d · %s", sess.GrandparentTitle, sess.ParentIndex, sess.Index, sess.Title)
+	case "track":
+		title = fmt.Sprintf("%s – %s", sess.GrandparentTitle, sess.Title)
+	}
+
+	player := sess.Player.Title
+	if sess.Player.Product != "" {
+		player += " (" + sess.Player.Product + ")"
+	}
+	details := []string{sess.User.Title, player, sessionDecisionLabel(sess.Decision())}
+	if sess.Session.Bandwidth > 0 {
+		details = append(details, fmt.Sprintf("%.1f Mbps", float64(sess.Session.Bandwidth)/1000))
+	}
+	if sess.Player.State == "paused" {
+		details = append(details, gettext.Get("Paused"))
+	}
+
+	row := adw.NewActionRow()
+	row.SetUseMarkup(false)
+	row.SetTitle(title)
+	row.SetSubtitle(strings.Join(details, " · "))
+
+	progress := gtk.NewProgressBar()
+	progress.SetValign(gtk.AlignCenterValue)
+	progress.SetSizeRequest(160, -1)
+	if sess.Duration > 0 {
+		progress.SetFraction(float64(sess.ViewOffset) / float64(sess.Duration))
+	}
+	row.AddSuffix(&progress.Widget)
+
+	if sess.Session.ID != "" {
+		stop := gtk.NewButtonFromIconName("media-playback-stop-symbolic")
+		stop.SetValign(gtk.AlignCenterValue)
+		stop.SetTooltipText(gettext.Get("Stop Stream"))
+		stop.AddCssClass("flat")
+		sessionID := sess.Session.ID
+		stop.ConnectClicked(new(func(b gtk.Button) {
+			b.SetSensitive(false)
+			go func() {
+				err := admin.TerminateSession(ctx, sessionID, gettext.Get("The server owner stopped this stream."))
+				if err != nil {
+					slog.Error("failed to terminate session", "source", src.Name(), "session", sessionID, "error", err)
+					notifications.OnToast.Notify(gettext.Get("Failed to stop stream"))
+					schwifty.OnMainThreadOncePure(func() {
+						stop.SetSensitive(true)
+					})
+					return
+				}
+				notifications.OnToast.Notify(gettext.Get("Stream stopped"))
+				if onTerminated != nil {
+					onTerminated()
+				}
+			}()
+		}))
+		row.AddSuffix(&stop.Widget)
+	}
+
+	return row
+}
+
+func sessionDecisionLabel(decision string) string {
+	switch decision {
+	case "transcode":
+		return gettext.Get("Transcode")
+	case "copy":
+		return gettext.Get("Direct Stream")
+	default:
+		return gettext.Get("Direct Play")
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"strconv"
 
 	"codeberg.org/dergs/tonearm/pkg/schwifty"
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
@@ -28,16 +27,17 @@ func Show(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey st
 		return router.FromError(gettext.Get("TV Show"), errSourceNotFound(serverID))
 	}
 
-	meta, err := src.GetMetadata(ctx, ratingKey)
+	id := sources.ItemID(ratingKey)
+	meta, err := src.GetMetadata(ctx, id)
 	if err != nil {
 		return router.FromError(gettext.Get("TV Show"), err)
 	}
 
-	seasons, err := src.GetChildren(ctx, ratingKey)
+	seasons, err := src.GetChildren(ctx, id)
 	if err != nil {
 		slog.Warn("failed to fetch seasons", "ratingKey", ratingKey, "error", err)
 	}
-	relatedHubs, err := src.RelatedHubs(ctx, ratingKey)
+	relatedHubs, err := src.RelatedHubs(ctx, id)
 	if err != nil {
 		slog.Debug("failed to fetch related hubs", "ratingKey", ratingKey, "error", err)
 	}
@@ -48,7 +48,10 @@ func Show(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey st
 	body := VStack().Spacing(25).VMargin(20).HMargin(40)
 
 	// Hero section
-	var buildButtonRow func() schwifty.Box
+	buildButtonRow := func() schwifty.Box {
+		return HStack().Spacing(10).
+			Append(watchlistToggle(ctx, appCtx, serverID, meta.GUID))
+	}
 	if nextEpisode != nil && len(nextEpisode.Media) > 0 && len(nextEpisode.Media[0].Part) > 0 {
 		ep := nextEpisode
 		playLabel := gettext.Get("Play")
@@ -79,14 +82,15 @@ func Show(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey st
 								Title:       ep.Title,
 								PartKey:     ep.Media[0].Part[0].Key,
 								Window:      appCtx.Window,
-								RatingKey:   ep.RatingKey,
+								ItemID:      ep.ID,
 								Media:       ep.Media,
 								Source:      src,
 								ViewOffset:  ep.ViewOffset,
 								NextEpisode: nextEp,
 							})
 						}),
-				)
+				).
+				Append(watchlistToggle(ctx, appCtx, serverID, meta.GUID))
 		}
 	}
 
@@ -122,10 +126,10 @@ func Show(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey st
 	}
 
 	// Cast section
-	if len(meta.Role) > 0 {
+	if len(meta.Cast) > 0 {
 		castList := lists.NewHorizontalList(gettext.Get("Cast"))
-		for _, role := range meta.Role {
-			castList.Append(cards.NewCastMember(role.Tag, role.Role, src.PhotoTranscodeURL(role.Thumb, 140, 140), serverID, strconv.Itoa(role.ID)))
+		for _, role := range meta.Cast {
+			castList.Append(cards.NewCastMember(role.Name, role.Role, src.PhotoTranscodeURL(role.Thumb, 140, 140), serverID, role.ID))
 		}
 		body = body.Append(castList.SetPageMargin(0))
 	}
@@ -138,6 +142,9 @@ func Show(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey st
 		hub := &relatedHubs[i]
 		list := lists.NewHorizontalList(hub.Title)
 		if lists.RenderHub(list, hub, coverURL, serverID) {
+			if hub.More && hub.Key != "" {
+				list.SetViewAllRoute(hubPath(serverID, hub))
+			}
 			body = body.Append(list.SetPageMargin(0))
 		}
 	}
@@ -153,12 +160,12 @@ func Show(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey st
 // findNextEpisode finds the next episode to play for a show.
 // It returns the first in-progress or unwatched episode, skipping specials (index 0).
 // If all episodes are watched, it returns the first episode of the first non-specials season.
-func findNextEpisode(ctx context.Context, src sources.Source, seasons []sources.Metadata) *sources.Metadata {
+func findNextEpisode(ctx context.Context, src sources.Source, seasons []sources.Item) *sources.Item {
 	if len(seasons) == 0 {
 		return nil
 	}
 
-	var fallback *sources.Metadata
+	var fallback *sources.Item
 
 	for i := range seasons {
 		season := &seasons[i]
@@ -171,7 +178,7 @@ func findNextEpisode(ctx context.Context, src sources.Source, seasons []sources.
 		// Skip fully watched seasons, but not before recording a fallback
 		if season.ViewedLeafCount >= season.LeafCount {
 			if fallback == nil {
-				episodes, err := src.GetChildren(ctx, season.RatingKey)
+				episodes, err := src.GetChildren(ctx, season.ID)
 				if err == nil && len(episodes) > 0 {
 					fallback = &episodes[0]
 				}
@@ -180,9 +187,9 @@ func findNextEpisode(ctx context.Context, src sources.Source, seasons []sources.
 		}
 
 		// This season has unwatched episodes - fetch them
-		episodes, err := src.GetChildren(ctx, season.RatingKey)
+		episodes, err := src.GetChildren(ctx, season.ID)
 		if err != nil {
-			slog.Warn("failed to fetch episodes for season", "ratingKey", season.RatingKey, "error", err)
+			slog.Warn("failed to fetch episodes for season", "ratingKey", season.ID, "error", err)
 			continue
 		}
 		if len(episodes) == 0 {
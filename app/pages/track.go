@@ -0,0 +1,143 @@
+package pages
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/audio"
+	"github.com/0skillallluck/scanline/app/components/cards"
+	"github.com/0skillallluck/scanline/app/components/widgets"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/app/sources"
+	"github.com/0skillallluck/scanline/internal/gettext"
+)
+
+var TrackRoute = router.NewRoute("track/:server/:ratingKey", Track)
+
+func Track(ctx context.Context, appCtx *appctx.AppContext, serverID, ratingKey string) *router.Response {
+	mgr := appCtx.Manager
+	src := mgr.SourceForServer(serverID)
+	if src == nil {
+		return router.FromError(gettext.Get("Track"), errSourceNotFound(serverID))
+	}
+
+	meta, err := src.GetMetadata(ctx, sources.ItemID(ratingKey))
+	if err != nil {
+		return router.FromError(gettext.Get("Track"), err)
+	}
+
+	artist := meta.OriginalTitle
+	if artist == "" {
+		artist = meta.GrandparentTitle
+	}
+
+	var badges []string
+	if meta.ParentYear > 0 {
+		badges = append(badges, strconv.Itoa(meta.ParentYear))
+	}
+	if meta.Duration > 0 {
+		badges = append(badges, widgets.FormatTimestamp(meta.Duration))
+	}
+	if details := audioDetails(meta.Media); details != "" {
+		badges = append(badges, details)
+	}
+
+	body := VStack().Spacing(25).VMargin(20).HMargin(40)
+
+	heroContent := widgets.HeroContent(widgets.HeroContentParams{
+		Title:               meta.Title,
+		Subtitle:            artist,
+		SubtitleClass:       "title-2 dimmed",
+		SubtitleActionName:  "win.route.artist",
+		SubtitleActionValue: serverID + "/" + string(meta.GrandparentID),
+		BadgeLinks: []widgets.BadgeLink{{
+			Label:       meta.ParentTitle,
+			ActionName:  "win.route.album",
+			ActionValue: serverID + "/" + string(meta.ParentID),
+		}},
+		Badges: badges,
+		BuildButtonRow: func() schwifty.Box {
+			return HStack().Spacing(10).
+				Append(
+					Button().
+						Child(
+							HStack(
+								Image().FromIconName("media-playback-start-symbolic"),
+								Label(gettext.Get("Play")),
+							).Spacing(6),
+						).
+						TooltipText(gettext.Get("Play this track")).
+						WithCSSClass("suggested-action").
+						WithCSSClass("pill").
+						ConnectClicked(func(b gtk.Button) {
+							playTrackInAlbum(ctx, src, meta)
+						}),
+				)
+		},
+		Summary: meta.Summary,
+		MetadataRows: []widgets.MetadataRow{
+			{Label: "Track", Value: gettext.Getf("Disc %d, Track %d", meta.ParentIndex, meta.Index)},
+		},
+	})
+
+	hero := widgets.HeroSection(
+		widgets.HeroPosterParams{
+			ImageURL: src.PhotoTranscodeURL(meta.ParentThumb, 300, 300),
+			Width:    300,
+			Height:   300,
+		},
+		heroContent,
+	)
+
+	body = body.Append(hero)
+
+	// Media info section
+	if infoCards := cards.MediaInfo(meta.Media); infoCards != nil {
+		body = body.Append(infoCards)
+	}
+
+	return &router.Response{
+		PageTitle: meta.Title,
+		View: ScrolledWindow().
+			Child(Clamp().MaximumSize(1200).Child(body)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+	}
+}
+
+// playTrackInAlbum queues the track's album and starts playing at the
+// track, so playback continues with the rest of the album.
+func playTrackInAlbum(ctx context.Context, src sources.Source, meta *sources.Item) {
+	go func() {
+		siblings, err := src.GetChildren(ctx, meta.ParentID)
+		if err != nil {
+			slog.Warn("failed to fetch album tracks", "ratingKey", meta.ParentID, "error", err)
+		}
+
+		var (
+			queue []audio.Track
+			start int
+		)
+		for i := range siblings {
+			if track, ok := audio.TrackFromMetadata(src, &siblings[i]); ok {
+				if track.ItemID == meta.ID {
+					start = len(queue)
+				}
+				queue = append(queue, track)
+			}
+		}
+		if len(queue) == 0 {
+			if track, ok := audio.TrackFromMetadata(src, meta); ok {
+				queue = append(queue, track)
+			}
+		}
+
+		schwifty.OnMainThreadOncePure(func() {
+			audio.Play(queue, start)
+		})
+	}()
+}
@@ -2,8 +2,14 @@ package pages
 
 import (
 	"context"
+	"errors"
+	"log/slog"
 	"strconv"
+	"strings"
+	"time"
 
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/dergs/tonearm/pkg/schwifty/state"
 	. "codeberg.org/dergs/tonearm/pkg/schwifty/syntax"
 	"codeberg.org/puregotk/puregotk/v4/adw"
 	"codeberg.org/puregotk/puregotk/v4/gdk"
@@ -11,32 +17,111 @@ import (
 	"codeberg.org/puregotk/puregotk/v4/gtk"
 	"codeberg.org/puregotk/puregotk/v4/pango"
 	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/widgets"
 	"github.com/0skillallluck/scanline/app/preference"
 	"github.com/0skillallluck/scanline/app/router"
 	"github.com/0skillallluck/scanline/app/sources"
 	"github.com/0skillallluck/scanline/internal/gettext"
 	"github.com/0skillallluck/scanline/utils/imageutils"
+	"github.com/0skillallluck/scanline/utils/notifications"
 )
 
-var WatchlistRoute = router.NewRoute("watchlist", watchlist)
+var (
+	WatchlistRoute     = router.NewRoute("watchlist", watchlist)
+	WatchlistItemRoute = router.NewRoute("watchlist/:ratingKey", watchlistItem)
+)
+
+var (
+	watchlistFilterLabels = []string{
+		gettext.Get("All"),
+		gettext.Get("Available"),
+		gettext.Get("Released"),
+	}
+	watchlistFilters = []string{
+		sources.WatchlistFilterAll,
+		sources.WatchlistFilterAvailable,
+		sources.WatchlistFilterReleased,
+	}
+
+	watchlistSortLabels = []string{
+		gettext.Get("Date Added"),
+		gettext.Get("Release Date"),
+		gettext.Get("Title"),
+	}
+	watchlistSorts = []string{
+		sources.WatchlistSortDateAdded,
+		sources.WatchlistSortReleaseDate,
+		sources.WatchlistSortTitle,
+	}
+)
 
 func watchlist(ctx context.Context, appCtx *appctx.AppContext) *router.Response {
-	items, err := appCtx.Manager.Watchlist(ctx)
+	viewState := state.NewStateful[any](Spinner().SizeRequest(32, 32).HAlign(gtk.AlignCenterValue).VAlign(gtk.AlignCenterValue))
+
+	filterDD := gtk.NewDropDownFromStrings(watchlistFilterLabels)
+	filterDD.SetTooltipText(gettext.Get("Filter"))
+	sortDD := gtk.NewDropDownFromStrings(watchlistSortLabels)
+	sortDD.SetTooltipText(gettext.Get("Sort"))
+
+	// generation discards results of loads that were superseded by a newer
+	// filter or sort selection.
+	var generation int
+	load := func() {
+		generation++
+		gen := generation
+		opts := sources.WatchlistOptions{
+			Filter: watchlistFilters[filterDD.GetSelected()],
+			Sort:   watchlistSorts[sortDD.GetSelected()],
+		}
+		viewState.SetValue(Spinner().SizeRequest(32, 32).HAlign(gtk.AlignCenterValue).VAlign(gtk.AlignCenterValue))
+		go func() {
+			view := watchlistView(ctx, appCtx, opts)
+			schwifty.OnMainThreadOncePure(func() {
+				if gen == generation {
+					viewState.SetValue(view)
+				}
+			})
+		}()
+	}
+	filterDD.ConnectSignal("notify::selected", new(func() { load() }))
+	sortDD.ConnectSignal("notify::selected", new(func() { load() }))
+	load()
+
+	return &router.Response{
+		PageTitle: gettext.Get("Watchlist"),
+		Toolbar: HStack(
+			Widget(&filterDD.Widget),
+			Widget(&sortDD.Widget),
+		).Spacing(8),
+		View: ScrolledWindow().
+			BindChild(viewState).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+	}
+}
+
+// watchlistView fetches the watchlist and builds the poster grid.
+func watchlistView(ctx context.Context, appCtx *appctx.AppContext, opts sources.WatchlistOptions) any {
+	items, err := appCtx.Manager.Watchlist(ctx, opts)
 	if err != nil {
-		return router.FromError(gettext.Get("Watchlist"), err)
+		return StatusPage().
+			IconName("dialog-error-symbolic").
+			Title(gettext.Get("Watchlist")).
+			Description(err.Error())
 	}
 
 	if len(items) == 0 {
-		return &router.Response{
-			PageTitle: gettext.Get("Watchlist"),
-			View: StatusPage().
-				IconName("starred-symbolic").
-				Title(gettext.Get("Watchlist")).
-				Description(gettext.Get("Your watchlist is empty.")),
+		description := gettext.Get("Your watchlist is empty.")
+		if opts.Filter != "" && opts.Filter != sources.WatchlistFilterAll {
+			description = gettext.Get("No watchlist items match this filter.")
 		}
+		return StatusPage().
+			IconName("starred-symbolic").
+			Title(gettext.Get("Watchlist")).
+			Description(description)
 	}
 
 	matches := appCtx.Manager.ResolveWatchlist(ctx, items)
+	now := time.Now()
 
 	body := WrapBox().
 		ConnectConstruct(func(w *adw.WrapBox) {
@@ -66,18 +151,31 @@ func watchlist(ctx context.Context, appCtx *appctx.AppContext) *router.Response
 			match = &m
 		}
 
-		body = body.Append(watchlistPoster(item.Title, subtitle, item.Thumb, match))
-	}
+		// Availability: where the item can be played, or when it comes out.
+		// Release dates come from the discover records, see Manager.Watchlist;
+		// streaming offers are left out, as the discover API only lists them
+		// per region and provider.
+		var status, statusClass string
+		switch {
+		case match != nil:
+			status, statusClass = gettext.Get("In your library"), "success"
+			if src := appCtx.Manager.SourceForServer(match.ServerID); src != nil {
+				status = gettext.Getf("On %s", src.Name())
+			}
+		case !item.Released(now):
+			date, _ := item.ReleaseDate()
+			status, statusClass = gettext.Getf("Coming %s", date.Format("Jan 2, 2006")), "accent"
+		default:
+			status, statusClass = gettext.Get("Not in your libraries"), "dim-label"
+		}
 
-	return &router.Response{
-		PageTitle: gettext.Get("Watchlist"),
-		View: ScrolledWindow().
-			Child(body.VMargin(20).HMargin(20)).
-			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+		body = body.Append(watchlistPoster(item.Title, subtitle, status, statusClass, item.Thumb, item.RatingKey, match))
 	}
+
+	return body.VMargin(20).HMargin(20)
 }
 
-func watchlistPoster(title, subtitle, thumbURL string, match *sources.WatchlistMatch) any {
+func watchlistPoster(title, subtitle, status, statusClass, thumbURL, ratingKey string, match *sources.WatchlistMatch) any {
 	btn := Button().
 		Child(
 			VStack(
@@ -104,6 +202,13 @@ func watchlistPoster(title, subtitle, thumbURL string, match *sources.WatchlistM
 					MaxWidthChars(18).
 					HAlign(gtk.AlignStartValue).
 					Ellipsis(pango.EllipsizeEndValue),
+				Label(status).
+					WithCSSClass(statusClass).
+					WithCSSClass("caption").
+					MarginTop(2).
+					MaxWidthChars(18).
+					HAlign(gtk.AlignStartValue).
+					Ellipsis(pango.EllipsizeEndValue),
 			),
 		).
 		Padding(15).
@@ -111,18 +216,188 @@ func watchlistPoster(title, subtitle, thumbURL string, match *sources.WatchlistM
 		VExpand(false).
 		WithCSSClass("flat")
 
-	if match != nil {
-		switch match.Type {
-		case "movie":
-			btn = btn.
-				ActionName("win.route.movie").
-				ActionTargetValue(glib.NewVariantString(match.ServerID + "/" + match.RatingKey))
-		case "show":
-			btn = btn.
-				ActionName("win.route.show").
-				ActionTargetValue(glib.NewVariantString(match.ServerID + "/" + match.RatingKey))
-		}
+	if match == nil {
+		return btn.
+			ActionName("win.route.watchlist").
+			ActionTargetValue(glib.NewVariantString(ratingKey))
+	}
+
+	switch match.Type {
+	case "movie":
+		btn = btn.
+			ActionName("win.route.movie").
+			ActionTargetValue(glib.NewVariantString(match.ServerID + "/" + string(match.ItemID)))
+	case "show":
+		btn = btn.
+			ActionName("win.route.show").
+			ActionTargetValue(glib.NewVariantString(match.ServerID + "/" + string(match.ItemID)))
 	}
 
 	return btn
 }
+
+// watchlistItem shows the discover details of a watchlist item that is not
+// available in any library.
+func watchlistItem(ctx context.Context, appCtx *appctx.AppContext, ratingKey string) *router.Response {
+	meta, err := appCtx.Manager.WatchlistMetadata(ctx, ratingKey)
+	if err != nil {
+		return router.FromError(gettext.Get("Watchlist"), err)
+	}
+
+	var badges []string
+	if meta.Year > 0 {
+		badges = append(badges, strconv.Itoa(meta.Year))
+	}
+	if meta.ContentRating != "" {
+		badges = append(badges, meta.ContentRating)
+	}
+	if meta.Duration > 0 {
+		badges = append(badges, widgets.FormatDuration(meta.Duration))
+	}
+	if meta.Type == "show" && meta.ChildCount > 0 {
+		badges = append(badges, gettext.GetN("%d Season", "%d Seasons", meta.ChildCount, meta.ChildCount))
+	}
+
+	heroContent := widgets.HeroContent(widgets.HeroContentParams{
+		Title:  meta.Title,
+		Badges: badges,
+		BuildButtonRow: func() schwifty.Box {
+			return HStack().Spacing(10).
+				Append(
+					Button().
+						Child(
+							HStack(
+								Image().FromIconName("user-trash-symbolic"),
+								Label(gettext.Get("Remove from Watchlist")),
+							).Spacing(6),
+						).
+						WithCSSClass("pill").
+						ConnectClicked(func(b gtk.Button) {
+							b.SetSensitive(false)
+							go func() {
+								err := appCtx.Manager.RemoveFromWatchlist(ctx, ratingKey)
+								if err != nil {
+									slog.Error("failed to remove from watchlist", "ratingKey", ratingKey, "error", err)
+								}
+								schwifty.OnMainThreadOncePure(func() {
+									if err != nil {
+										b.SetSensitive(true)
+										notifications.OnToast.Notify(gettext.Get("Failed to update watchlist"))
+										return
+									}
+									notifications.OnToast.Notify(gettext.Get("Removed from watchlist"))
+									router.Back()
+								})
+							}()
+						}),
+				)
+		},
+		Tagline: meta.Tagline,
+		Summary: meta.Summary,
+		MetadataRows: []widgets.MetadataRow{
+			{Label: "Genres", Value: discoverTagNames(meta.Genre)},
+			{Label: "Directors", Value: discoverTagNames(meta.Director)},
+			{Label: "Cast", Value: discoverTagNames(meta.Role)},
+			{Label: "Studio", Value: meta.Studio},
+		},
+	})
+
+	hero := widgets.HeroSection(
+		widgets.HeroPosterParams{
+			ImageURL: meta.Thumb,
+			Width:    240,
+			Height:   360,
+		},
+		heroContent,
+	)
+
+	body := VStack().Spacing(25).MarginTop(40).MarginBottom(20).HMargin(40).
+		Append(hero).
+		Append(
+			Label(gettext.Get("Not available on any of your servers.")).
+				WithCSSClass("dim-label").
+				HAlign(gtk.AlignStartValue),
+		)
+
+	return &router.Response{
+		PageTitle: meta.Title,
+		View: ScrolledWindow().
+			Child(Clamp().MaximumSize(1200).Child(body)).
+			Policy(gtk.PolicyNeverValue, gtk.PolicyAutomaticValue),
+	}
+}
+
+func discoverTagNames(tags []sources.WatchlistTag) string {
+	names := make([]string, 0, len(tags))
+	for _, t := range tags {
+		names = append(names, t.Tag)
+	}
+	return strings.Join(names, ", ")
+}
+
+// watchlistToggle builds a button that adds an item to, or removes it from,
+// the watchlist. It stays hidden until the item's state is known, and for
+// items without Plex online metadata.
+func watchlistToggle(ctx context.Context, appCtx *appctx.AppContext, serverID, guid string) any {
+	button := gtk.NewButton()
+	button.AddCssClass("pill")
+	button.SetVisible(false)
+	if !preference.Experimental().EnableWatchlist() {
+		return Widget(&button.Widget)
+	}
+
+	var watchlisted bool
+	update := func() {
+		icon, label, tooltip := "non-starred-symbolic", gettext.Get("Add to Watchlist"), gettext.Get("Add this to your watchlist")
+		if watchlisted {
+			icon, label, tooltip = "starred-symbolic", gettext.Get("On Watchlist"), gettext.Get("Remove this from your watchlist")
+		}
+		button.SetChild(HStack(
+			Image().FromIconName(icon),
+			Label(label),
+		).Spacing(6).ToGTK())
+		button.SetTooltipText(tooltip)
+	}
+
+	go func() {
+		on, err := appCtx.Manager.IsWatchlisted(ctx, serverID, guid)
+		if err != nil {
+			if !errors.Is(err, sources.ErrNotOnDiscover) {
+				slog.Warn("failed to fetch watchlist state", "guid", guid, "error", err)
+			}
+			return
+		}
+		schwifty.OnMainThreadOncePure(func() {
+			watchlisted = on
+			update()
+			button.SetVisible(true)
+		})
+	}()
+
+	button.ConnectClicked(new(func(b gtk.Button) {
+		b.SetSensitive(false)
+		target := !watchlisted
+		go func() {
+			err := appCtx.Manager.SetWatchlisted(ctx, serverID, guid, target)
+			if err != nil {
+				slog.Error("failed to update watchlist", "guid", guid, "error", err)
+			}
+			schwifty.OnMainThreadOncePure(func() {
+				b.SetSensitive(true)
+				if err != nil {
+					notifications.OnToast.Notify(gettext.Get("Failed to update watchlist"))
+					return
+				}
+				watchlisted = target
+				update()
+				if watchlisted {
+					notifications.OnToast.Notify(gettext.Get("Added to watchlist"))
+				} else {
+					notifications.OnToast.Notify(gettext.Get("Removed from watchlist"))
+				}
+			})
+		}()
+	}))
+
+	return Widget(&button.Widget)
+}
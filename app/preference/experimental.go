@@ -40,3 +40,19 @@ func (e *ExperimentalSettings) BindStartInFullscreen(target *gobject.Object, pro
 func (e *ExperimentalSettings) StartInFullscreen() bool {
 	return e.settings.GetBoolean("start-in-fullscreen")
 }
+
+func (e *ExperimentalSettings) BindEnableJellyfin(target *gobject.Object, property string) {
+	e.settings.Bind("enable-jellyfin", target, property, gio.GSettingsBindNoSensitivityValue)
+}
+
+func (e *ExperimentalSettings) EnableJellyfin() bool {
+	return e.settings.GetBoolean("enable-jellyfin")
+}
+
+func (e *ExperimentalSettings) BindEnableEmby(target *gobject.Object, property string) {
+	e.settings.Bind("enable-emby", target, property, gio.GSettingsBindNoSensitivityValue)
+}
+
+func (e *ExperimentalSettings) EnableEmby() bool {
+	return e.settings.GetBoolean("enable-emby")
+}
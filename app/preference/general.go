@@ -2,6 +2,7 @@ package preference
 
 import (
 	"codeberg.org/puregotk/puregotk/v4/gio"
+	"codeberg.org/puregotk/puregotk/v4/gobject"
 )
 
 type GeneralSettings struct {
@@ -31,3 +32,67 @@ func (g *GeneralSettings) GetWindowWidth() int32 {
 func (g *GeneralSettings) SetWindowWidth(width int32) {
 	g.settings.SetInt("window-width", width)
 }
+
+func (g *GeneralSettings) BindNormalizeVolume(target *gobject.Object, property string) {
+	g.settings.Bind("normalize-volume", target, property, gio.GSettingsBindNoSensitivityValue)
+}
+
+func (g *GeneralSettings) NormalizeVolume() bool {
+	return g.settings.GetBoolean("normalize-volume")
+}
+
+func (g *GeneralSettings) OnNormalizeVolumeChanged(callback func()) {
+	g.settings.ConnectSignal("changed::normalize-volume", new(callback))
+}
+
+func (g *GeneralSettings) GetMusicVolume() float64 {
+	return g.settings.GetDouble("music-volume")
+}
+
+func (g *GeneralSettings) SetMusicVolume(volume float64) {
+	g.settings.SetDouble("music-volume", volume)
+}
+
+func (g *GeneralSettings) BindSlideshowInterval(target *gobject.Object, property string) {
+	g.settings.Bind("slideshow-interval", target, property, gio.GSettingsBindNoSensitivityValue)
+}
+
+func (g *GeneralSettings) SlideshowInterval() int32 {
+	return g.settings.GetInt("slideshow-interval")
+}
+
+func (g *GeneralSettings) BindSlideshowShuffle(target *gobject.Object, property string) {
+	g.settings.Bind("slideshow-shuffle", target, property, gio.GSettingsBindNoSensitivityValue)
+}
+
+func (g *GeneralSettings) SlideshowShuffle() bool {
+	return g.settings.GetBoolean("slideshow-shuffle")
+}
+
+func (g *GeneralSettings) BindPickProfileAtStartup(target *gobject.Object, property string) {
+	g.settings.Bind("pick-profile-at-startup", target, property, gio.GSettingsBindNoSensitivityValue)
+}
+
+func (g *GeneralSettings) PickProfileAtStartup() bool {
+	return g.settings.GetBoolean("pick-profile-at-startup")
+}
+
+func (g *GeneralSettings) BindUnifyLibraries(target *gobject.Object, property string) {
+	g.settings.Bind("unify-libraries", target, property, gio.GSettingsBindNoSensitivityValue)
+}
+
+func (g *GeneralSettings) UnifyLibraries() bool {
+	return g.settings.GetBoolean("unify-libraries")
+}
+
+func (g *GeneralSettings) OnUnifyLibrariesChanged(callback func()) {
+	g.settings.ConnectSignal("changed::unify-libraries", new(callback))
+}
+
+func (g *GeneralSettings) BindSyncWatchState(target *gobject.Object, property string) {
+	g.settings.Bind("sync-watch-state", target, property, gio.GSettingsBindNoSensitivityValue)
+}
+
+func (g *GeneralSettings) SyncWatchState() bool {
+	return g.settings.GetBoolean("sync-watch-state")
+}
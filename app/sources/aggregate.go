@@ -0,0 +1,345 @@
+package sources
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ItemCopy is a copy of an item on one server.
+type ItemCopy struct {
+	ServerID string
+	Item     Item
+}
+
+// MergedItem is an item de-duplicated across servers by its GUIDs. Copies
+// is ordered best first: copies on reachable servers, then by resolution,
+// then copies on servers connected to locally.
+type MergedItem struct {
+	Copies []ItemCopy
+}
+
+// Best returns the preferred copy of the item.
+func (mi *MergedItem) Best() *ItemCopy {
+	return &mi.Copies[0]
+}
+
+// HubSource is the hub of one server that a merged hub was built from.
+type HubSource struct {
+	ServerID string
+
+	// Key fetches the hub's items with Source.HubItems.
+	Key string
+
+	// More indicates that the server's hub has more items.
+	More bool
+}
+
+// MergedHub is a hub combined from the hubs of the same kind on each
+// server.
+type MergedHub struct {
+	Title string
+	Type  string
+	Kind  string
+
+	Items   []MergedItem
+	Sources []HubSource
+}
+
+// IsMergedType reports whether libraries of a section type are merged
+// across servers. Only movies and shows carry the GUIDs needed to
+// de-duplicate them.
+func IsMergedType(sectionType string) bool {
+	return sectionType == "movie" || sectionType == "show"
+}
+
+// serverRank holds what copies of items on a server are ranked by.
+type serverRank struct {
+	order     int
+	reachable bool
+	local     bool
+}
+
+// mergeSources returns the enabled sources in a stable order, along with
+// the ranks of their servers.
+func (m *Manager) mergeSources() ([]Source, map[string]serverRank) {
+	sources := m.EnabledSources()
+	slices.SortFunc(sources, func(a, b Source) int {
+		return cmp.Or(strings.Compare(a.Name(), b.Name()), strings.Compare(a.ID(), b.ID()))
+	})
+
+	ranks := make(map[string]serverRank, len(sources))
+	m.mu.RLock()
+	for i, src := range sources {
+		rank := serverRank{order: i}
+		for _, acct := range m.accounts {
+			for _, srv := range acct.Servers {
+				if srv.ID == src.ID() {
+					rank.reachable = srv.Reachable
+					rank.local = isLocalURL(srv.URL)
+				}
+			}
+		}
+		ranks[src.ID()] = rank
+	}
+	m.mu.RUnlock()
+	return sources, ranks
+}
+
+// MergedLibrary returns the items of every enabled library of a section
+// type across the enabled sources, de-duplicated and sorted by title. An
+// error is returned only if no source could be listed.
+func (m *Manager) MergedLibrary(ctx context.Context, sectionType string) ([]MergedItem, error) {
+	sources, ranks := m.mergeSources()
+
+	results := make([][]ItemCopy, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+			results[i], errs[i] = libraryCopies(ctx, src, sectionType)
+		}(i, src)
+	}
+	wg.Wait()
+
+	var copies []ItemCopy
+	failed := 0
+	for i, src := range sources {
+		if errs[i] != nil {
+			slog.Warn("failed to fetch library content", "source", src.Name(), "type", sectionType, "error", errs[i])
+			failed++
+			continue
+		}
+		copies = append(copies, results[i]...)
+	}
+	if failed > 0 && failed == len(sources) {
+		return nil, fmt.Errorf("no %s library could be fetched: %w", sectionType, errs[0])
+	}
+
+	merged := mergeItems(copies, ranks)
+	slices.SortStableFunc(merged, func(a, b MergedItem) int {
+		return strings.Compare(sortTitle(&a.Best().Item), sortTitle(&b.Best().Item))
+	})
+	return merged, nil
+}
+
+// libraryCopies returns the items of all sections of a type on src.
+func libraryCopies(ctx context.Context, src Source, sectionType string) ([]ItemCopy, error) {
+	sections, err := src.LibrarySections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var copies []ItemCopy
+	for _, sec := range sections {
+		if sec.Type != sectionType {
+			continue
+		}
+		items, _, err := src.LibraryContent(ctx, sec.Key, &ContentOptions{Sort: SortTitle, IncludeGUIDs: true})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			copies = append(copies, ItemCopy{ServerID: src.ID(), Item: item})
+		}
+	}
+	return copies, nil
+}
+
+// MergedHomeHubs returns the home hubs of the enabled sources, with hubs
+// of the same kind combined and their items de-duplicated. Each merged hub
+// holds at most count items.
+func (m *Manager) MergedHomeHubs(ctx context.Context, count int) []MergedHub {
+	sources, ranks := m.mergeSources()
+
+	results := make([][]Hub, len(sources))
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+			hubs, err := src.HomeHubs(ctx, count)
+			if err != nil {
+				slog.Error("failed to fetch home hubs", "source", src.Name(), "error", err)
+				return
+			}
+			results[i] = hubs
+		}(i, src)
+	}
+	wg.Wait()
+
+	// Group the hubs in order of first appearance. Hubs without a kind are
+	// matched by title, which servers share for their built-in hubs.
+	var merged []MergedHub
+	var items [][][]ItemCopy // merged hub → server → items
+	byKey := make(map[string]int)
+	for i, src := range sources {
+		for _, hub := range results[i] {
+			key := hub.Kind
+			if key == "" {
+				key = hub.Type + "/" + hub.Title
+			}
+			idx, ok := byKey[key]
+			if !ok {
+				idx = len(merged)
+				byKey[key] = idx
+				merged = append(merged, MergedHub{Title: hub.Title, Type: hub.Type, Kind: hub.Kind})
+				items = append(items, nil)
+			}
+			merged[idx].Sources = append(merged[idx].Sources, HubSource{ServerID: src.ID(), Key: hub.Key, More: hub.More})
+
+			copies := make([]ItemCopy, len(hub.Items))
+			for j, item := range hub.Items {
+				copies[j] = ItemCopy{ServerID: src.ID(), Item: item}
+			}
+			items[idx] = append(items[idx], copies)
+		}
+	}
+
+	for i := range merged {
+		hubItems := mergeItems(interleave(items[i]), ranks)
+		if count > 0 && len(hubItems) > count {
+			hubItems = hubItems[:count]
+		}
+		merged[i].Items = hubItems
+	}
+	return merged
+}
+
+// interleave takes items from each list in turn, so that every server is
+// represented near the start of a merged hub.
+func interleave(lists [][]ItemCopy) []ItemCopy {
+	var out []ItemCopy
+	for i := 0; ; i++ {
+		added := false
+		for _, list := range lists {
+			if i < len(list) {
+				out = append(out, list[i])
+				added = true
+			}
+		}
+		if !added {
+			return out
+		}
+	}
+}
+
+// mergeItems groups the copies of the same item, keeping the order in
+// which items first appear. Copies match when they have the same type and
+// share a GUID.
+func mergeItems(copies []ItemCopy, ranks map[string]serverRank) []MergedItem {
+	var merged []MergedItem
+	byKey := make(map[string]int)
+	for _, c := range copies {
+		keys := dedupKeys(&c.Item)
+		idx := -1
+		for _, key := range keys {
+			if i, ok := byKey[key]; ok {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			idx = len(merged)
+			merged = append(merged, MergedItem{})
+		}
+		merged[idx].Copies = append(merged[idx].Copies, c)
+		for _, key := range keys {
+			if _, ok := byKey[key]; !ok {
+				byKey[key] = idx
+			}
+		}
+	}
+
+	for i := range merged {
+		slices.SortStableFunc(merged[i].Copies, func(a, b ItemCopy) int {
+			return compareCopies(a, b, ranks)
+		})
+	}
+	return merged
+}
+
+// dedupKeys returns the keys an item is matched across servers by.
+func dedupKeys(item *Item) []string {
+	guids := matchableGUIDs(item)
+	keys := make([]string, len(guids))
+	for i, guid := range guids {
+		keys[i] = item.Type + "/" + guid
+	}
+	return keys
+}
+
+// matchableGUIDs returns the GUIDs of an item that identify it across
+// servers. GUIDs that are only unique within a server, such as those of
+// unmatched items, are left out.
+func matchableGUIDs(item *Item) []string {
+	guids := make([]string, 0, len(item.ExternalIDs)+1)
+	for _, guid := range append([]string{item.GUID}, item.ExternalIDs...) {
+		if guid == "" || strings.HasPrefix(guid, "local://") || strings.HasPrefix(guid, "com.plexapp.agents.none://") {
+			continue
+		}
+		guids = append(guids, guid)
+	}
+	return guids
+}
+
+// compareCopies orders copies best first: on a reachable server, with the
+// highest resolution, on a server connected to locally, with the most
+// episodes, and finally by server.
+func compareCopies(a, b ItemCopy, ranks map[string]serverRank) int {
+	ra, rb := ranks[a.ServerID], ranks[b.ServerID]
+	return cmp.Or(
+		compareBool(ra.reachable, rb.reachable),
+		cmp.Compare(resolution(&b.Item), resolution(&a.Item)),
+		compareBool(ra.local, rb.local),
+		cmp.Compare(b.Item.LeafCount, a.Item.LeafCount),
+		cmp.Compare(ra.order, rb.order),
+	)
+}
+
+// compareBool orders true before false.
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// resolution returns the vertical resolution of the best version of an
+// item, or 0 if unknown.
+func resolution(item *Item) int {
+	best := 0
+	for _, media := range item.Media {
+		height := media.Height
+		if height == 0 {
+			switch strings.ToLower(media.VideoResolution) {
+			case "4k":
+				height = 2160
+			case "sd":
+				height = 480
+			default:
+				height, _ = strconv.Atoi(media.VideoResolution)
+			}
+		}
+		best = max(best, height)
+	}
+	return best
+}
+
+// sortTitle returns the key an item is sorted by title with.
+func sortTitle(item *Item) string {
+	if item.TitleSort != "" {
+		return strings.ToLower(item.TitleSort)
+	}
+	return strings.ToLower(item.Title)
+}
@@ -11,7 +11,15 @@ import (
 // ProviderType identifies the type of media provider.
 type ProviderType string
 
-const ProviderPlex ProviderType = "plex"
+const (
+	ProviderPlex     ProviderType = "plex"
+	ProviderJellyfin ProviderType = "jellyfin"
+	ProviderEmby     ProviderType = "emby"
+
+	// ProviderPlexServer is a single Plex server added by URL, without a
+	// plex.tv account.
+	ProviderPlexServer ProviderType = "plex_server"
+)
 
 // Account represents a user account for a media provider.
 type Account struct {
@@ -20,6 +28,14 @@ type Account struct {
 	Username string       `json:"username"`
 	ClientID string       `json:"client_id"`
 	Servers  []*Server    `json:"servers"`
+
+	// ProfileUUID and ProfileTitle identify the Plex Home user the account
+	// is switched to. Both are empty while the account owner is active.
+	ProfileUUID  string `json:"profile_uuid,omitempty"`
+	ProfileTitle string `json:"profile_title,omitempty"`
+
+	// UserID is the ID of the Jellyfin user on the account's server.
+	UserID string `json:"user_id,omitempty"`
 }
 
 // Server represents a media server associated with an account.
@@ -27,9 +43,46 @@ type Server struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
 	Enabled     bool   `json:"enabled"`
+	Owned       bool   `json:"owned"`
 	URL         string `json:"url"`
 	AccessToken string `json:"-"`
 	Reachable   bool   `json:"-"` // in-memory only; not persisted
+
+	// SharedBy is the username of the friend sharing the server; empty for
+	// owned servers. Home marks servers shared through a Plex Home.
+	SharedBy string `json:"shared_by,omitempty"`
+	Home     bool   `json:"home,omitempty"`
+
+	// Connections are the URLs of the server ranked by preference,
+	// reachable ones first; URL is the one in use.
+	Connections []string `json:"connections,omitempty"`
+	ConnectionPrefs
+
+	// Libraries is the allowlist of enabled library section keys. Nil
+	// enables every library.
+	Libraries []string `json:"libraries"`
+
+	// UserID is the ID of the Emby user on the server. Emby Connect
+	// accounts sign in as a different local user on each server.
+	UserID string `json:"user_id,omitempty"`
+}
+
+// ConnectionPrefs are the user's preferences for connecting to a Plex
+// server.
+type ConnectionPrefs struct {
+	// CustomURLs are addresses plex.tv does not advertise, such as a
+	// reverse proxy or a VPN host name. For servers added by address they
+	// are the only connections.
+	CustomURLs []string `json:"custom_urls,omitempty"`
+
+	// PreferredURL is used whenever it is reachable.
+	PreferredURL string `json:"preferred_url,omitempty"`
+
+	// DisableRelay excludes connections through the Plex relay.
+	DisableRelay bool `json:"disable_relay,omitempty"`
+
+	// RequireHTTPS excludes unencrypted connections.
+	RequireHTTPS bool `json:"require_https,omitempty"`
 }
 
 type sourcesConfig struct {
@@ -6,13 +6,27 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/0skillallluck/scanline/provider/plex/auth"
+	"github.com/0skillallluck/scanline/provider/plex/gdm"
 )
 
-func connectionPenalty(c auth.ResourceConnection) int {
+// connectionPenalty ranks a connection; lower is better. The preferred
+// connection ranks first, followed by local connections to an address the
+// server answered GDM discovery from.
+func connectionPenalty(c auth.ResourceConnection, lanAddresses []string, prefs ConnectionPrefs) int {
+	if prefs.PreferredURL != "" && c.URI == prefs.PreferredURL {
+		return -2
+	}
+	if c.Local && slices.Contains(lanAddresses, net.JoinHostPort(c.Address, strconv.Itoa(c.Port))) {
+		return -1
+	}
 	if c.Relay {
 		return 4
 	}
@@ -30,6 +44,123 @@ func connectionPenalty(c auth.ResourceConnection) int {
 
 const connectionTimeout = 3 * time.Second
 
+// connectionCandidates returns the connections allowed by prefs. Custom
+// URLs are added ahead of the advertised connections, so that they rank
+// before remote connections of the same penalty.
+func connectionCandidates(connections []auth.ResourceConnection, prefs ConnectionPrefs) []auth.ResourceConnection {
+	candidates := make([]auth.ResourceConnection, 0, len(prefs.CustomURLs)+len(connections))
+	for _, uri := range prefs.CustomURLs {
+		candidates = append(candidates, customConnection(uri))
+	}
+	for _, conn := range connections {
+		if !slices.Contains(prefs.CustomURLs, conn.URI) {
+			candidates = append(candidates, conn)
+		}
+	}
+	return slices.DeleteFunc(candidates, func(c auth.ResourceConnection) bool {
+		return (prefs.DisableRelay && c.Relay) || (prefs.RequireHTTPS && c.Protocol != "https")
+	})
+}
+
+// customConnection describes a URL entered by the user as a remote
+// connection.
+func customConnection(uri string) auth.ResourceConnection {
+	conn := auth.ResourceConnection{URI: uri}
+	if u, err := url.Parse(uri); err == nil {
+		conn.Protocol = u.Scheme
+		conn.Address = u.Hostname()
+		conn.Port, _ = strconv.Atoi(u.Port())
+	}
+	return conn
+}
+
+// isLocalURL reports whether a server URL points into the local network.
+// plex.direct host names embed the address of the server, e.g.
+// "192-168-1-2.<hash>.plex.direct".
+func isLocalURL(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if strings.HasSuffix(host, ".plex.direct") {
+		label, _, _ := strings.Cut(host, ".")
+		host = strings.ReplaceAll(label, "-", ".")
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host == "localhost" || strings.HasSuffix(host, ".local")
+	}
+	return ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast()
+}
+
+const (
+	// lanDiscoveryWait is how long GDM discovery waits for servers to
+	// answer.
+	lanDiscoveryWait = time.Second
+
+	// lanDiscoveryTTL is how long the result of a GDM discovery is used
+	// before discovering again.
+	lanDiscoveryTTL = 10 * time.Minute
+)
+
+// lanCache holds the result of the last GDM discovery.
+type lanCache struct {
+	mu        sync.Mutex
+	addresses map[string][]string // nil until discovered
+	at        time.Time
+	pending   func() map[string][]string
+}
+
+// discoverLAN returns a function that returns the addresses ("host:port")
+// servers answered GDM discovery from, keyed by machine identifier.
+//
+// The last result is reused for lanDiscoveryTTL, and after that while a new
+// discovery runs in the background, so that only the first discovery, and
+// the first after a network change, is waited for.
+func (m *Manager) discoverLAN(ctx context.Context) func() map[string][]string {
+	c := &m.lan
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending == nil && (c.addresses == nil || time.Since(c.at) > lanDiscoveryTTL) {
+		c.pending = sync.OnceValue(func() map[string][]string {
+			addresses := discoverLANAddresses(ctx)
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			c.pending = nil
+			if ctx.Err() == nil {
+				c.addresses, c.at = addresses, time.Now()
+			}
+			return addresses
+		})
+		go c.pending()
+	}
+	if addresses := c.addresses; addresses != nil {
+		return func() map[string][]string { return addresses }
+	}
+	return c.pending
+}
+
+// forget drops the last result, as it was found on another network.
+func (c *lanCache) forget() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.addresses = nil
+}
+
+// discoverLANAddresses runs GDM discovery.
+func discoverLANAddresses(ctx context.Context) map[string][]string {
+	resources, err := gdm.Discover(ctx, lanDiscoveryWait)
+	if err != nil {
+		slog.Debug("plex: LAN discovery failed", "error", err)
+	}
+	addresses := make(map[string][]string)
+	for _, r := range resources {
+		addresses[r.ResourceIdentifier] = append(addresses[r.ResourceIdentifier], r.Address())
+	}
+	return addresses
+}
+
 func newConnectionClient() *http.Client {
 	return &http.Client{
 		Timeout: connectionTimeout,
@@ -42,60 +173,77 @@ func newConnectionClient() *http.Client {
 	}
 }
 
-// connResult holds the outcome of a single connection probe.
-type connResult struct {
-	uri     string
-	penalty int
-	ok      bool
+// rankConnections orders the connections of a server allowed by prefs and
+// probes them. It returns the URIs of all of them, reachable ones first,
+// so that the list can be failed over in order. An error is returned when
+// no connection is reachable.
+func rankConnections(ctx context.Context, httpClient *http.Client, connections []auth.ResourceConnection, lanAddresses []string, prefs ConnectionPrefs, token, clientIdentifier string) ([]string, error) {
+	sorted := connectionCandidates(connections, prefs)
+	slices.SortStableFunc(sorted, func(a, b auth.ResourceConnection) int {
+		return connectionPenalty(a, lanAddresses, prefs) - connectionPenalty(b, lanAddresses, prefs)
+	})
+	uris := make([]string, len(sorted))
+	for i, conn := range sorted {
+		uris[i] = conn.URI
+	}
+
+	reachable := probeConnections(ctx, httpClient, uris, token, clientIdentifier)
+	ranked := make([]string, 0, len(uris))
+	for i, uri := range uris {
+		if reachable[i] {
+			ranked = append(ranked, uri)
+		}
+	}
+	if len(ranked) == 0 {
+		return uris, fmt.Errorf("no reachable connection found")
+	}
+	for i, uri := range uris {
+		if !reachable[i] {
+			ranked = append(ranked, uri)
+		}
+	}
+
+	slog.Debug("plex: connection selected", "uri", ranked[0], "reachable", len(ranked))
+	return ranked, nil
 }
 
-func findBestConnection(ctx context.Context, httpClient *http.Client, connections []auth.ResourceConnection, token, clientIdentifier string) (string, error) {
+// probeConnections checks in parallel which server URIs answer, reporting
+// the result for each URI.
+func probeConnections(ctx context.Context, httpClient *http.Client, uris []string, token, clientIdentifier string) []bool {
 	ctx, cancel := context.WithTimeout(ctx, connectionTimeout)
 	defer cancel()
 
-	results := make([]connResult, len(connections))
+	reachable := make([]bool, len(uris))
 	var wg sync.WaitGroup
-	for i, conn := range connections {
+	for i, uri := range uris {
 		wg.Add(1)
-		go func(i int, conn auth.ResourceConnection) {
+		go func(i int, uri string) {
 			defer wg.Done()
-			results[i] = connResult{uri: conn.URI, penalty: connectionPenalty(conn)}
 
-			req, err := http.NewRequestWithContext(ctx, http.MethodGet, conn.URI+"/identity", nil)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri+"/identity", nil)
 			if err != nil {
-				slog.Debug("plex: connection attempt failed to create request", "uri", conn.URI, "error", err)
+				slog.Debug("plex: connection attempt failed to create request", "uri", uri, "error", err)
 				return
 			}
-			req.Header.Set("X-Plex-Token", token)
+			if token != "" {
+				req.Header.Set("X-Plex-Token", token)
+			}
 			req.Header.Set("X-Plex-Client-Identifier", clientIdentifier)
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				slog.Debug("plex: connection attempt failed", "uri", conn.URI, "local", conn.Local, "relay", conn.Relay, "ipv6", conn.IPv6, "error", err)
+				slog.Debug("plex: connection attempt failed", "uri", uri, "error", err)
 				return
 			}
 			_ = resp.Body.Close()
 			if resp.StatusCode == http.StatusOK {
-				slog.Debug("plex: connection reachable", "uri", conn.URI, "local", conn.Local, "relay", conn.Relay, "ipv6", conn.IPv6)
-				results[i].ok = true
+				slog.Debug("plex: connection reachable", "uri", uri)
+				reachable[i] = true
 			} else {
-				slog.Debug("plex: connection attempt rejected", "uri", conn.URI, "local", conn.Local, "relay", conn.Relay, "ipv6", conn.IPv6, "status", resp.StatusCode)
+				slog.Debug("plex: connection attempt rejected", "uri", uri, "status", resp.StatusCode)
 			}
-		}(i, conn)
+		}(i, uri)
 	}
 	wg.Wait()
-
-	// Pick the reachable connection with the lowest penalty.
-	best := -1
-	for i, r := range results {
-		if r.ok && (best == -1 || r.penalty < results[best].penalty) {
-			best = i
-		}
-	}
-	if best == -1 {
-		return "", fmt.Errorf("no reachable connection found")
-	}
-
-	slog.Debug("plex: connection selected", "uri", results[best].uri)
-	return results[best].uri, nil
+	return reachable
 }
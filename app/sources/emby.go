@@ -0,0 +1,25 @@
+package sources
+
+import (
+	"github.com/0skillallluck/scanline/provider/emby"
+)
+
+// EmbySource adapts an emby.Client to the Source interface.
+type EmbySource struct {
+	*mediaBrowserSource
+	client *emby.Client
+}
+
+// NewEmbySource creates a Source that wraps an Emby server client.
+func NewEmbySource(serverID, name string, client *emby.Client) *EmbySource {
+	return &EmbySource{
+		mediaBrowserSource: newMediaBrowserSource(serverID, name, client),
+		client:             client,
+	}
+}
+
+// EmbyClient returns the underlying emby.Client for provider-specific
+// operations. Avoid using this for general media access.
+func (s *EmbySource) EmbyClient() *emby.Client {
+	return s.client
+}
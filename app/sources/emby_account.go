@@ -0,0 +1,104 @@
+package sources
+
+import (
+	"log/slog"
+
+	"github.com/0skillallluck/scanline/app/secrets"
+	"github.com/0skillallluck/scanline/provider/emby"
+	"github.com/google/uuid"
+)
+
+// embyTokenKey returns the keyring key for the access token of an Emby
+// account. For Emby Connect accounts this is the Connect user token.
+func embyTokenKey(accountID string) string {
+	return "emby_token_" + accountID
+}
+
+// embyServerTokenKey returns the keyring key for the server access token
+// an Emby Connect account exchanged for a server.
+func embyServerTokenKey(accountID, serverID string) string {
+	return "emby_server_token_" + accountID + "_" + serverID
+}
+
+// EmbyConnectServer is a server of an Emby Connect account, resolved to a
+// reachable address and signed in with an exchanged token.
+type EmbyConnectServer struct {
+	Server   emby.ConnectServer
+	URL      string
+	Exchange *emby.ConnectExchange
+}
+
+// AddEmbyAccount adds an Emby account signed in directly to the server at
+// serverURL. Like Jellyfin accounts, it belongs to a single server, which
+// is enabled right away.
+func (m *Manager) AddEmbyAccount(serverURL, clientID string, info *emby.PublicSystemInfo, result *emby.AuthenticationResult) {
+	accountID := uuid.New().String()
+
+	// Keyring I/O outside lock
+	secrets.SetToken(embyTokenKey(accountID), result.AccessToken)
+
+	srv := &Server{
+		ID:        info.ID,
+		Name:      info.ServerName,
+		Enabled:   true,
+		Owned:     result.User.Policy.IsAdministrator,
+		URL:       serverURL,
+		Reachable: true,
+		UserID:    result.User.ID,
+	}
+	acct := &Account{
+		ID:       accountID,
+		Type:     ProviderEmby,
+		Username: result.User.Name,
+		ClientID: clientID,
+		Servers:  []*Server{srv},
+	}
+	slog.Debug("emby: adding account", "server", srv.Name, "username", acct.Username)
+
+	m.addAccount(acct, result.AccessToken)
+}
+
+// AddEmbyConnectAccount adds an Emby Connect account with the servers
+// linked to it. All servers are enabled.
+func (m *Manager) AddEmbyConnectAccount(clientID string, result *emby.ConnectAuthenticationResult, servers []EmbyConnectServer) {
+	accountID := uuid.New().String()
+
+	// Keyring I/O outside lock
+	secrets.SetToken(embyTokenKey(accountID), result.AccessToken)
+
+	acct := &Account{
+		ID:       accountID,
+		Type:     ProviderEmby,
+		Username: result.User.Name,
+		ClientID: clientID,
+	}
+	for _, s := range servers {
+		secrets.SetToken(embyServerTokenKey(accountID, s.Server.SystemID), s.Exchange.AccessToken)
+		acct.Servers = append(acct.Servers, &Server{
+			ID:          s.Server.SystemID,
+			Name:        s.Server.Name,
+			Enabled:     true,
+			URL:         s.URL,
+			AccessToken: s.Exchange.AccessToken,
+			Reachable:   true,
+			UserID:      s.Exchange.LocalUserID,
+		})
+	}
+	slog.Debug("emby: adding connect account", "username", acct.Username, "servers", len(acct.Servers))
+
+	m.addAccount(acct, result.AccessToken)
+}
+
+// addAccount adds an account whose servers are all enabled and creates
+// their sources.
+func (m *Manager) addAccount(acct *Account, token string) {
+	m.mu.Lock()
+	m.accounts = append(m.accounts, acct)
+	for _, srv := range acct.Servers {
+		m.sources[srv.ID] = m.newAccountSource(acct, srv, token)
+	}
+	saveConfig(m.accounts)
+	m.mu.Unlock()
+
+	m.SourcesChanged.Notify(struct{}{})
+}
@@ -0,0 +1,321 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/0skillallluck/scanline/provider/plex"
+)
+
+const (
+	// reprobeInterval is how often the connections of servers are probed
+	// again in the background.
+	reprobeInterval = 5 * time.Minute
+
+	// networkSettleDelay is how long to wait after a network change before
+	// probing, as several changes are usually reported at once.
+	networkSettleDelay = 2 * time.Second
+
+	// responseHeaderTimeout bounds how long a server that accepted a
+	// request may take to answer it. Slow answers fail the request but
+	// are not failed over, as the connection itself works.
+	responseHeaderTimeout = 30 * time.Second
+)
+
+// failoverTransport executes the requests of all connection failovers.
+// Its dial timeout is short so that a connection that stopped answering
+// is failed over quickly.
+var failoverTransport = &http.Transport{
+	Proxy: http.ProxyFromEnvironment,
+	DialContext: (&net.Dialer{
+		Timeout:   connectionTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext,
+	MaxIdleConns:          100,
+	MaxIdleConnsPerHost:   10,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   connectionTimeout,
+	ResponseHeaderTimeout: responseHeaderTimeout,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+// connectionChangeFunc is called when a server switches to another
+// connection or its reachability changes.
+type connectionChangeFunc func(serverID, url string, reachable bool)
+
+// connectionFailover routes the requests of a Plex source to the current
+// connection of its server. When a connection stops answering, requests
+// are retried on the next connection in the ranked list, which becomes
+// the current one.
+type connectionFailover struct {
+	serverID string
+	client   *plex.Client
+	onChange connectionChangeFunc
+
+	mu          sync.Mutex
+	connections []string
+	current     int
+	reachable   bool
+}
+
+// newConnectionFailover creates a failover over the ranked connections of
+// a server and installs it in client.
+func newConnectionFailover(srv *Server, client *plex.Client, onChange connectionChangeFunc) *connectionFailover {
+	connections := srv.Connections
+	if !slices.Contains(connections, srv.URL) {
+		connections = append([]string{srv.URL}, connections...)
+	}
+	f := &connectionFailover{
+		serverID:    srv.ID,
+		client:      client,
+		onChange:    onChange,
+		connections: connections,
+		current:     slices.Index(connections, srv.URL),
+		reachable:   srv.Reachable,
+	}
+	client.SetHTTPClient(&http.Client{Transport: f})
+	return f
+}
+
+// RoundTrip sends the request to the current connection, failing over to
+// the other connections on connection-level errors.
+func (f *connectionFailover) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	connections, start := f.connections, f.current
+	f.mu.Unlock()
+
+	var lastErr error
+	for i := range connections {
+		idx := (start + i) % len(connections)
+		attempt, err := retarget(req, connections[idx], i > 0)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := failoverTransport.RoundTrip(attempt)
+		if err == nil {
+			f.use(idx, true)
+			return resp, nil
+		}
+		if !isConnectionError(req.Context(), err) || !replayable(req) {
+			return nil, err
+		}
+		slog.Debug("plex: connection failed, failing over", "server", f.serverID, "uri", connections[idx], "error", err)
+		lastErr = err
+	}
+	f.use(start, false)
+	return nil, lastErr
+}
+
+// isReachable reports whether the server answered on the current
+// connection.
+func (f *connectionFailover) isReachable() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.reachable
+}
+
+// reprobe probes all connections and switches to the most preferred one
+// that answers.
+func (f *connectionFailover) reprobe(ctx context.Context, httpClient *http.Client) {
+	f.mu.Lock()
+	connections := f.connections
+	f.mu.Unlock()
+
+	reachable := probeConnections(ctx, httpClient, connections, f.client.Token(), f.client.ClientID())
+	if ctx.Err() != nil {
+		return
+	}
+	for i, ok := range reachable {
+		if ok {
+			f.use(i, true)
+			return
+		}
+	}
+	f.mu.Lock()
+	current := f.current
+	f.mu.Unlock()
+	f.use(current, false)
+}
+
+// use makes the connection at idx current and records whether the server
+// is reachable, reporting changes.
+func (f *connectionFailover) use(idx int, reachable bool) {
+	f.mu.Lock()
+	changed := f.current != idx || f.reachable != reachable
+	f.current, f.reachable = idx, reachable
+	uri := f.connections[idx]
+	f.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	if reachable {
+		slog.Info("plex: using connection", "server", f.serverID, "uri", uri)
+	} else {
+		slog.Warn("plex: server unreachable", "server", f.serverID)
+	}
+	f.client.SetServerURL(uri)
+	if f.onChange != nil {
+		f.onChange(f.serverID, uri, reachable)
+	}
+}
+
+// retarget returns a copy of req sent to the server at uri. The body is
+// rewound for retries.
+func retarget(req *http.Request, uri string, retry bool) (*http.Request, error) {
+	target, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	out := req.Clone(req.Context())
+	out.URL.Scheme = target.Scheme
+	out.URL.Host = target.Host
+	out.Host = ""
+	if retry && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		out.Body = body
+	}
+	return out, nil
+}
+
+// replayable reports whether req can be sent again.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// isConnectionError reports whether err means the server could not be
+// reached: dialing failed, or the connection was refused, reset or its
+// network is unreachable. Canceled requests, TLS and certificate errors and
+// servers slow to answer are not, as another connection would not help.
+func isConnectionError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EHOSTUNREACH) ||
+		errors.Is(err, syscall.ENETUNREACH)
+}
+
+// connectionChanged records the connection a server switched to and
+// whether it is reachable.
+func (m *Manager) connectionChanged(serverID, url string, reachable bool) {
+	m.mu.Lock()
+	changed := false
+	for _, acct := range m.accounts {
+		for _, srv := range acct.Servers {
+			if srv.ID == serverID && (srv.URL != url || srv.Reachable != reachable) {
+				srv.URL, srv.Reachable = url, reachable
+				changed = true
+			}
+		}
+	}
+	if changed {
+		saveConfig(m.accounts)
+	}
+	m.mu.Unlock()
+
+	if changed {
+		m.SourcesChanged.Notify(struct{}{})
+	}
+}
+
+// StartConnectionMonitor probes the connections of Plex servers in the
+// background, periodically and after network changes, until ctx is done.
+func (m *Manager) StartConnectionMonitor(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(reprobeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			case <-m.networkChanged:
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(networkSettleDelay):
+				}
+				// Changes reported while settling are covered by this probe.
+				select {
+				case <-m.networkChanged:
+				default:
+				}
+			}
+			m.ReprobeConnections(ctx)
+		}
+	}()
+}
+
+// NetworkChanged asks the connection monitor to probe the connections of
+// Plex servers, as the network the computer is on has changed.
+func (m *Manager) NetworkChanged() {
+	m.lan.forget()
+	select {
+	case m.networkChanged <- struct{}{}:
+	default:
+	}
+}
+
+// ReprobeConnections probes the connections of the enabled Plex servers,
+// switching each to its most preferred reachable connection. Servers that
+// were unreachable when discovered, or are still offline, are resolved
+// again from plex.tv.
+func (m *Manager) ReprobeConnections(ctx context.Context) {
+	m.mu.RLock()
+	var failovers []*connectionFailover
+	for _, src := range m.sources {
+		if ps, ok := unwrapSource(src).(*PlexSource); ok && ps.failover != nil {
+			failovers = append(failovers, ps.failover)
+		}
+	}
+	unresolved := false
+	for _, acct := range m.accounts {
+		if acct.Type != ProviderPlex && acct.Type != ProviderPlexServer {
+			continue
+		}
+		for _, srv := range acct.Servers {
+			if _, ok := m.sources[srv.ID]; srv.Enabled && !ok {
+				unresolved = true
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	slog.Debug("plex: re-probing connections", "server_count", len(failovers))
+	connClient := newConnectionClient()
+	var wg sync.WaitGroup
+	for _, f := range failovers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.reprobe(ctx, connClient)
+		}()
+	}
+	wg.Wait()
+
+	for _, f := range failovers {
+		if !f.isReachable() {
+			unresolved = true
+		}
+	}
+	if unresolved && ctx.Err() == nil {
+		m.RefreshServers(ctx)
+	}
+}
@@ -0,0 +1,261 @@
+package sources
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// guidIndexPageSize is the number of items fetched per request when
+// building an index.
+const guidIndexPageSize = 500
+
+// GUIDMatch is a library item found for a GUID.
+type GUIDMatch struct {
+	ServerID string
+	ItemID   ItemID
+	Type     string // "movie" or "show"
+}
+
+// guidIndex maps the GUIDs of a source's movies and shows, both the Plex
+// GUID and external ones such as "imdb://tt0133093", to library items.
+//
+// Sections are indexed in bulk the first time and then refreshed
+// incrementally: a section whose ContentChangedAt is unchanged is skipped,
+// and otherwise only items updated since the last refresh are fetched.
+// Deletions are not reported, so a section whose size does not add up to
+// the indexed items plus the new ones is rebuilt.
+//
+// Refreshes fetch from the server without holding mu, so that lookups are
+// not held up by them; refreshMu keeps them from running concurrently.
+type guidIndex struct {
+	refreshMu sync.Mutex
+	sections  map[string]*indexedSection // section key → state
+
+	mu     sync.Mutex
+	byGUID map[string]GUIDMatch
+}
+
+type indexedSection struct {
+	contentChangedAt int64
+	updatedAt        int64 // newest item UpdatedAt seen
+	size             int
+	guids            map[ItemID][]string // item ID → GUIDs
+}
+
+func newGUIDIndex() *guidIndex {
+	return &guidIndex{
+		sections: make(map[string]*indexedSection),
+		byGUID:   make(map[string]GUIDMatch),
+	}
+}
+
+// refresh brings the index up to date with the source's movie and show
+// sections.
+func (idx *guidIndex) refresh(ctx context.Context, src Source) error {
+	idx.refreshMu.Lock()
+	defer idx.refreshMu.Unlock()
+
+	sections, err := src.LibrarySections(ctx)
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]bool)
+	for _, sec := range sections {
+		if sec.Type != "movie" && sec.Type != "show" {
+			continue
+		}
+		current[sec.Key] = true
+
+		state := idx.sections[sec.Key]
+		if state != nil && sec.ContentChangedAt != 0 && state.contentChangedAt == sec.ContentChangedAt {
+			continue
+		}
+		if err := idx.refreshSection(ctx, src, sec); err != nil {
+			slog.Warn("failed to index library section", "source", src.Name(), "section", sec.Key, "error", err)
+		}
+	}
+
+	// Drop sections that were removed from the server.
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for key, state := range idx.sections {
+		if !current[key] {
+			idx.dropSection(state)
+			delete(idx.sections, key)
+		}
+	}
+	return nil
+}
+
+// refreshSection indexes a section, incrementally if it was indexed before.
+// The caller holds idx.refreshMu.
+func (idx *guidIndex) refreshSection(ctx context.Context, src Source, sec LibrarySection) error {
+	if state := idx.sections[sec.Key]; state != nil {
+		items, err := fetchSectionItems(ctx, src, sec.Key, state.updatedAt)
+		if err != nil {
+			return err
+		}
+		_, size, err := src.LibraryContent(ctx, sec.Key, &ContentOptions{Size: 1})
+		if err != nil {
+			return err
+		}
+
+		added := 0
+		for _, item := range items {
+			if _, ok := state.guids[item.ID]; !ok {
+				added++
+			}
+		}
+		if size == state.size+added {
+			idx.mu.Lock()
+			idx.addItems(src, state, items)
+			state.size = size
+			state.contentChangedAt = sec.ContentChangedAt
+			idx.mu.Unlock()
+			return nil
+		}
+		// Items were deleted; rebuild the section.
+	}
+
+	items, err := fetchSectionItems(ctx, src, sec.Key, 0)
+	if err != nil {
+		return err
+	}
+	state := &indexedSection{
+		contentChangedAt: sec.ContentChangedAt,
+		size:             len(items),
+		guids:            make(map[ItemID][]string),
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if old := idx.sections[sec.Key]; old != nil {
+		idx.dropSection(old)
+	}
+	idx.sections[sec.Key] = state
+	idx.addItems(src, state, items)
+	return nil
+}
+
+// fetchSectionItems fetches the items of a section with their GUIDs, only
+// those updated since the given time if not zero.
+func fetchSectionItems(ctx context.Context, src Source, sectionKey string, updatedSince int64) ([]Item, error) {
+	opts := &ContentOptions{IncludeGUIDs: true, Size: guidIndexPageSize, UpdatedSince: updatedSince}
+	var items []Item
+	for {
+		page, total, err := src.LibraryContent(ctx, sectionKey, opts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if len(page) < guidIndexPageSize || len(items) >= total {
+			return items, nil
+		}
+		opts.Start += len(page)
+	}
+}
+
+// addItems indexes items of a section, replacing their previous entries.
+// The caller holds idx.mu.
+func (idx *guidIndex) addItems(src Source, state *indexedSection, items []Item) {
+	for i := range items {
+		item := &items[i]
+		guids := make([]string, 0, len(item.ExternalIDs)+1)
+		if item.GUID != "" {
+			guids = append(guids, item.GUID)
+		}
+		guids = append(guids, item.ExternalIDs...)
+
+		idx.removeGUIDs(item.ID, state.guids[item.ID])
+		state.guids[item.ID] = guids
+		for _, guid := range guids {
+			idx.byGUID[guid] = GUIDMatch{ServerID: src.ID(), ItemID: item.ID, Type: item.Type}
+		}
+		state.updatedAt = max(state.updatedAt, item.UpdatedAt)
+	}
+}
+
+// dropSection removes all entries of a section. The caller holds idx.mu.
+func (idx *guidIndex) dropSection(state *indexedSection) {
+	for id, guids := range state.guids {
+		idx.removeGUIDs(id, guids)
+	}
+}
+
+// removeGUIDs removes the entries of an item. Entries that now point at
+// another item are kept. The caller holds idx.mu.
+func (idx *guidIndex) removeGUIDs(id ItemID, guids []string) {
+	for _, guid := range guids {
+		if idx.byGUID[guid].ItemID == id {
+			delete(idx.byGUID, guid)
+		}
+	}
+}
+
+// lookup returns the item indexed for any of the given GUIDs.
+func (idx *guidIndex) lookup(guids ...string) (GUIDMatch, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, guid := range guids {
+		if m, ok := idx.byGUID[guid]; ok {
+			return m, true
+		}
+	}
+	return GUIDMatch{}, false
+}
+
+// guidIndexFor returns the index of a source, creating it if needed.
+func (m *Manager) guidIndexFor(serverID string) *guidIndex {
+	m.indexMu.Lock()
+	defer m.indexMu.Unlock()
+	idx, ok := m.guidIndexes[serverID]
+	if !ok {
+		idx = newGUIDIndex()
+		m.guidIndexes[serverID] = idx
+	}
+	return idx
+}
+
+// refreshGUIDIndexes refreshes the GUID indexes of all enabled sources
+// concurrently and returns the sources whose index is usable, in the order
+// of merged libraries.
+func (m *Manager) refreshGUIDIndexes(ctx context.Context) []Source {
+	sources, _ := m.mergeSources()
+	ok := make([]bool, len(sources))
+
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func(i int, src Source) {
+			defer wg.Done()
+			if err := m.guidIndexFor(src.ID()).refresh(ctx, src); err != nil {
+				slog.Warn("failed to refresh GUID index", "source", src.Name(), "error", err)
+				return
+			}
+			ok[i] = true
+		}(i, src)
+	}
+	wg.Wait()
+
+	var ready []Source
+	for i, src := range sources {
+		if ok[i] {
+			ready = append(ready, src)
+		}
+	}
+	return ready
+}
+
+// MatchGUID finds an item on every enabled source by any of its GUIDs.
+// Results are ordered by server name, as in merged libraries.
+func (m *Manager) MatchGUID(ctx context.Context, guids ...string) []GUIDMatch {
+	var matches []GUIDMatch
+	for _, src := range m.refreshGUIDIndexes(ctx) {
+		if match, ok := m.guidIndexFor(src.ID()).lookup(guids...); ok {
+			matches = append(matches, match)
+		}
+	}
+	return matches
+}
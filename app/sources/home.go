@@ -0,0 +1,102 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/0skillallluck/scanline/app/secrets"
+	"github.com/0skillallluck/scanline/provider/plex/auth"
+)
+
+// HomeUser is a member of a Plex Home.
+type HomeUser = auth.HomeUser
+
+// ErrInvalidPIN is returned when switching to a protected Home user fails
+// because of a wrong PIN.
+var ErrInvalidPIN = auth.ErrInvalidPIN
+
+// profileTokenKey returns the keyring key for the token of the Home user
+// an account is switched to.
+func profileTokenKey(accountID string) string {
+	return "plex_profile_token_" + accountID
+}
+
+// accountToken returns the token of the account's active Home user, or the
+// account owner's token if no other user is selected.
+func accountToken(accountID string) string {
+	if token := secrets.GetToken(profileTokenKey(accountID)); token != "" {
+		return token
+	}
+	return secrets.GetToken("plex_token_" + accountID)
+}
+
+// accountByID returns the account with the given ID, or nil.
+func (m *Manager) accountByID(accountID string) *Account {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, acct := range m.accounts {
+		if acct.ID == accountID {
+			return acct
+		}
+	}
+	return nil
+}
+
+// HomeUsers lists the Plex Home members of an account.
+func (m *Manager) HomeUsers(ctx context.Context, accountID string) ([]HomeUser, error) {
+	acct := m.accountByID(accountID)
+	if acct == nil {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	token := secrets.GetToken("plex_token_" + accountID)
+	if token == "" {
+		return nil, ErrNoPlexAccount
+	}
+	return auth.HomeUsers(ctx, token, acct.ClientID)
+}
+
+// SwitchHomeUser makes user the active profile of an account. The pin is
+// required for protected users. Servers are rediscovered with the user's
+// token so that server access tokens, and with them watch state and
+// content restrictions, follow the profile.
+func (m *Manager) SwitchHomeUser(ctx context.Context, accountID string, user HomeUser, pin string) error {
+	acct := m.accountByID(accountID)
+	if acct == nil {
+		return fmt.Errorf("account %s not found", accountID)
+	}
+	ownerToken := secrets.GetToken("plex_token_" + accountID)
+	if ownerToken == "" {
+		return ErrNoPlexAccount
+	}
+
+	userToken, err := auth.SwitchHomeUser(ctx, ownerToken, acct.ClientID, user.UUID, pin)
+	if err != nil {
+		return err
+	}
+	slog.Debug("plex: switched home user", "account", acct.Username, "user", user.Title)
+
+	// The owner keeps using the account token itself.
+	if user.Admin {
+		secrets.DeleteToken(profileTokenKey(accountID))
+	} else {
+		secrets.SetToken(profileTokenKey(accountID), userToken)
+	}
+
+	m.mu.Lock()
+	if user.Admin {
+		acct.ProfileUUID, acct.ProfileTitle = "", ""
+	} else {
+		acct.ProfileUUID, acct.ProfileTitle = user.UUID, user.Title
+	}
+	saveConfig(m.accounts)
+	m.mu.Unlock()
+
+	// Indexed items may be hidden from the new profile.
+	m.indexMu.Lock()
+	m.guidIndexes = make(map[string]*guidIndex)
+	m.indexMu.Unlock()
+
+	m.RefreshServers(ctx)
+	return nil
+}
@@ -0,0 +1,39 @@
+package sources
+
+import (
+	"context"
+
+	"github.com/0skillallluck/scanline/provider/jellyfin"
+)
+
+// JellyfinSource adapts a jellyfin.Client to the Source interface.
+type JellyfinSource struct {
+	*mediaBrowserSource
+	client *jellyfin.Client
+}
+
+// NewJellyfinSource creates a Source that wraps a Jellyfin server client.
+func NewJellyfinSource(serverID, name string, client *jellyfin.Client) *JellyfinSource {
+	return &JellyfinSource{
+		mediaBrowserSource: newMediaBrowserSource(serverID, name, client),
+		client:             client,
+	}
+}
+
+// JellyfinClient returns the underlying jellyfin.Client for
+// provider-specific operations. Avoid using this for general media access.
+func (s *JellyfinSource) JellyfinClient() *jellyfin.Client {
+	return s.client
+}
+
+func (s *JellyfinSource) GetLyrics(ctx context.Context, stream *Stream) (*Lyrics, error) {
+	lyrics, err := s.client.Lyrics(ctx, stream.Key)
+	if err != nil {
+		return nil, err
+	}
+	format := "txt"
+	if lyrics.Synced() {
+		format = "lrc"
+	}
+	return &Lyrics{Format: format, Text: lyrics.LRC()}, nil
+}
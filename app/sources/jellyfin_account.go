@@ -0,0 +1,45 @@
+package sources
+
+import (
+	"log/slog"
+
+	"github.com/0skillallluck/scanline/app/secrets"
+	"github.com/0skillallluck/scanline/provider/jellyfin"
+	"github.com/google/uuid"
+)
+
+// jellyfinTokenKey returns the keyring key for the access token of a
+// Jellyfin account.
+func jellyfinTokenKey(accountID string) string {
+	return "jellyfin_token_" + accountID
+}
+
+// AddJellyfinAccount adds a Jellyfin account signed in to the server at
+// serverURL. Unlike Plex accounts, a Jellyfin account belongs to a single
+// server, which is enabled right away.
+func (m *Manager) AddJellyfinAccount(serverURL, clientID string, info *jellyfin.PublicSystemInfo, result *jellyfin.AuthenticationResult) {
+	accountID := uuid.New().String()
+
+	// Keyring I/O outside lock
+	secrets.SetToken(jellyfinTokenKey(accountID), result.AccessToken)
+
+	srv := &Server{
+		ID:        info.ID,
+		Name:      info.ServerName,
+		Enabled:   true,
+		Owned:     result.User.Policy.IsAdministrator,
+		URL:       serverURL,
+		Reachable: true,
+	}
+	acct := &Account{
+		ID:       accountID,
+		Type:     ProviderJellyfin,
+		Username: result.User.Name,
+		ClientID: clientID,
+		UserID:   result.User.ID,
+		Servers:  []*Server{srv},
+	}
+	slog.Debug("jellyfin: adding account", "server", srv.Name, "username", acct.Username)
+
+	m.addAccount(acct, result.AccessToken)
+}
@@ -0,0 +1,371 @@
+package sources
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0skillallluck/scanline/provider/jellyfin"
+)
+
+// Mapping between Jellyfin API types and the provider-neutral model.
+
+// jellyfinTypes maps Jellyfin item types to item types.
+var jellyfinTypes = map[string]string{
+	jellyfin.TypeMovie:       "movie",
+	jellyfin.TypeSeries:      "show",
+	jellyfin.TypeSeason:      "season",
+	jellyfin.TypeEpisode:     "episode",
+	jellyfin.TypeMusicArtist: "artist",
+	jellyfin.TypeMusicAlbum:  "album",
+	jellyfin.TypeAudio:       "track",
+	jellyfin.TypePhoto:       "photo",
+	jellyfin.TypePhotoAlbum:  "photoalbum",
+	jellyfin.TypeFolder:      "photoalbum",
+	jellyfin.TypeVideo:       "clip",
+}
+
+// jellyfinSectionTypes maps the collection types of library views to
+// section types. Views of other types are not listed.
+var jellyfinSectionTypes = map[string]string{
+	jellyfin.CollectionMovies:     "movie",
+	jellyfin.CollectionTVShows:    "show",
+	jellyfin.CollectionMusic:      "artist",
+	jellyfin.CollectionHomeVideos: "photo",
+	jellyfin.CollectionPhotos:     "photo",
+}
+
+// jellyfinStreamTypes maps media stream types to the Stream* constants.
+var jellyfinStreamTypes = map[string]int{
+	jellyfin.StreamVideo:    StreamVideo,
+	jellyfin.StreamAudio:    StreamAudio,
+	jellyfin.StreamSubtitle: StreamSubtitle,
+	jellyfin.StreamLyric:    StreamLyrics,
+}
+
+func jellyfinItems(items []jellyfin.Item) []Item {
+	if items == nil {
+		return nil
+	}
+	out := make([]Item, len(items))
+	for i := range items {
+		out[i] = jellyfinItem(&items[i])
+	}
+	return out
+}
+
+// inSection sets the section of items listed from a library section, as
+// Jellyfin and Emby do not report it with the items.
+func inSection(items []Item, sectionID string) []Item {
+	for i := range items {
+		items[i].SectionID = sectionID
+	}
+	return items
+}
+
+func jellyfinItem(m *jellyfin.Item) Item {
+	item := Item{
+		ID:            ItemID(m.ID),
+		Type:          jellyfinTypes[m.Type],
+		Title:         m.Name,
+		TitleSort:     m.SortName,
+		OriginalTitle: m.OriginalTitle,
+		Summary:       m.Overview,
+		Year:          m.ProductionYear,
+		Thumb:         jellyfinImage(m.ID, jellyfin.ImagePrimary, m.ImageTags[jellyfin.ImagePrimary]),
+		Duration:      int(m.RunTimeTicks / jellyfin.TicksPerMillisecond),
+		ChildCount:    m.ChildCount,
+		Index:         m.IndexNumber,
+		ParentIndex:   m.ParentIndexNumber,
+		ContentRating: m.OfficialRating,
+		Ratings:       jellyfinRatings(m),
+		Genre:         jellyfinGenres(m.GenreItems),
+		Director:      jellyfinPeople(m.People, jellyfin.PersonDirector),
+		Writer:        jellyfinPeople(m.People, jellyfin.PersonWriter),
+		Cast:          jellyfinPeople(m.People, jellyfin.PersonActor),
+	}
+	if len(m.Taglines) > 0 {
+		item.Tagline = m.Taglines[0]
+	}
+	if len(m.Studios) > 0 {
+		item.Studio = m.Studios[0].Name
+	}
+	if !m.PremiereDate.IsZero() {
+		item.OriginallyAvailableAt = m.PremiereDate.Format("2006-01-02")
+	}
+	if !m.DateCreated.IsZero() {
+		item.AddedAt = m.DateCreated.Unix()
+	}
+	if !m.DateLastSaved.IsZero() {
+		item.UpdatedAt = m.DateLastSaved.Unix()
+	}
+
+	switch {
+	case len(m.BackdropImageTags) > 0:
+		item.Art = jellyfinImage(m.ID, jellyfin.ImageBackdrop, m.BackdropImageTags[0])
+	case len(m.ParentBackdropImageTags) > 0:
+		item.Art = jellyfinImage(m.ParentBackdropItemID, jellyfin.ImageBackdrop, m.ParentBackdropImageTags[0])
+	}
+
+	// Containers count their playable descendants; Jellyfin only reports
+	// how many of them are unwatched.
+	item.LeafCount = m.RecursiveItemCount
+	if item.LeafCount == 0 {
+		item.LeafCount = m.ChildCount
+	}
+	if ud := m.UserData; ud != nil {
+		item.ViewOffset = int(ud.PlaybackPositionTicks / jellyfin.TicksPerMillisecond)
+		item.ViewCount = ud.PlayCount
+		if ud.Played && item.ViewCount == 0 {
+			item.ViewCount = 1
+		}
+		if !ud.LastPlayedDate.IsZero() {
+			item.LastViewedAt = ud.LastPlayedDate.Unix()
+		}
+		if item.LeafCount > 0 {
+			item.ViewedLeafCount = max(item.LeafCount-ud.UnplayedItemCount, 0)
+		}
+	}
+
+	switch m.Type {
+	case jellyfin.TypeSeason:
+		item.ParentID = ItemID(m.SeriesID)
+		item.ParentTitle = m.SeriesName
+		item.ParentThumb = jellyfinImage(m.SeriesID, jellyfin.ImagePrimary, m.SeriesPrimaryImageTag)
+	case jellyfin.TypeEpisode:
+		item.ParentID = ItemID(m.SeasonID)
+		item.ParentTitle = m.SeasonName
+		item.GrandparentID = ItemID(m.SeriesID)
+		item.GrandparentTitle = m.SeriesName
+		item.GrandparentThumb = jellyfinImage(m.SeriesID, jellyfin.ImagePrimary, m.SeriesPrimaryImageTag)
+	case jellyfin.TypeMusicAlbum:
+		if len(m.AlbumArtists) > 0 {
+			item.ParentID = ItemID(m.AlbumArtists[0].ID)
+			item.ParentTitle = m.AlbumArtists[0].Name
+		}
+	case jellyfin.TypeAudio:
+		item.ParentID = ItemID(m.AlbumID)
+		item.ParentTitle = m.Album
+		item.ParentThumb = jellyfinImage(m.AlbumID, jellyfin.ImagePrimary, m.AlbumPrimaryImageTag)
+		if len(m.AlbumArtists) > 0 {
+			item.GrandparentID = ItemID(m.AlbumArtists[0].ID)
+		}
+		item.GrandparentTitle = m.AlbumArtist
+		if artists := strings.Join(m.Artists, ", "); artists != m.AlbumArtist {
+			item.OriginalTitle = artists
+		}
+	}
+
+	for _, key := range []string{"Imdb", "Tmdb", "Tvdb"} {
+		if id := m.ProviderIDs[key]; id != "" {
+			item.ExternalIDs = append(item.ExternalIDs, strings.ToLower(key)+"://"+id)
+		}
+	}
+
+	if m.Type == jellyfin.TypePhoto {
+		item.Media = []MediaVersion{jellyfinPhotoMedia(m)}
+	}
+	for i := range m.MediaSources {
+		item.Media = append(item.Media, jellyfinMedia(m, &m.MediaSources[i]))
+	}
+	return item
+}
+
+// jellyfinImage returns the image path of an item, or "" when the item
+// has no image of that type.
+func jellyfinImage(itemID, imageType, tag string) string {
+	if itemID == "" || tag == "" {
+		return ""
+	}
+	return jellyfin.ImagePath(itemID, imageType, tag)
+}
+
+// jellyfinMedia maps a media source. The part key is the direct play path
+// of the source.
+func jellyfinMedia(m *jellyfin.Item, ms *jellyfin.MediaSource) MediaVersion {
+	media := MediaVersion{
+		Duration:  int(ms.RunTimeTicks / jellyfin.TicksPerMillisecond),
+		Bitrate:   ms.Bitrate / 1000,
+		Container: ms.Container,
+	}
+	part := MediaPart{
+		Key:       jellyfin.VideoStreamPath(m.ID, ms.ID),
+		Duration:  media.Duration,
+		File:      ms.Path,
+		Size:      ms.Size,
+		Container: ms.Container,
+	}
+	if m.Type == jellyfin.TypeAudio {
+		part.Key = jellyfin.AudioStreamPath(m.ID, ms.ID)
+	}
+	for i := range ms.MediaStreams {
+		s := &ms.MediaStreams[i]
+		stream := jellyfinStream(s)
+		switch stream.StreamType {
+		case StreamVideo:
+			if media.VideoCodec == "" {
+				media.VideoCodec = s.Codec
+				media.Width, media.Height = s.Width, s.Height
+				media.AspectRatio = jellyfinAspectRatio(s)
+				media.VideoResolution = jellyfinResolution(s.Height)
+			}
+		case StreamAudio:
+			if media.AudioCodec == "" {
+				media.AudioCodec = s.Codec
+				media.AudioChannels = s.Channels
+			}
+			stream.Gain = m.NormalizationGain
+		}
+		part.Stream = append(part.Stream, stream)
+	}
+	if m.HasLyrics {
+		part.Stream = append(part.Stream, Stream{
+			StreamType: StreamLyrics,
+			Key:        jellyfin.LyricsPath(m.ID),
+			Format:     "lrc",
+		})
+	}
+	media.Part = []MediaPart{part}
+	return media
+}
+
+// jellyfinStream maps a media stream. Stream IDs are the stream index plus
+// one, since an ID of 0 selects no stream in a PlaybackRequest.
+func jellyfinStream(s *jellyfin.MediaStream) Stream {
+	return Stream{
+		ID:           s.Index + 1,
+		StreamType:   jellyfinStreamTypes[s.Type],
+		Codec:        s.Codec,
+		Index:        s.Index,
+		Bitrate:      s.BitRate / 1000,
+		Language:     s.Language,
+		LanguageCode: s.Language,
+		Title:        s.Title,
+		DisplayTitle: s.DisplayTitle,
+		Channels:     s.Channels,
+		SamplingRate: s.SampleRate,
+		BitDepth:     s.BitDepth,
+		Width:        s.Width,
+		Height:       s.Height,
+		FrameRate:    s.RealFrameRate,
+		Selected:     s.IsDefault,
+		Default:      s.IsDefault,
+		Forced:       s.IsForced,
+	}
+}
+
+// jellyfinPhotoMedia synthesizes the media of a photo, whose part key is
+// the image path so that it can be scaled with Source.PhotoTranscodeURL.
+func jellyfinPhotoMedia(m *jellyfin.Item) MediaVersion {
+	media := MediaVersion{
+		Width:  m.Width,
+		Height: m.Height,
+		Make:   m.CameraMake,
+		Model:  m.CameraModel,
+		ISO:    m.IsoSpeedRating,
+		Part:   []MediaPart{{Key: jellyfin.ImagePath(m.ID, jellyfin.ImagePrimary, m.ImageTags[jellyfin.ImagePrimary])}},
+	}
+	if m.Aperture > 0 {
+		media.Aperture = fmt.Sprintf("f/%g", m.Aperture)
+	}
+	if m.ExposureTime > 0 {
+		if m.ExposureTime < 1 {
+			media.Exposure = fmt.Sprintf("1/%.0f", 1/m.ExposureTime)
+		} else {
+			media.Exposure = fmt.Sprintf("%g", m.ExposureTime)
+		}
+	}
+	if m.FocalLength > 0 {
+		media.Lens = fmt.Sprintf("%gmm", m.FocalLength)
+	}
+	return media
+}
+
+// jellyfinAspectRatio computes the aspect ratio of a video stream from its
+// dimensions; Jellyfin reports it as a display string such as "16:9".
+func jellyfinAspectRatio(s *jellyfin.MediaStream) float64 {
+	if s.Height == 0 {
+		return 0
+	}
+	return float64(s.Width) / float64(s.Height)
+}
+
+// jellyfinResolution returns the resolution label of a video height.
+func jellyfinResolution(height int) string {
+	switch {
+	case height >= 2000:
+		return "4k"
+	case height >= 1000:
+		return "1080"
+	case height >= 700:
+		return "720"
+	case height >= 560:
+		return "576"
+	case height >= 400:
+		return "480"
+	case height > 0:
+		return "sd"
+	}
+	return ""
+}
+
+// jellyfinRatings maps the community rating (0-10) and the critic rating
+// (a Rotten Tomatoes percentage) of an item.
+func jellyfinRatings(m *jellyfin.Item) Ratings {
+	var ratings Ratings
+	if m.CriticRating > 0 {
+		ratings = append(ratings, Rating{Source: RatingRottenTomatoes, Type: "critic", Value: m.CriticRating / 10})
+	}
+	if m.CommunityRating > 0 {
+		ratings = append(ratings, Rating{Type: "audience", Value: m.CommunityRating})
+	}
+	return ratings
+}
+
+func jellyfinGenres(genres []jellyfin.NameID) []Tag {
+	if genres == nil {
+		return nil
+	}
+	out := make([]Tag, len(genres))
+	for i, g := range genres {
+		out[i] = Tag{ID: g.ID, Name: g.Name}
+	}
+	return out
+}
+
+// jellyfinPeople returns the people credited as personType.
+func jellyfinPeople(people []jellyfin.Person, personType string) []Tag {
+	var out []Tag
+	for _, p := range people {
+		if p.Type != personType {
+			continue
+		}
+		out = append(out, Tag{
+			ID:    p.ID,
+			Name:  p.Name,
+			Role:  p.Role,
+			Thumb: jellyfinImage(p.ID, jellyfin.ImagePrimary, p.PrimaryImageTag),
+		})
+	}
+	return out
+}
+
+func jellyfinSection(v *jellyfin.Item) LibrarySection {
+	section := LibrarySection{
+		Key:   v.ID,
+		Type:  jellyfinSectionTypes[v.CollectionType],
+		Title: v.Name,
+		Thumb: jellyfinImage(v.ID, jellyfin.ImagePrimary, v.ImageTags[jellyfin.ImagePrimary]),
+	}
+	if !v.DateLastSaved.IsZero() {
+		section.UpdatedAt = v.DateLastSaved.Unix()
+	}
+	return section
+}
+
+// jellyfinSorts maps the Sort* constants to Jellyfin sort orders, sorted
+// descending unless title order is requested.
+var jellyfinSorts = map[string]string{
+	SortTitle:      jellyfin.SortByName,
+	SortAddedAt:    jellyfin.SortByDateCreated,
+	SortReleasedAt: jellyfin.SortByPremiereDate,
+}
@@ -0,0 +1,224 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/0skillallluck/scanline/provider/plex"
+)
+
+// librarySource limits the library sections of a source to the libraries
+// enabled for its server.
+type librarySource struct {
+	Source
+	allowed map[string]bool
+}
+
+// newServerSource creates the Source for a server, applying its library
+// allowlist. Requests fail over between the server's connections, with
+// changes reported to onChange.
+func newServerSource(srv *Server, client *plex.Client, onChange connectionChangeFunc) Source {
+	src := NewPlexSource(srv.ID, srv.Name, client)
+	src.failover = newConnectionFailover(srv, client, onChange)
+	return withLibraries(withSnapshot(src, src.failover, nil), srv.Libraries)
+}
+
+// withLibraries wraps src so that only the given library sections are
+// listed. A nil allowlist returns the unwrapped source.
+func withLibraries(src Source, libraries []string) Source {
+	src = withoutLibraries(src)
+	if libraries == nil {
+		return src
+	}
+	allowed := make(map[string]bool, len(libraries))
+	for _, key := range libraries {
+		allowed[key] = true
+	}
+	return &librarySource{Source: src, allowed: allowed}
+}
+
+// withoutLibraries returns the source without its library filter.
+func withoutLibraries(src Source) Source {
+	if ls, ok := src.(*librarySource); ok {
+		return ls.Source
+	}
+	return src
+}
+
+// unwrapSource returns the source without its library filter and
+// snapshot.
+func unwrapSource(src Source) Source {
+	src = withoutLibraries(src)
+	if ss, ok := src.(*snapshotSource); ok {
+		return ss.Source
+	}
+	return src
+}
+
+func (s *librarySource) LibrarySections(ctx context.Context) ([]LibrarySection, error) {
+	sections, err := s.Source.LibrarySections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(sections, func(sec LibrarySection) bool {
+		return !s.allowed[sec.Key]
+	}), nil
+}
+
+func (s *librarySource) LibrarySection(ctx context.Context, sectionID string) (*LibrarySection, error) {
+	if !s.allowed[sectionID] {
+		return nil, errSectionDisabled(sectionID)
+	}
+	return s.Source.LibrarySection(ctx, sectionID)
+}
+
+func (s *librarySource) LibraryContent(ctx context.Context, sectionID string, opts *ContentOptions) ([]Item, int, error) {
+	if !s.allowed[sectionID] {
+		return nil, 0, errSectionDisabled(sectionID)
+	}
+	return s.Source.LibraryContent(ctx, sectionID, opts)
+}
+
+func (s *librarySource) SectionHubs(ctx context.Context, sectionID string) ([]Hub, error) {
+	if !s.allowed[sectionID] {
+		return nil, errSectionDisabled(sectionID)
+	}
+	return s.Source.SectionHubs(ctx, sectionID)
+}
+
+// Hubs and search results mix the items of several sections; items of
+// disabled sections are removed from them. Items whose section the source
+// does not report are kept.
+
+func (s *librarySource) HomeHubs(ctx context.Context, count int) ([]Hub, error) {
+	hubs, err := s.Source.HomeHubs(ctx, count)
+	return s.filterHubs(hubs), err
+}
+
+// HubItems keeps the server's total, since pages are still requested by
+// the server's offsets; HubPage returns the offset of the next page.
+func (s *librarySource) HubItems(ctx context.Context, hubKey string, start, size int) ([]Item, int, error) {
+	items, total, err := s.Source.HubItems(ctx, hubKey, start, size)
+	return s.filterItems(items), total, err
+}
+
+func (s *librarySource) RelatedHubs(ctx context.Context, id ItemID) ([]Hub, error) {
+	hubs, err := s.Source.RelatedHubs(ctx, id)
+	return s.filterHubs(hubs), err
+}
+
+func (s *librarySource) Search(ctx context.Context, query string, limit int) ([]Hub, error) {
+	hubs, err := s.Source.Search(ctx, query, limit)
+	return s.filterHubs(hubs), err
+}
+
+// HubPage fetches a page of a hub like Source.HubItems, and also returns
+// the server offset of the next page. Items of disabled sections are left
+// out of the page but count towards the offset and the total.
+func HubPage(ctx context.Context, src Source, hubKey string, start, size int) (items []Item, next, total int, err error) {
+	ls, filtered := src.(*librarySource)
+	if filtered {
+		src = ls.Source
+	}
+	items, total, err = src.HubItems(ctx, hubKey, start, size)
+	next = start + len(items)
+	if filtered {
+		items = ls.filterItems(items)
+	}
+	return items, next, total, err
+}
+
+// filterItems removes the items of disabled sections.
+func (s *librarySource) filterItems(items []Item) []Item {
+	return slices.DeleteFunc(items, func(item Item) bool {
+		return item.SectionID != "" && !s.allowed[item.SectionID]
+	})
+}
+
+// filterHubs removes the items of disabled sections from hubs, and the
+// hubs that only held such items.
+func (s *librarySource) filterHubs(hubs []Hub) []Hub {
+	out := hubs[:0]
+	for _, hub := range hubs {
+		n := len(hub.Items)
+		hub.Items = s.filterItems(hub.Items)
+		if n > 0 && len(hub.Items) == 0 {
+			continue
+		}
+		out = append(out, hub)
+	}
+	return out
+}
+
+func errSectionDisabled(sectionID string) error {
+	return fmt.Errorf("library section %s is disabled", sectionID)
+}
+
+// ServerLibraries returns every library section of an enabled server,
+// including the ones disabled in its allowlist.
+func (m *Manager) ServerLibraries(ctx context.Context, serverID string) ([]LibrarySection, error) {
+	src := m.SourceForServer(serverID)
+	if src == nil {
+		return nil, fmt.Errorf("server %s is not enabled", serverID)
+	}
+	return withoutLibraries(src).LibrarySections(ctx)
+}
+
+// IsLibraryEnabled reports whether a library section of a server is in its
+// allowlist.
+func (m *Manager) IsLibraryEnabled(serverID, sectionKey string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, acct := range m.accounts {
+		for _, srv := range acct.Servers {
+			if srv.ID == serverID {
+				return srv.Libraries == nil || slices.Contains(srv.Libraries, sectionKey)
+			}
+		}
+	}
+	return false
+}
+
+// SetLibraryEnabled adds a library section to, or removes it from, a
+// server's allowlist. all lists every section key of the server; when all
+// of them end up enabled the allowlist is cleared so that libraries added
+// later are enabled too.
+func (m *Manager) SetLibraryEnabled(serverID, sectionKey string, enabled bool, all []string) {
+	m.mu.Lock()
+	var found *Server
+	for _, acct := range m.accounts {
+		for _, srv := range acct.Servers {
+			if srv.ID == serverID {
+				found = srv
+			}
+		}
+	}
+	if found == nil {
+		m.mu.Unlock()
+		return
+	}
+
+	libraries := found.Libraries
+	if libraries == nil {
+		libraries = slices.Clone(all)
+	}
+	libraries = slices.DeleteFunc(libraries, func(key string) bool { return key == sectionKey })
+	if enabled {
+		libraries = append(libraries, sectionKey)
+	}
+	if len(libraries) == len(all) && !slices.ContainsFunc(all, func(key string) bool {
+		return !slices.Contains(libraries, key)
+	}) {
+		libraries = nil
+	}
+	found.Libraries = libraries
+
+	if src, ok := m.sources[serverID]; ok {
+		m.sources[serverID] = withLibraries(src, libraries)
+	}
+	saveConfig(m.accounts)
+	m.mu.Unlock()
+
+	m.SourcesChanged.Notify(struct{}{})
+}
@@ -0,0 +1,123 @@
+package sources
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// sectionsSource serves a movie library in section 1 and another in
+// section 2. Its hubs hold the movie of section 2 between those of
+// section 1.
+type sectionsSource struct {
+	Source
+}
+
+var (
+	enabledMovie  = Item{ID: "10", GUID: "plex://movie/enabled", Type: "movie", Title: "Enabled", SectionID: "1"}
+	disabledMovie = Item{ID: "20", GUID: "plex://movie/disabled", Type: "movie", Title: "Disabled", SectionID: "2"}
+	otherMovie    = Item{ID: "11", GUID: "plex://movie/other", Type: "movie", Title: "Other", SectionID: "1"}
+)
+
+func (s *sectionsSource) ID() string   { return "server" }
+func (s *sectionsSource) Name() string { return "Server" }
+
+func (s *sectionsSource) LibrarySections(ctx context.Context) ([]LibrarySection, error) {
+	return []LibrarySection{{Key: "1", Type: "movie"}, {Key: "2", Type: "movie"}}, nil
+}
+
+func (s *sectionsSource) LibraryContent(ctx context.Context, sectionID string, opts *ContentOptions) ([]Item, int, error) {
+	if sectionID == "1" {
+		return []Item{enabledMovie}, 1, nil
+	}
+	return []Item{disabledMovie}, 1, nil
+}
+
+func (s *sectionsSource) HomeHubs(ctx context.Context, count int) ([]Hub, error) {
+	return []Hub{
+		{Key: "recent", Title: "Recently Added", Items: []Item{enabledMovie, disabledMovie}},
+		{Key: "recent/2", Title: "Recently Added in Disabled", Items: []Item{disabledMovie}},
+	}, nil
+}
+
+func (s *sectionsSource) HubItems(ctx context.Context, hubKey string, start, size int) ([]Item, int, error) {
+	items := []Item{enabledMovie, disabledMovie, otherMovie}
+	end := min(start+size, len(items))
+	return slices.Clone(items[min(start, end):end]), len(items), nil
+}
+
+func (s *sectionsSource) Search(ctx context.Context, query string, limit int) ([]Hub, error) {
+	return []Hub{{Key: "movie", Title: "Movies", Items: []Item{enabledMovie, disabledMovie}}}, nil
+}
+
+func TestLibrarySource_HidesDisabledSections(t *testing.T) {
+	ctx := context.Background()
+	src := withLibraries(&sectionsSource{}, []string{"1"})
+
+	if _, _, err := src.LibraryContent(ctx, "2", nil); err == nil {
+		t.Error("LibraryContent() of a disabled section error = nil, want error")
+	}
+
+	hubs, err := src.HomeHubs(ctx, 10)
+	if err != nil {
+		t.Fatalf("HomeHubs() error = %v", err)
+	}
+	if len(hubs) != 1 || len(hubs[0].Items) != 1 || hubs[0].Items[0].ID != enabledMovie.ID {
+		t.Errorf("HomeHubs() = %+v, want one hub with only the enabled movie", hubs)
+	}
+
+	items, total, err := src.HubItems(ctx, "recent", 0, 10)
+	if err != nil {
+		t.Fatalf("HubItems() error = %v", err)
+	}
+	if len(items) != 2 || items[0].ID != enabledMovie.ID || items[1].ID != otherMovie.ID || total != 3 {
+		t.Errorf("HubItems() = %+v of %d, want the movies of section 1 of 3", items, total)
+	}
+
+	results, err := src.Search(ctx, "movie", 10)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 || len(results[0].Items) != 1 || results[0].Items[0].ID != enabledMovie.ID {
+		t.Errorf("Search() = %+v, want only the enabled movie", results)
+	}
+
+	idx := newGUIDIndex()
+	if err := idx.refresh(ctx, src); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	if _, ok := idx.lookup(enabledMovie.GUID); !ok {
+		t.Error("GUID index misses the enabled movie")
+	}
+	if _, ok := idx.lookup(disabledMovie.GUID); ok {
+		t.Error("GUID index has the movie of the disabled section")
+	}
+}
+
+func TestHubPage_KeepsServerOffsets(t *testing.T) {
+	ctx := context.Background()
+	src := withLibraries(&sectionsSource{}, []string{"1"})
+
+	tests := []struct {
+		start    int
+		wantIDs  []ItemID
+		wantNext int
+	}{
+		{start: 0, wantIDs: []ItemID{enabledMovie.ID}, wantNext: 2},
+		{start: 2, wantIDs: []ItemID{otherMovie.ID}, wantNext: 3},
+		{start: 3, wantIDs: nil, wantNext: 3},
+	}
+	for _, tt := range tests {
+		items, next, total, err := HubPage(ctx, src, "recent", tt.start, 2)
+		if err != nil {
+			t.Fatalf("HubPage(%d) error = %v", tt.start, err)
+		}
+		var ids []ItemID
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		if !slices.Equal(ids, tt.wantIDs) || next != tt.wantNext || total != 3 {
+			t.Errorf("HubPage(%d) = %v, next %d of %d; want %v, next %d of 3", tt.start, ids, next, total, tt.wantIDs, tt.wantNext)
+		}
+	}
+}
@@ -2,24 +2,48 @@ package sources
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/0skillallluck/scanline/app/secrets"
 	"github.com/0skillallluck/scanline/internal/signals"
+	"github.com/0skillallluck/scanline/provider/emby"
+	"github.com/0skillallluck/scanline/provider/jellyfin"
 	"github.com/0skillallluck/scanline/provider/plex"
 	"github.com/0skillallluck/scanline/provider/plex/auth"
 	"github.com/0skillallluck/scanline/provider/plex/watchlist"
 	"github.com/google/uuid"
 )
 
+var (
+	// ErrNoPlexAccount is returned for account-level features such as the
+	// watchlist when no Plex account is signed in.
+	ErrNoPlexAccount = errors.New("no Plex account signed in")
+
+	// ErrNotOnDiscover is returned for items without a "plex://" GUID,
+	// which have no online record to put on the watchlist.
+	ErrNotOnDiscover = errors.New("item is not matched to Plex online metadata")
+)
+
 // Manager manages multi-account sources with signals for UI updates.
 type Manager struct {
 	accounts []*Account
 	sources  map[string]Source // serverID → Source (enabled+resolved only)
 	mu       sync.RWMutex
 
+	// guidIndexes maps serverID → GUID index, built on first use.
+	guidIndexes map[string]*guidIndex
+	indexMu     sync.Mutex
+
+	// networkChanged wakes the connection monitor after network changes.
+	networkChanged chan struct{}
+
+	// lan is the last GDM discovery, reused across server refreshes.
+	lan lanCache
+
 	// SourcesChanged fires when accounts, servers, or enabled state changes.
 	SourcesChanged *signals.StatelessSignal[struct{}]
 }
@@ -28,6 +52,8 @@ type Manager struct {
 func NewManager() *Manager {
 	m := &Manager{
 		sources:        make(map[string]Source),
+		guidIndexes:    make(map[string]*guidIndex),
+		networkChanged: make(chan struct{}, 1),
 		SourcesChanged: signals.NewStatelessSignal[struct{}](),
 	}
 
@@ -43,20 +69,19 @@ func NewManager() *Manager {
 
 	// Create Sources for all enabled servers, loading server tokens from keyring
 	for _, acct := range m.accounts {
-		token := secrets.GetToken("plex_token_" + acct.ID)
-		if token == "" {
+		token := sourceToken(acct)
+		if token == "" && acct.Type != ProviderPlexServer {
 			continue
 		}
 		for _, srv := range acct.Servers {
 			// Load server-specific access token from keyring
-			if srvToken := secrets.GetToken(serverTokenKey(acct.ID, srv.ID)); srvToken != "" {
+			if srvToken := secrets.GetToken(accountServerTokenKey(acct, srv.ID)); srvToken != "" {
 				srv.AccessToken = srvToken
 			}
 			// Assume reachable until a connection probe says otherwise
 			srv.Reachable = srv.URL != ""
 			if srv.Enabled && srv.URL != "" {
-				client := plex.NewClient(srv.URL, tokenForServer(token, srv), acct.ClientID)
-				m.sources[srv.ID] = NewPlexSource(srv.ID, srv.Name, client)
+				m.sources[srv.ID] = m.newAccountSource(acct, srv, token)
 			}
 		}
 	}
@@ -80,6 +105,51 @@ func tokenForServer(accountToken string, srv *Server) string {
 	return accountToken
 }
 
+// accountServerTokenKey returns the keyring key for a server-specific
+// access token of the account.
+func accountServerTokenKey(acct *Account, serverID string) string {
+	if acct.Type == ProviderEmby {
+		return embyServerTokenKey(acct.ID, serverID)
+	}
+	return serverTokenKey(acct.ID, serverID)
+}
+
+// accountTokenKey returns the keyring key for the access token of the
+// account.
+func accountTokenKey(acct *Account) string {
+	switch acct.Type {
+	case ProviderJellyfin:
+		return jellyfinTokenKey(acct.ID)
+	case ProviderEmby:
+		return embyTokenKey(acct.ID)
+	case ProviderPlexServer:
+		return plexServerTokenKey(acct.ID)
+	default:
+		return "plex_token_" + acct.ID
+	}
+}
+
+// sourceToken returns the token the account's sources authenticate with.
+func sourceToken(acct *Account) string {
+	if acct.Type == ProviderPlex {
+		return accountToken(acct.ID)
+	}
+	return secrets.GetToken(accountTokenKey(acct))
+}
+
+// newAccountSource creates the Source for a server of an account.
+func (m *Manager) newAccountSource(acct *Account, srv *Server, token string) Source {
+	switch acct.Type {
+	case ProviderJellyfin:
+		client := jellyfin.NewClient(srv.URL, token, acct.UserID, acct.ClientID)
+		return withLibraries(withSnapshot(NewJellyfinSource(srv.ID, srv.Name, client), nil, m.offlineChanged), srv.Libraries)
+	case ProviderEmby:
+		client := emby.NewClient(srv.URL, tokenForServer(token, srv), srv.UserID, acct.ClientID)
+		return withLibraries(withSnapshot(NewEmbySource(srv.ID, srv.Name, client), nil, m.offlineChanged), srv.Libraries)
+	}
+	return newServerSource(srv, plex.NewClient(srv.URL, tokenForServer(token, srv), acct.ClientID), m.connectionChanged)
+}
+
 // Accounts returns all accounts.
 func (m *Manager) Accounts() []*Account {
 	m.mu.RLock()
@@ -112,6 +182,31 @@ func (m *Manager) SourceForServer(serverID string) Source {
 	return m.sources[serverID]
 }
 
+// IsServerOwned reports whether the signed-in user owns the given server.
+func (m *Manager) IsServerOwned(serverID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, acct := range m.accounts {
+		for _, srv := range acct.Servers {
+			if srv.ID == serverID {
+				return srv.Owned
+			}
+		}
+	}
+	return false
+}
+
+// OwnedSources returns the active Sources for enabled servers owned by the user.
+func (m *Manager) OwnedSources() []Source {
+	var result []Source
+	for _, src := range m.EnabledSources() {
+		if m.IsServerOwned(src.ID()) {
+			result = append(result, src)
+		}
+	}
+	return result
+}
+
 // AddPlexAccount adds a new Plex account with discovered servers.
 // Owned servers are auto-enabled and their connections resolved.
 func (m *Manager) AddPlexAccount(ctx context.Context, token, username, clientID string, resources []auth.Resource) {
@@ -122,6 +217,7 @@ func (m *Manager) AddPlexAccount(ctx context.Context, token, username, clientID
 
 	// Network I/O outside lock — resolve connections for owned servers
 	connClient := newConnectionClient()
+	lan := m.discoverLAN(ctx)
 	serverCount := 0
 	for _, r := range resources {
 		if strings.Contains(r.Provides, "server") {
@@ -138,8 +234,13 @@ func (m *Manager) AddPlexAccount(ctx context.Context, token, username, clientID
 			ID:          r.ClientIdentifier,
 			Name:        r.Name,
 			Enabled:     r.Owned,
+			Owned:       r.Owned,
 			Reachable:   true,
 			AccessToken: r.AccessToken,
+			Home:        r.Home,
+		}
+		if !r.Owned {
+			srv.SharedBy = r.SourceTitle
 		}
 
 		// Store server-specific access token in keyring
@@ -148,13 +249,14 @@ func (m *Manager) AddPlexAccount(ctx context.Context, token, username, clientID
 		}
 
 		if r.Owned {
-			url, err := findBestConnection(ctx, connClient, r.Connections, token, clientID)
+			connections, err := rankConnections(ctx, connClient, r.Connections, lan()[r.ClientIdentifier], ConnectionPrefs{}, token, clientID)
+			srv.Connections = connections
 			if err != nil {
 				slog.Warn("failed to resolve server connection", "server", r.Name, "error", err)
 				srv.Reachable = false
 			} else {
-				slog.Debug("plex: server connection resolved", "server", r.Name, "url", url)
-				srv.URL = url
+				slog.Debug("plex: server connection resolved", "server", r.Name, "url", connections[0])
+				srv.URL = connections[0]
 			}
 		}
 
@@ -175,7 +277,7 @@ func (m *Manager) AddPlexAccount(ctx context.Context, token, username, clientID
 	for _, srv := range servers {
 		if srv.Enabled && srv.Reachable && srv.URL != "" {
 			client := plex.NewClient(srv.URL, tokenForServer(token, srv), clientID)
-			m.sources[srv.ID] = NewPlexSource(srv.ID, srv.Name, client)
+			m.sources[srv.ID] = newServerSource(srv, client, m.connectionChanged)
 		}
 	}
 	saveConfig(m.accounts)
@@ -189,14 +291,14 @@ func (m *Manager) RemoveAccount(accountID string) {
 	m.mu.Lock()
 	var remaining []*Account
 	var tokenKey string
-	var serverIDs []string
+	var serverKeys []string
 	for _, acct := range m.accounts {
 		if acct.ID == accountID {
 			for _, srv := range acct.Servers {
 				delete(m.sources, srv.ID)
-				serverIDs = append(serverIDs, srv.ID)
+				serverKeys = append(serverKeys, accountServerTokenKey(acct, srv.ID))
 			}
-			tokenKey = "plex_token_" + acct.ID
+			tokenKey = accountTokenKey(acct)
 			continue
 		}
 		remaining = append(remaining, acct)
@@ -211,8 +313,9 @@ func (m *Manager) RemoveAccount(accountID string) {
 	// Keyring I/O outside lock
 	if tokenKey != "" {
 		secrets.DeleteToken(tokenKey)
-		for _, srvID := range serverIDs {
-			secrets.DeleteToken(serverTokenKey(accountID, srvID))
+		secrets.DeleteToken(profileTokenKey(accountID))
+		for _, key := range serverKeys {
+			secrets.DeleteToken(key)
 		}
 	}
 	m.SourcesChanged.Notify(struct{}{})
@@ -221,7 +324,10 @@ func (m *Manager) RemoveAccount(accountID string) {
 // SetServerEnabled toggles a server's enabled state.
 func (m *Manager) SetServerEnabled(accountID, serverID string, enabled bool) {
 	// Keyring I/O outside lock
-	token := secrets.GetToken("plex_token_" + accountID)
+	var token string
+	if acct := m.accountByID(accountID); acct != nil {
+		token = sourceToken(acct)
+	}
 
 	m.mu.Lock()
 	found := false
@@ -239,9 +345,8 @@ func (m *Manager) SetServerEnabled(accountID, serverID string, enabled bool) {
 				if srv.URL == "" {
 					slog.Warn("server URL not cached, need to refresh servers", "server", srv.Name)
 				}
-				if srv.URL != "" && token != "" {
-					client := plex.NewClient(srv.URL, tokenForServer(token, srv), acct.ClientID)
-					m.sources[srv.ID] = NewPlexSource(srv.ID, srv.Name, client)
+				if srv.URL != "" && (token != "" || acct.Type == ProviderPlexServer) {
+					m.sources[srv.ID] = m.newAccountSource(acct, srv, token)
 				}
 			} else {
 				delete(m.sources, srv.ID)
@@ -272,6 +377,7 @@ type refreshResult struct {
 }
 
 // RefreshServers re-discovers servers for all accounts from plex.tv.
+// Servers added by URL are probed directly instead.
 func (m *Manager) RefreshServers(ctx context.Context) {
 	// Snapshot account info under RLock
 	m.mu.RLock()
@@ -281,15 +387,29 @@ func (m *Manager) RefreshServers(ctx context.Context) {
 		username     string
 		clientID     string
 		enabledState map[string]bool
+		libraries    map[string][]string
+		prefs        map[string]ConnectionPrefs
+		urls         map[string]string // last known, for offline servers
+		servers      []Server          // copies, for servers added by URL
 	}
 	infos := make([]accountInfo, 0, len(m.accounts))
 	for _, acct := range m.accounts {
-		if acct.Type != ProviderPlex {
+		if acct.Type != ProviderPlex && acct.Type != ProviderPlexServer {
 			continue
 		}
 		enabled := make(map[string]bool)
+		libraries := make(map[string][]string)
+		prefs := make(map[string]ConnectionPrefs)
+		urls := make(map[string]string)
+		var servers []Server
 		for _, srv := range acct.Servers {
 			enabled[srv.ID] = srv.Enabled
+			libraries[srv.ID] = srv.Libraries
+			prefs[srv.ID] = srv.ConnectionPrefs
+			urls[srv.ID] = srv.URL
+			if acct.Type == ProviderPlexServer {
+				servers = append(servers, *srv)
+			}
 		}
 		infos = append(infos, accountInfo{
 			id:           acct.ID,
@@ -297,6 +417,10 @@ func (m *Manager) RefreshServers(ctx context.Context) {
 			username:     acct.Username,
 			clientID:     acct.ClientID,
 			enabledState: enabled,
+			libraries:    libraries,
+			prefs:        prefs,
+			urls:         urls,
+			servers:      servers,
 		})
 	}
 	m.mu.RUnlock()
@@ -304,6 +428,7 @@ func (m *Manager) RefreshServers(ctx context.Context) {
 	// All network/keyring I/O outside lock — parallelize across accounts
 	slog.Debug("plex: refreshing servers", "account_count", len(infos))
 	connClient := newConnectionClient()
+	lan := m.discoverLAN(ctx)
 	var resultsMu sync.Mutex
 	var results []refreshResult
 	var wg sync.WaitGroup
@@ -311,7 +436,28 @@ func (m *Manager) RefreshServers(ctx context.Context) {
 		wg.Add(1)
 		go func(info accountInfo) {
 			defer wg.Done()
-			token := secrets.GetToken("plex_token_" + info.id)
+			if info.providerType == ProviderPlexServer {
+				token := secrets.GetToken(plexServerTokenKey(info.id))
+				var newServers []*Server
+				newSources := make(map[string]Source)
+				for _, srv := range info.servers {
+					srv := &srv
+					if src := m.probePlexServer(ctx, srv, token, info.clientID); src != nil {
+						newSources[srv.ID] = src
+					}
+					newServers = append(newServers, srv)
+				}
+				resultsMu.Lock()
+				results = append(results, refreshResult{
+					accountID: info.id,
+					servers:   newServers,
+					sources:   newSources,
+				})
+				resultsMu.Unlock()
+				return
+			}
+
+			token := accountToken(info.id)
 			if token == "" {
 				return
 			}
@@ -339,8 +485,16 @@ func (m *Manager) RefreshServers(ctx context.Context) {
 					ID:          r.ClientIdentifier,
 					Name:        r.Name,
 					Enabled:     enabled,
+					Owned:       r.Owned,
 					Reachable:   true,
 					AccessToken: r.AccessToken,
+					Home:        r.Home,
+					Libraries:   info.libraries[r.ClientIdentifier],
+
+					ConnectionPrefs: info.prefs[r.ClientIdentifier],
+				}
+				if !r.Owned {
+					srv.SharedBy = r.SourceTitle
 				}
 
 				// Update server-specific access token in keyring
@@ -349,14 +503,20 @@ func (m *Manager) RefreshServers(ctx context.Context) {
 				}
 
 				if enabled {
-					url, err := findBestConnection(ctx, connClient, r.Connections, token, info.clientID)
+					connections, err := rankConnections(ctx, connClient, r.Connections, lan()[r.ClientIdentifier], srv.ConnectionPrefs, token, info.clientID)
+					srv.Connections = connections
 					if err != nil {
 						slog.Warn("failed to resolve server connection", "server", r.Name, "error", err)
 						srv.Reachable = false
+						srv.URL = info.urls[srv.ID]
+						client := plex.NewClient(srv.URL, tokenForServer(token, srv), info.clientID)
+						if src := m.offlineServerSource(srv, client); src != nil {
+							newSources[srv.ID] = src
+						}
 					} else {
-						srv.URL = url
-						client := plex.NewClient(url, tokenForServer(token, srv), info.clientID)
-						newSources[srv.ID] = NewPlexSource(srv.ID, srv.Name, client)
+						srv.URL = connections[0]
+						client := plex.NewClient(srv.URL, tokenForServer(token, srv), info.clientID)
+						newSources[srv.ID] = newServerSource(srv, client, m.connectionChanged)
 					}
 				}
 
@@ -398,8 +558,14 @@ func (m *Manager) RefreshServers(ctx context.Context) {
 	m.SourcesChanged.Notify(struct{}{})
 }
 
-// Watchlist fetches watchlist items across all Plex accounts.
-func (m *Manager) Watchlist(ctx context.Context) ([]watchlist.Item, error) {
+// watchlistAccount is a Plex account with a client for its watchlist.
+type watchlistAccount struct {
+	id     string
+	client *watchlist.Client
+}
+
+// watchlistAccounts returns the signed-in Plex accounts that have a token.
+func (m *Manager) watchlistAccounts() []watchlistAccount {
 	m.mu.RLock()
 	type acctInfo struct {
 		id       string
@@ -413,21 +579,55 @@ func (m *Manager) Watchlist(ctx context.Context) ([]watchlist.Item, error) {
 	}
 	m.mu.RUnlock()
 
-	var allItems []watchlist.Item
-	seen := make(map[string]bool)
-
+	var result []watchlistAccount
 	for _, info := range infos {
-		token := secrets.GetToken("plex_token_" + info.id)
+		token := accountToken(info.id)
 		if token == "" {
 			continue
 		}
+		result = append(result, watchlistAccount{id: info.id, client: watchlist.NewClient(token, info.clientID)})
+	}
+	return result
+}
+
+// watchlistClientForServer returns the watchlist client of the account the
+// server belongs to, falling back to the first Plex account.
+func (m *Manager) watchlistClientForServer(serverID string) *watchlist.Client {
+	accounts := m.watchlistAccounts()
+	if len(accounts) == 0 {
+		return nil
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, acct := range m.accounts {
+		for _, srv := range acct.Servers {
+			if srv.ID != serverID {
+				continue
+			}
+			for _, wa := range accounts {
+				if wa.id == acct.ID {
+					return wa.client
+				}
+			}
+		}
+	}
+	return accounts[0].client
+}
+
+// Watchlist fetches watchlist items across all Plex accounts.
+func (m *Manager) Watchlist(ctx context.Context, opts WatchlistOptions) ([]watchlist.Item, error) {
+	var allItems []watchlist.Item
+	seen := make(map[string]bool)
 
-		client := watchlist.NewClient(token, info.clientID)
-		items, err := client.List(ctx, "all")
+	accounts := m.watchlistAccounts()
+	for _, acct := range accounts {
+		items, err := acct.client.List(ctx, opts)
 		if err != nil {
-			slog.Warn("failed to fetch watchlist", "account", info.id, "error", err)
+			slog.Warn("failed to fetch watchlist", "account", acct.id, "error", err)
 			continue
 		}
+		fillReleaseDates(ctx, acct.client, items)
 
 		for _, item := range items {
 			if !seen[item.RatingKey] {
@@ -437,69 +637,133 @@ func (m *Manager) Watchlist(ctx context.Context) ([]watchlist.Item, error) {
 		}
 	}
 
+	// Each account's list comes back sorted; merged lists need re-sorting.
+	// Date added is not reported per item, so that order is left as is.
+	if len(accounts) > 1 {
+		switch opts.Sort {
+		case watchlist.SortTitle:
+			slices.SortStableFunc(allItems, func(a, b watchlist.Item) int {
+				return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+			})
+		case watchlist.SortReleaseDate:
+			slices.SortStableFunc(allItems, func(a, b watchlist.Item) int {
+				return strings.Compare(b.OriginallyAvailableAt, a.OriginallyAvailableAt)
+			})
+		}
+	}
+
 	return allItems, nil
 }
 
-// WatchlistMatch represents a watchlist item that was found in a local library.
-type WatchlistMatch struct {
-	ServerID  string
-	RatingKey string
-	Type      string // "movie" or "show"
+// fillReleaseDates completes the release dates of watchlist items from their
+// discover records. The watchlist leaves the date out for items that have
+// not been scheduled yet when listed, though the record often has it.
+func fillReleaseDates(ctx context.Context, client *watchlist.Client, items []watchlist.Item) {
+	var wg sync.WaitGroup
+	for i := range items {
+		if items[i].OriginallyAvailableAt != "" {
+			continue
+		}
+		wg.Add(1)
+		go func(item *watchlist.Item) {
+			defer wg.Done()
+			meta, err := client.Metadata(ctx, item.RatingKey)
+			if err != nil {
+				slog.Debug("failed to fetch discover metadata", "ratingKey", item.RatingKey, "error", err)
+				return
+			}
+			item.OriginallyAvailableAt = meta.OriginallyAvailableAt
+		}(&items[i])
+	}
+	wg.Wait()
 }
 
+// IsWatchlisted reports whether the item with the given GUID is on the
+// watchlist of the account the server belongs to.
+func (m *Manager) IsWatchlisted(ctx context.Context, serverID, guid string) (bool, error) {
+	ratingKey, ok := watchlist.RatingKeyFromGUID(guid)
+	if !ok {
+		return false, ErrNotOnDiscover
+	}
+	client := m.watchlistClientForServer(serverID)
+	if client == nil {
+		return false, ErrNoPlexAccount
+	}
+	return client.IsWatchlisted(ctx, ratingKey)
+}
+
+// SetWatchlisted adds the item with the given GUID to, or removes it from,
+// the watchlist of the account the server belongs to.
+func (m *Manager) SetWatchlisted(ctx context.Context, serverID, guid string, watchlisted bool) error {
+	ratingKey, ok := watchlist.RatingKeyFromGUID(guid)
+	if !ok {
+		return ErrNotOnDiscover
+	}
+	client := m.watchlistClientForServer(serverID)
+	if client == nil {
+		return ErrNoPlexAccount
+	}
+
+	if watchlisted {
+		return client.Add(ctx, ratingKey)
+	}
+	return client.Remove(ctx, ratingKey)
+}
+
+// RemoveFromWatchlist removes a watchlist item by its discover rating key
+// from every account that has it.
+func (m *Manager) RemoveFromWatchlist(ctx context.Context, ratingKey string) error {
+	var lastErr error
+	removed := false
+	for _, acct := range m.watchlistAccounts() {
+		if err := acct.client.Remove(ctx, ratingKey); err != nil {
+			slog.Warn("failed to remove from watchlist", "account", acct.id, "error", err)
+			lastErr = err
+			continue
+		}
+		removed = true
+	}
+	if removed {
+		return nil
+	}
+	if lastErr == nil {
+		return ErrNoPlexAccount
+	}
+	return lastErr
+}
+
+// WatchlistMetadata fetches the full discover record of a watchlist item.
+func (m *Manager) WatchlistMetadata(ctx context.Context, ratingKey string) (*WatchlistMetadata, error) {
+	accounts := m.watchlistAccounts()
+	if len(accounts) == 0 {
+		return nil, ErrNoPlexAccount
+	}
+	return accounts[0].client.Metadata(ctx, ratingKey)
+}
+
+// WatchlistMatch represents a watchlist item that was found in a local library.
+type WatchlistMatch = GUIDMatch
+
 // ResolveWatchlist checks which watchlist items are available on local servers.
 // Returns a map from watchlist GUID to the first matching local item.
 func (m *Manager) ResolveWatchlist(ctx context.Context, items []watchlist.Item) map[string]WatchlistMatch {
-	sources := m.EnabledSources()
+	sources := m.refreshGUIDIndexes(ctx)
 	if len(sources) == 0 {
 		return nil
 	}
 
 	result := make(map[string]WatchlistMatch)
-
-	for _, src := range sources {
-		sections, err := src.LibrarySections(ctx)
-		if err != nil {
-			slog.Warn("failed to fetch library sections for watchlist resolve", "source", src.Name(), "error", err)
+	for _, item := range items {
+		if item.GUID == "" {
 			continue
 		}
-
-		// Build a map of section type → section keys for efficient lookup
-		sectionsByType := make(map[string][]string)
-		for _, sec := range sections {
-			sectionsByType[sec.Type] = append(sectionsByType[sec.Type], sec.Key)
-		}
-
-		for _, item := range items {
-			if item.GUID == "" {
-				continue
-			}
-			// Skip if already resolved
-			if _, ok := result[item.GUID]; ok {
-				continue
-			}
-
-			sectionKeys := sectionsByType[item.Type]
-			for _, sectionKey := range sectionKeys {
-				content, _, err := src.LibraryContent(ctx, sectionKey, &ContentOptions{
-					GUID: item.GUID,
-					Size: 1,
-				})
-				if err != nil {
-					continue
-				}
-				if len(content) > 0 {
-					result[item.GUID] = WatchlistMatch{
-						ServerID:  src.ID(),
-						RatingKey: content[0].RatingKey,
-						Type:      content[0].Type,
-					}
-					break
-				}
+		for _, src := range sources {
+			if match, ok := m.guidIndexFor(src.ID()).lookup(item.GUID); ok && match.Type == item.Type {
+				result[item.GUID] = match
+				break
 			}
 		}
 	}
-
 	return result
 }
 
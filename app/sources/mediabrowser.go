@@ -0,0 +1,560 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/provider/jellyfin"
+)
+
+// Keys of the hubs built by mediaBrowserSource. Library hubs are suffixed with
+// the section key, e.g. "added/<viewID>".
+const (
+	jellyfinHubResume   = "resume"
+	jellyfinHubNextUp   = "nextup"
+	jellyfinHubAdded    = "added/"
+	jellyfinHubReleased = "released/"
+)
+
+// jellyfinSectionItems are the item types listed in sections, by section
+// type. Recently added hubs list the types in jellyfinAddedItems instead.
+var (
+	jellyfinSectionItems = map[string][]string{
+		"movie": {jellyfin.TypeMovie},
+		"show":  {jellyfin.TypeSeries},
+	}
+	jellyfinAddedItems = map[string][]string{
+		"movie":  {jellyfin.TypeMovie},
+		"show":   {jellyfin.TypeEpisode},
+		"artist": {jellyfin.TypeMusicAlbum},
+		"photo":  {jellyfin.TypePhoto},
+	}
+)
+
+// jellyfinSearchTypes are the item types searched, one hub each.
+var jellyfinSearchTypes = []string{
+	jellyfin.TypeMovie,
+	jellyfin.TypeSeries,
+	jellyfin.TypeEpisode,
+	jellyfin.TypeMusicArtist,
+	jellyfin.TypeMusicAlbum,
+	jellyfin.TypeAudio,
+}
+
+// mediaBrowserClient is the API shared by Jellyfin and Emby servers, which
+// both descend from Media Browser.
+type mediaBrowserClient interface {
+	Views(ctx context.Context) ([]jellyfin.Item, error)
+	Items(ctx context.Context, opts *jellyfin.ItemsOptions) ([]jellyfin.Item, int, error)
+	Item(ctx context.Context, id string) (*jellyfin.Item, error)
+	Seasons(ctx context.Context, seriesID string) ([]jellyfin.Item, error)
+	Episodes(ctx context.Context, seriesID, seasonID string) ([]jellyfin.Item, error)
+	Resume(ctx context.Context, start, limit int) ([]jellyfin.Item, int, error)
+	NextUp(ctx context.Context, start, limit int) ([]jellyfin.Item, int, error)
+	AlbumArtists(ctx context.Context, opts *jellyfin.ItemsOptions) ([]jellyfin.Item, int, error)
+	Similar(ctx context.Context, id string, limit int) ([]jellyfin.Item, error)
+
+	ImageURL(path string, width, height int) string
+	StreamURL(path string) string
+	PlaybackInfo(ctx context.Context, itemID string, req *jellyfin.PlaybackInfoRequest) (*jellyfin.PlaybackInfo, error)
+	TranscodeURL(source *jellyfin.MediaSource, maxWidth, maxHeight int) string
+
+	ReportPlaybackStart(ctx context.Context, progress *jellyfin.PlaybackProgress) error
+	ReportPlaybackProgress(ctx context.Context, progress *jellyfin.PlaybackProgress) error
+	ReportPlaybackStopped(ctx context.Context, progress *jellyfin.PlaybackProgress) error
+	MarkPlayed(ctx context.Context, itemID string) error
+	MarkUnplayed(ctx context.Context, itemID string) error
+}
+
+// mediaBrowserSource adapts a Jellyfin or Emby client to the Source
+// interface. JellyfinSource and EmbySource embed it.
+type mediaBrowserSource struct {
+	client   mediaBrowserClient
+	serverID string
+	name     string
+
+	mu sync.Mutex
+	// sectionTypes caches the section type of each library view.
+	sectionTypes map[string]string
+	// sessions tracks the play sessions that progress is reported for.
+	sessions map[ItemID]*mediaBrowserSession
+}
+
+// mediaBrowserSession is the play session of an item.
+type mediaBrowserSession struct {
+	mediaSourceID string
+	playSessionID string
+
+	// started is set once playback start was reported.
+	started bool
+}
+
+func newMediaBrowserSource(serverID, name string, client mediaBrowserClient) *mediaBrowserSource {
+	return &mediaBrowserSource{
+		serverID:     serverID,
+		name:         name,
+		client:       client,
+		sectionTypes: make(map[string]string),
+		sessions:     make(map[ItemID]*mediaBrowserSession),
+	}
+}
+
+func (s *mediaBrowserSource) ID() string   { return s.serverID }
+func (s *mediaBrowserSource) Name() string { return s.name }
+
+// LibrarySections lists the user's views of supported collection types.
+func (s *mediaBrowserSource) LibrarySections(ctx context.Context) ([]LibrarySection, error) {
+	views, err := s.client.Views(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var out []LibrarySection
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range views {
+		section := jellyfinSection(&views[i])
+		if section.Type == "" {
+			continue
+		}
+		s.sectionTypes[section.Key] = section.Type
+		out = append(out, section)
+	}
+	return out, nil
+}
+
+func (s *mediaBrowserSource) LibrarySection(ctx context.Context, sectionID string) (*LibrarySection, error) {
+	sections, err := s.LibrarySections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range sections {
+		if sections[i].Key == sectionID {
+			return &sections[i], nil
+		}
+	}
+	return nil, fmt.Errorf("library section %s not found", sectionID)
+}
+
+// sectionType returns the type of a section, listing the sections if it
+// is not cached yet.
+func (s *mediaBrowserSource) sectionType(ctx context.Context, sectionID string) (string, error) {
+	s.mu.Lock()
+	sectionType, ok := s.sectionTypes[sectionID]
+	s.mu.Unlock()
+	if ok {
+		return sectionType, nil
+	}
+	section, err := s.LibrarySection(ctx, sectionID)
+	if err != nil {
+		return "", err
+	}
+	return section.Type, nil
+}
+
+// LibraryContent lists the movies or series of a video section, the album
+// artists of a music section, or the top-level folders and photos of a
+// photo section.
+func (s *mediaBrowserSource) LibraryContent(ctx context.Context, sectionID string, opts *ContentOptions) ([]Item, int, error) {
+	sectionType, err := s.sectionType(ctx, sectionID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if opts == nil {
+		opts = &ContentOptions{}
+	}
+
+	query := &jellyfin.ItemsOptions{
+		ParentID:   sectionID,
+		StartIndex: opts.Start,
+		Limit:      opts.Size,
+		SortBy:     jellyfin.SortByName,
+	}
+	if sortBy, ok := jellyfinSorts[opts.Sort]; ok {
+		query.SortBy = sortBy
+		query.Descending = opts.Sort != SortTitle
+	}
+	for _, filter := range []struct{ id, personType string }{
+		{opts.Actor, jellyfin.PersonActor},
+		{opts.Director, jellyfin.PersonDirector},
+		{opts.Writer, jellyfin.PersonWriter},
+	} {
+		if filter.id != "" {
+			query.PersonIDs = append(query.PersonIDs, filter.id)
+			query.PersonTypes = append(query.PersonTypes, filter.personType)
+		}
+	}
+	if opts.Genre != "" {
+		query.GenreIDs = []string{opts.Genre}
+	}
+	if opts.UpdatedSince > 0 {
+		query.MinDateLastSaved = time.Unix(opts.UpdatedSince, 0)
+	}
+	if opts.IncludeGUIDs {
+		query.Fields = []string{"ProviderIds"}
+	}
+
+	var items []jellyfin.Item
+	var total int
+	if sectionType == "artist" {
+		items, total, err = s.client.AlbumArtists(ctx, query)
+	} else {
+		if types := jellyfinSectionItems[sectionType]; types != nil {
+			query.Recursive = true
+			query.IncludeItemTypes = types
+		}
+		items, total, err = s.client.Items(ctx, query)
+	}
+	return inSection(jellyfinItems(items), sectionID), total, err
+}
+
+func (s *mediaBrowserSource) GetMetadata(ctx context.Context, id ItemID) (*Item, error) {
+	meta, err := s.client.Item(ctx, string(id))
+	if err != nil {
+		return nil, err
+	}
+	item := jellyfinItem(meta)
+	return &item, nil
+}
+
+// GetChildren returns the seasons of a series, the episodes of a season,
+// the albums of an artist, or the children of an album or folder.
+func (s *mediaBrowserSource) GetChildren(ctx context.Context, id ItemID) ([]Item, error) {
+	parent, err := s.client.Item(ctx, string(id))
+	if err != nil {
+		return nil, err
+	}
+
+	var children []jellyfin.Item
+	switch parent.Type {
+	case jellyfin.TypeSeries:
+		children, err = s.client.Seasons(ctx, string(id))
+	case jellyfin.TypeSeason:
+		children, err = s.client.Episodes(ctx, parent.SeriesID, string(id))
+	case jellyfin.TypeMusicArtist:
+		children, _, err = s.client.Items(ctx, &jellyfin.ItemsOptions{
+			Recursive:        true,
+			IncludeItemTypes: []string{jellyfin.TypeMusicAlbum},
+			AlbumArtistIDs:   []string{string(id)},
+			SortBy:           jellyfin.SortByPremiereDate,
+			Descending:       true,
+		})
+	default:
+		children, _, err = s.client.Items(ctx, &jellyfin.ItemsOptions{
+			ParentID: string(id),
+			SortBy:   jellyfin.SortByIndex,
+			Fields:   []string{"MediaSources"},
+		})
+	}
+	return jellyfinItems(children), err
+}
+
+// GetMarkers returns no markers; intros and credits are detected by server
+// plugins and not exposed with the item.
+func (s *mediaBrowserSource) GetMarkers(ctx context.Context, id ItemID) ([]Marker, error) {
+	return nil, nil
+}
+
+// GetLyrics is not supported by default; Emby delivers lyrics as subtitle
+// streams.
+func (s *mediaBrowserSource) GetLyrics(ctx context.Context, stream *Stream) (*Lyrics, error) {
+	return nil, errors.New("lyrics are not supported by this server")
+}
+
+// HomeHubs returns Continue Watching, Next Up and the recently added items
+// of each library.
+func (s *mediaBrowserSource) HomeHubs(ctx context.Context, count int) ([]Hub, error) {
+	var out []Hub
+	for _, key := range []string{jellyfinHubResume, jellyfinHubNextUp} {
+		hub, err := s.hub(ctx, key, count)
+		if err != nil {
+			return nil, err
+		}
+		if len(hub.Items) > 0 {
+			out = append(out, *hub)
+		}
+	}
+
+	sections, err := s.LibrarySections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range sections {
+		hub, err := s.hub(ctx, jellyfinHubAdded+section.Key, count)
+		if err != nil {
+			return nil, err
+		}
+		if len(hub.Items) > 0 {
+			hub.Title = gettext.Getf("Recently Added in %s", section.Title)
+			out = append(out, *hub)
+		}
+	}
+	return out, nil
+}
+
+// SectionHubs returns the recently added and, for video sections, the
+// recently released items of a section.
+func (s *mediaBrowserSource) SectionHubs(ctx context.Context, sectionID string) ([]Hub, error) {
+	sectionType, err := s.sectionType(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{jellyfinHubAdded + sectionID}
+	if sectionType == "movie" || sectionType == "show" {
+		keys = append(keys, jellyfinHubReleased+sectionID)
+	}
+
+	var out []Hub
+	for _, key := range keys {
+		hub, err := s.hub(ctx, key, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(hub.Items) > 0 {
+			out = append(out, *hub)
+		}
+	}
+	return out, nil
+}
+
+// hub returns a hub with a preview of up to count items (0 for the
+// default).
+func (s *mediaBrowserSource) hub(ctx context.Context, key string, count int) (*Hub, error) {
+	if count <= 0 {
+		count = 20
+	}
+	items, total, err := s.HubItems(ctx, key, 0, count)
+	if err != nil {
+		return nil, err
+	}
+	hub := &Hub{Key: key, Items: items, More: total > len(items)}
+	switch {
+	case key == jellyfinHubResume:
+		hub.Title = gettext.Get("Continue Watching")
+		hub.Kind = HubContinueWatching
+	case key == jellyfinHubNextUp:
+		hub.Title = gettext.Get("Next Up")
+		hub.Type = "episode"
+	case strings.HasPrefix(key, jellyfinHubAdded):
+		hub.Title = gettext.Get("Recently Added")
+	case strings.HasPrefix(key, jellyfinHubReleased):
+		hub.Title = gettext.Get("Recently Released")
+	}
+	if hub.Type == "" && len(items) > 0 {
+		hub.Type = items[0].Type
+	}
+	return hub, nil
+}
+
+func (s *mediaBrowserSource) HubItems(ctx context.Context, hubKey string, start, size int) ([]Item, int, error) {
+	var items []jellyfin.Item
+	var total int
+	var err error
+	switch hubKey {
+	case jellyfinHubResume:
+		items, total, err = s.client.Resume(ctx, start, size)
+	case jellyfinHubNextUp:
+		items, total, err = s.client.NextUp(ctx, start, size)
+	default:
+		query := &jellyfin.ItemsOptions{
+			Recursive:  true,
+			StartIndex: start,
+			Limit:      size,
+			Descending: true,
+		}
+		var sectionID string
+		if id, ok := strings.CutPrefix(hubKey, jellyfinHubAdded); ok {
+			sectionID, query.SortBy = id, jellyfin.SortByDateCreated
+		} else if id, ok := strings.CutPrefix(hubKey, jellyfinHubReleased); ok {
+			sectionID, query.SortBy = id, jellyfin.SortByPremiereDate
+		} else {
+			return nil, 0, fmt.Errorf("unknown hub %s", hubKey)
+		}
+		sectionType, err := s.sectionType(ctx, sectionID)
+		if err != nil {
+			return nil, 0, err
+		}
+		query.ParentID = sectionID
+		query.IncludeItemTypes = jellyfinAddedItems[sectionType]
+		if query.SortBy == jellyfin.SortByPremiereDate {
+			query.IncludeItemTypes = jellyfinSectionItems[sectionType]
+		}
+		items, total, err = s.client.Items(ctx, query)
+		return inSection(jellyfinItems(items), sectionID), total, err
+	}
+	return jellyfinItems(items), total, err
+}
+
+// RelatedHubs returns a hub of items similar to the given item.
+func (s *mediaBrowserSource) RelatedHubs(ctx context.Context, id ItemID) ([]Hub, error) {
+	similar, err := s.client.Similar(ctx, string(id), 20)
+	if err != nil || len(similar) == 0 {
+		return nil, err
+	}
+	items := jellyfinItems(similar)
+	return []Hub{{
+		Title: gettext.Get("More Like This"),
+		Type:  items[0].Type,
+		Items: items,
+	}}, nil
+}
+
+// Search returns a hub of up to limit matches per item type.
+func (s *mediaBrowserSource) Search(ctx context.Context, query string, limit int) ([]Hub, error) {
+	var out []Hub
+	for _, itemType := range jellyfinSearchTypes {
+		items, _, err := s.client.Items(ctx, &jellyfin.ItemsOptions{
+			SearchTerm:       query,
+			Recursive:        true,
+			IncludeItemTypes: []string{itemType},
+			Limit:            limit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			continue
+		}
+		out = append(out, Hub{
+			Title: jellyfinSearchTitle(itemType),
+			Type:  jellyfinTypes[itemType],
+			Items: jellyfinItems(items),
+		})
+	}
+	return out, nil
+}
+
+// jellyfinSearchTitle returns the title of the search hub of an item type.
+func jellyfinSearchTitle(itemType string) string {
+	switch itemType {
+	case jellyfin.TypeMovie:
+		return gettext.Get("Movies")
+	case jellyfin.TypeSeries:
+		return gettext.Get("Shows")
+	case jellyfin.TypeEpisode:
+		return gettext.Get("Episodes")
+	case jellyfin.TypeMusicArtist:
+		return gettext.Get("Artists")
+	case jellyfin.TypeMusicAlbum:
+		return gettext.Get("Albums")
+	default:
+		return gettext.Get("Tracks")
+	}
+}
+
+func (s *mediaBrowserSource) PhotoTranscodeURL(path string, width, height int) string {
+	return s.client.ImageURL(path, width, height)
+}
+
+func (s *mediaBrowserSource) StreamURL(partKey string) string {
+	return s.client.StreamURL(partKey)
+}
+
+// DecidePlayback plays parts directly from their stream path. Transcodes
+// are negotiated with PlaybackInfo, which opens a play session that
+// progress reports refer to.
+func (s *mediaBrowserSource) DecidePlayback(ctx context.Context, req PlaybackRequest) (*PlaybackDecision, error) {
+	if req.Path != "" {
+		return nil, errors.New("playback of paths is not supported by this server")
+	}
+	mediaSourceID := jellyfinMediaSourceID(req.PartKey)
+	if !req.Transcode {
+		s.setSession(req.ItemID, &mediaBrowserSession{mediaSourceID: mediaSourceID, playSessionID: req.SessionID})
+		return &PlaybackDecision{URL: s.client.StreamURL(req.PartKey)}, nil
+	}
+
+	infoReq := &jellyfin.PlaybackInfoRequest{
+		MediaSourceID:       mediaSourceID,
+		MaxStreamingBitrate: req.MaxBitrate * 1000,
+		StartTimeTicks:      int64(req.Offset) * 1000 * jellyfin.TicksPerMillisecond,
+		EnableTranscoding:   true,
+		DeviceProfile:       jellyfin.NewDeviceProfile(req.SubtitleStreamID > 0),
+	}
+	// Stream IDs are the stream index plus one (see jellyfinStream).
+	if req.AudioStreamID > 0 {
+		index := req.AudioStreamID - 1
+		infoReq.AudioStreamIndex = &index
+	}
+	if req.SubtitleStreamID > 0 {
+		index := req.SubtitleStreamID - 1
+		infoReq.SubtitleStreamIndex = &index
+	}
+	info, err := s.client.PlaybackInfo(ctx, string(req.ItemID), infoReq)
+	if err != nil {
+		return nil, err
+	}
+	for i := range info.MediaSources {
+		source := &info.MediaSources[i]
+		if source.TranscodingURL == "" {
+			continue
+		}
+		s.setSession(req.ItemID, &mediaBrowserSession{mediaSourceID: source.ID, playSessionID: info.PlaySessionID})
+		var width, height int
+		if req.MaxResolution != "" {
+			fmt.Sscanf(req.MaxResolution, "%dx%d", &width, &height)
+		}
+		return &PlaybackDecision{URL: s.client.TranscodeURL(source, width, height), Transcoded: true}, nil
+	}
+	return nil, errors.New("server offered no transcoded stream")
+}
+
+// jellyfinMediaSourceID returns the media source of a stream path (see
+// jellyfinMedia).
+func jellyfinMediaSourceID(partKey string) string {
+	_, rawQuery, _ := strings.Cut(partKey, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	return query.Get("mediaSourceId")
+}
+
+func (s *mediaBrowserSource) setSession(id ItemID, session *mediaBrowserSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[id] = session
+}
+
+func (s *mediaBrowserSource) Scrobble(ctx context.Context, id ItemID) error {
+	return s.client.MarkPlayed(ctx, string(id))
+}
+
+func (s *mediaBrowserSource) Unscrobble(ctx context.Context, id ItemID) error {
+	return s.client.MarkUnplayed(ctx, string(id))
+}
+
+// UpdateProgress reports the start of playback with the first report of an
+// item, and ends its play session when playback stops.
+func (s *mediaBrowserSource) UpdateProgress(ctx context.Context, id ItemID, state PlaybackState, timeMs, durationMs int) error {
+	s.mu.Lock()
+	session := s.sessions[id]
+	if session == nil {
+		session = &mediaBrowserSession{}
+		s.sessions[id] = session
+	}
+	started := session.started
+	session.started = true
+	if state == StateStopped {
+		delete(s.sessions, id)
+	}
+	s.mu.Unlock()
+
+	progress := &jellyfin.PlaybackProgress{
+		ItemID:        string(id),
+		MediaSourceID: session.mediaSourceID,
+		PlaySessionID: session.playSessionID,
+		PositionTicks: int64(timeMs) * jellyfin.TicksPerMillisecond,
+		IsPaused:      state == StatePaused,
+		CanSeek:       true,
+	}
+	switch {
+	case state == StateStopped:
+		return s.client.ReportPlaybackStopped(ctx, progress)
+	case !started:
+		return s.client.ReportPlaybackStart(ctx, progress)
+	default:
+		return s.client.ReportPlaybackProgress(ctx, progress)
+	}
+}
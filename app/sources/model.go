@@ -0,0 +1,403 @@
+package sources
+
+import "strings"
+
+// ItemID identifies an item on its source. It is opaque: UI code passes it
+// back to the source it came from and never parses it. Sources convert it
+// to and from the identifiers of their provider's API.
+type ItemID string
+
+// Item is a media item: a movie, show, season, episode, artist, album,
+// track, photo album or photo.
+type Item struct {
+	// ID identifies the item on its source.
+	ID ItemID
+
+	// GUID is the item's identifier in the provider's metadata agent
+	// (e.g. "plex://movie/5d776825880197001ec967c9"). Empty if unmatched.
+	GUID string
+
+	// ExternalIDs are identifiers in external databases, such as
+	// "imdb://tt0133093" or "tmdb://603". Only set when requested (see
+	// ContentOptions.IncludeGUIDs).
+	ExternalIDs []string
+
+	// Type is the item type (movie, show, season, episode, artist, album,
+	// track, photo, photoalbum).
+	Type string
+
+	// SectionID is the key of the library section the item belongs to.
+	// Empty if the source does not report it for the request.
+	SectionID string
+
+	Title     string
+	TitleSort string
+
+	// OriginalTitle is the original title of foreign content. For tracks
+	// it holds the track artist when it differs from the album artist.
+	OriginalTitle string
+
+	Summary string
+	Tagline string
+	Year    int
+	Studio  string
+
+	// OriginallyAvailableAt is the release date (YYYY-MM-DD).
+	OriginallyAvailableAt string
+
+	// Thumb and Art are image paths, resolved with Source.PhotoTranscodeURL.
+	Thumb string
+	Art   string
+
+	// Duration is the runtime in milliseconds.
+	Duration int
+
+	// ViewOffset is the resume position in milliseconds.
+	ViewOffset int
+
+	// ViewCount is the number of times the item was watched.
+	ViewCount int
+
+	// LastViewedAt is the Unix timestamp of when the item was last watched
+	// or played, 0 if unknown.
+	LastViewedAt int64
+
+	// ChildCount is the number of direct children (seasons of a show,
+	// episodes of a season).
+	ChildCount int
+
+	// LeafCount and ViewedLeafCount count the playable descendants
+	// (episodes of a show) and how many of them were watched.
+	LeafCount       int
+	ViewedLeafCount int
+
+	// AddedAt and UpdatedAt are Unix timestamps.
+	AddedAt   int64
+	UpdatedAt int64
+
+	// Index is the position within the parent (episode or track number);
+	// ParentIndex is the parent's position (season or disc number).
+	Index       int
+	ParentIndex int
+
+	ParentID    ItemID
+	ParentTitle string
+	ParentYear  int
+	ParentThumb string
+
+	GrandparentID    ItemID
+	GrandparentTitle string
+	GrandparentThumb string
+
+	ContentRating string
+	Ratings       Ratings
+	UserRating    float64
+
+	// Media lists the available versions of the item.
+	Media []MediaVersion
+
+	Genre    []Tag
+	Director []Tag
+	Writer   []Tag
+	Cast     []Tag
+}
+
+// MediaVersion is one encoding of an item, e.g. a 4K and a 1080p file of
+// the same movie.
+type MediaVersion struct {
+	ID       int
+	Duration int // milliseconds
+	Bitrate  int // kbps
+
+	Width         int
+	Height        int
+	AspectRatio   float64
+	AudioChannels int
+	AudioCodec    string
+	VideoCodec    string
+
+	// VideoResolution is a resolution label such as "1080", "720" or "4k".
+	VideoResolution string
+
+	Container string
+
+	// Photo capture details from EXIF data.
+	Aperture string
+	Exposure string
+	ISO      int
+	Lens     string
+	Make     string
+	Model    string
+
+	Part []MediaPart
+}
+
+// MediaPart is a file of a media version.
+type MediaPart struct {
+	ID int
+
+	// Key identifies the part for Source.StreamURL and
+	// Source.PhotoTranscodeURL.
+	Key string
+
+	Duration  int    // milliseconds
+	File      string // path on the server
+	Size      int64  // bytes
+	Container string
+
+	Stream []Stream
+}
+
+// Stream types.
+const (
+	StreamVideo    = 1
+	StreamAudio    = 2
+	StreamSubtitle = 3
+	StreamLyrics   = 4
+)
+
+// Stream is a video, audio, subtitle or lyrics stream of a media part.
+type Stream struct {
+	// ID selects the stream in a PlaybackRequest.
+	ID int
+
+	// StreamType is one of the Stream* constants.
+	StreamType int
+
+	Codec        string
+	Index        int
+	Bitrate      int // kbps
+	Language     string
+	LanguageCode string
+	Title        string
+	DisplayTitle string
+
+	Channels     int
+	SamplingRate int // Hz
+	BitDepth     int
+
+	Width     int
+	Height    int
+	FrameRate float64
+
+	Selected bool
+	Default  bool
+	Forced   bool
+
+	// Loudness analysis, used for volume normalization. Gains are in dB,
+	// peaks in amplitude (1.0 = full scale).
+	Gain      float64
+	AlbumGain float64
+	Peak      float64
+	AlbumPeak float64
+
+	// Key fetches the content of lyrics and sidecar subtitle streams (see
+	// Source.GetLyrics).
+	Key string
+
+	// Format is the content format of lyrics and subtitle streams (e.g.
+	// "lrc", "txt", "srt").
+	Format string
+}
+
+// LyricsStream returns the preferred lyrics stream of the part, favoring
+// synced (LRC) lyrics over plain text. It returns nil when there is none.
+func (p *MediaPart) LyricsStream() *Stream {
+	var found *Stream
+	for i := range p.Stream {
+		s := &p.Stream[i]
+		if s.StreamType != StreamLyrics || s.Key == "" {
+			continue
+		}
+		if strings.EqualFold(s.Format, "lrc") {
+			return s
+		}
+		if found == nil {
+			found = s
+		}
+	}
+	return found
+}
+
+// Tag is a genre or a credited person. For cast members Role is the
+// character name.
+type Tag struct {
+	// ID identifies the tag for ContentOptions filters.
+	ID    string
+	Name  string
+	Role  string
+	Thumb string
+}
+
+// Rating sources.
+const (
+	RatingRottenTomatoes = "rottentomatoes"
+	RatingIMDb           = "imdb"
+	RatingTMDb           = "tmdb"
+)
+
+// Rating is a critic or audience rating.
+type Rating struct {
+	// Source is one of the Rating* constants, or empty if unknown.
+	Source string
+
+	// Type is "critic" or "audience".
+	Type string
+
+	// Value is on a 10-point scale.
+	Value float64
+}
+
+// Ratings is the list of ratings of an item.
+type Ratings []Rating
+
+// Marker is a section of an item's timeline, such as an intro or the
+// credits.
+type Marker struct {
+	// Type is the marker type ("intro", "credits").
+	Type string
+
+	// Final indicates the last marker of its type.
+	Final bool
+
+	// Offsets in milliseconds.
+	StartTimeOffset int
+	EndTimeOffset   int
+}
+
+// Lyrics is the content of a lyrics stream.
+type Lyrics struct {
+	// Format is "lrc" for synced lyrics or "txt" for plain text.
+	Format string
+
+	Text string
+}
+
+// LibrarySection is a library of a source (e.g. Movies, TV Shows).
+type LibrarySection struct {
+	// Key identifies the section on its source.
+	Key string
+
+	// Type is the type of the section's items (movie, show, artist, photo).
+	Type  string
+	Title string
+	Thumb string
+	Art   string
+
+	// UpdatedAt, ScannedAt and ContentChangedAt are Unix timestamps.
+	UpdatedAt        int64
+	ScannedAt        int64
+	ContentChangedAt int64
+}
+
+// Sort orders accepted by ContentOptions.
+const (
+	SortTitle      = "title"
+	SortAddedAt    = "added"
+	SortReleasedAt = "released"
+)
+
+// ContentOptions filters and pages the items of a library section.
+type ContentOptions struct {
+	Start int
+	Size  int
+
+	// Sort is one of the Sort* constants; empty for the source's default.
+	Sort string
+
+	// Tag filters, by Tag.ID.
+	Actor    string
+	Director string
+	Writer   string
+	Genre    string
+
+	// UpdatedSince limits results to items updated at or after this Unix
+	// timestamp.
+	UpdatedSince int64
+
+	// IncludeGUIDs fills in Item.ExternalIDs.
+	IncludeGUIDs bool
+}
+
+// Hub kinds with special presentation.
+const (
+	HubContinueWatching = "continue"
+	HubOnDeck           = "ondeck"
+)
+
+// Hub is a row of items, such as "Recently Added" or a search result
+// group.
+type Hub struct {
+	// Key fetches the hub's items with Source.HubItems.
+	Key string
+
+	Title string
+
+	// Type is the type of the hub's items.
+	Type string
+
+	// Kind is one of the Hub* constants, or empty for an ordinary hub.
+	Kind string
+
+	// More indicates that Items is a preview and the hub has more items.
+	More bool
+
+	Items []Item
+}
+
+// PlaybackRequest asks a source for a stream of an item.
+type PlaybackRequest struct {
+	ItemID ItemID
+
+	// PartKey is the media part to play.
+	PartKey string
+
+	// Path overrides the item, e.g. to stream a tuned live TV session (see
+	// LiveSession.Key).
+	Path string
+
+	// SessionID ties the stream to a playback session for progress
+	// reporting.
+	SessionID string
+
+	// Transcode requests a transcoded stream. Otherwise the part is played
+	// directly, and the settings below are ignored.
+	Transcode bool
+
+	// DirectStreamAudio passes the audio stream through untouched.
+	DirectStreamAudio bool
+
+	// MaxBitrate is in kbps, 0 for the original quality.
+	MaxBitrate int
+
+	// MaxResolution is "WxH", empty for the original resolution.
+	MaxResolution string
+
+	// AudioStreamID and SubtitleStreamID select streams by Stream.ID. A
+	// subtitle stream is burned in.
+	AudioStreamID    int
+	SubtitleStreamID int
+
+	// Offset is the start position in seconds.
+	Offset int
+}
+
+// PlaybackDecision is a source's answer to a PlaybackRequest.
+type PlaybackDecision struct {
+	// URL is the stream to play.
+	URL string
+
+	// Transcoded reports whether the stream is transcoded, in which case
+	// seeking requires a new request with an Offset.
+	Transcoded bool
+}
+
+// PlaybackState is the state reported with playback progress.
+type PlaybackState string
+
+// Playback states.
+const (
+	StatePlaying PlaybackState = "playing"
+	StatePaused  PlaybackState = "paused"
+	StateStopped PlaybackState = "stopped"
+)
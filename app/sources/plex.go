@@ -2,16 +2,23 @@ package sources
 
 import (
 	"context"
-	"net/url"
+	"time"
 
 	"github.com/0skillallluck/scanline/provider/plex"
+	"github.com/0skillallluck/scanline/provider/plex/library"
+	"github.com/0skillallluck/scanline/provider/plex/timeline"
 )
 
-// PlexSource adapts a plex.Client to the Source interface.
+// PlexSource adapts a plex.Client to the Source interface. Besides the core
+// interface it implements HistorySource, AdminSource and LiveTVSource.
 type PlexSource struct {
 	client   *plex.Client
 	serverID string
 	name     string
+
+	// failover routes requests between the server's connections; nil for
+	// sources created outside the Manager.
+	failover *connectionFailover
 }
 
 // NewPlexSource creates a Source that wraps a Plex Media Server client.
@@ -23,8 +30,8 @@ func NewPlexSource(serverID, name string, client *plex.Client) *PlexSource {
 	}
 }
 
-// PlexClient returns the underlying plex.Client for provider-specific operations
-// (e.g. transcoding, timeline). Avoid using this for general media access.
+// PlexClient returns the underlying plex.Client for provider-specific
+// operations. Avoid using this for general media access.
 func (s *PlexSource) PlexClient() *plex.Client {
 	return s.client
 }
@@ -33,39 +40,81 @@ func (s *PlexSource) ID() string   { return s.serverID }
 func (s *PlexSource) Name() string { return s.name }
 
 func (s *PlexSource) LibrarySections(ctx context.Context) ([]LibrarySection, error) {
-	return s.client.Library.Sections(ctx)
+	sections, err := s.client.Library.Sections(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]LibrarySection, len(sections))
+	for i := range sections {
+		out[i] = plexSection(&sections[i])
+	}
+	return out, nil
 }
 
 func (s *PlexSource) LibrarySection(ctx context.Context, sectionID string) (*LibrarySection, error) {
-	return s.client.Library.Section(ctx, sectionID)
+	section, err := s.client.Library.Section(ctx, sectionID)
+	if err != nil {
+		return nil, err
+	}
+	out := plexSection(section)
+	return &out, nil
 }
 
-func (s *PlexSource) LibraryContent(ctx context.Context, sectionID string, opts *ContentOptions) ([]Metadata, int, error) {
-	return s.client.Library.Content(ctx, sectionID, opts)
+func (s *PlexSource) LibraryContent(ctx context.Context, sectionID string, opts *ContentOptions) ([]Item, int, error) {
+	items, total, err := s.client.Library.Content(ctx, sectionID, plexContentOptions(opts))
+	return plexItems(items), total, err
 }
 
-func (s *PlexSource) GetMetadata(ctx context.Context, key string) (*Metadata, error) {
-	return s.client.Library.Metadata(ctx, key)
+func (s *PlexSource) GetMetadata(ctx context.Context, id ItemID) (*Item, error) {
+	meta, err := s.client.Library.Metadata(ctx, string(id))
+	if err != nil {
+		return nil, err
+	}
+	item := plexItem(meta)
+	return &item, nil
 }
 
-func (s *PlexSource) GetChildren(ctx context.Context, key string) ([]Metadata, error) {
-	return s.client.Library.Children(ctx, key)
+func (s *PlexSource) GetChildren(ctx context.Context, id ItemID) ([]Item, error) {
+	children, err := s.client.Library.Children(ctx, string(id))
+	return plexItems(children), err
 }
 
-func (s *PlexSource) GetMarkers(ctx context.Context, key string) ([]Marker, error) {
-	return s.client.Library.Markers(ctx, key)
+func (s *PlexSource) GetMarkers(ctx context.Context, id ItemID) ([]Marker, error) {
+	markers, err := s.client.Library.Markers(ctx, string(id))
+	return plexMarkers(markers), err
+}
+
+func (s *PlexSource) GetLyrics(ctx context.Context, stream *Stream) (*Lyrics, error) {
+	lyrics, err := s.client.Library.Lyrics(ctx, &library.Stream{Key: stream.Key, Format: stream.Format})
+	if err != nil {
+		return nil, err
+	}
+	return &Lyrics{Format: lyrics.Format, Text: lyrics.Text}, nil
 }
 
 func (s *PlexSource) HomeHubs(ctx context.Context, count int) ([]Hub, error) {
-	return s.client.Hubs.Home(ctx, count)
+	hs, err := s.client.Hubs.Home(ctx, count)
+	return plexHubs(hs), err
+}
+
+func (s *PlexSource) SectionHubs(ctx context.Context, sectionID string) ([]Hub, error) {
+	hs, err := s.client.Hubs.Section(ctx, sectionID)
+	return plexHubs(hs), err
+}
+
+func (s *PlexSource) HubItems(ctx context.Context, hubKey string, start, size int) ([]Item, int, error) {
+	items, total, err := s.client.Hubs.Items(ctx, hubKey, start, size)
+	return plexItems(items), total, err
 }
 
-func (s *PlexSource) RelatedHubs(ctx context.Context, key string) ([]Hub, error) {
-	return s.client.Hubs.Related(ctx, key)
+func (s *PlexSource) RelatedHubs(ctx context.Context, id ItemID) ([]Hub, error) {
+	hs, err := s.client.Hubs.Related(ctx, string(id))
+	return plexHubs(hs), err
 }
 
 func (s *PlexSource) Search(ctx context.Context, query string, limit int) ([]Hub, error) {
-	return s.client.Search.Query(ctx, query, limit)
+	hs, err := s.client.Search.Query(ctx, query, limit)
+	return plexHubs(hs), err
 }
 
 func (s *PlexSource) PhotoTranscodeURL(path string, width, height int) string {
@@ -76,30 +125,94 @@ func (s *PlexSource) StreamURL(partKey string) string {
 	return s.client.StreamURL(partKey)
 }
 
-func (s *PlexSource) ResolvePlaybackURL(ctx context.Context, partKey, ratingKey, sessionID string) string {
-	return s.client.ResolvePlaybackURL(ctx, partKey, ratingKey, sessionID)
+// DecidePlayback calls the Plex decision endpoint, which also registers the
+// session for progress reporting. Direct play falls back to the part's
+// stream URL when the decision fails.
+func (s *PlexSource) DecidePlayback(ctx context.Context, req PlaybackRequest) (*PlaybackDecision, error) {
+	if !req.Transcode {
+		return &PlaybackDecision{URL: s.client.ResolvePlaybackURL(ctx, req.PartKey, string(req.ItemID), req.SessionID)}, nil
+	}
+	q := s.client.BuildTranscodeQuery(plex.TranscodeParams{
+		RatingKey:         string(req.ItemID),
+		Path:              req.Path,
+		SessionID:         req.SessionID,
+		DirectStreamAudio: req.DirectStreamAudio,
+		MaxBitrate:        req.MaxBitrate,
+		MaxResolution:     req.MaxResolution,
+		AudioStreamID:     req.AudioStreamID,
+		SubtitleStreamID:  req.SubtitleStreamID,
+		Offset:            req.Offset,
+	})
+	if err := s.client.MakeTranscodeDecision(ctx, q); err != nil {
+		return nil, err
+	}
+	return &PlaybackDecision{URL: s.client.TranscodeStartURL(q), Transcoded: true}, nil
+}
+
+func (s *PlexSource) Scrobble(ctx context.Context, id ItemID) error {
+	return s.client.Timeline.Scrobble(ctx, string(id))
+}
+
+func (s *PlexSource) Unscrobble(ctx context.Context, id ItemID) error {
+	return s.client.Timeline.Unscrobble(ctx, string(id))
+}
+
+func (s *PlexSource) UpdateProgress(ctx context.Context, id ItemID, state PlaybackState, timeMs, durationMs int) error {
+	return s.client.Timeline.UpdateProgress(ctx, string(id), timeline.PlaybackState(state), timeMs, durationMs)
+}
+
+func (s *PlexSource) History(ctx context.Context, opts *HistoryOptions) ([]HistoryEntry, int, error) {
+	return s.client.History.List(ctx, opts)
+}
+
+func (s *PlexSource) Accounts(ctx context.Context) ([]ServerAccount, error) {
+	return s.client.Server.Accounts(ctx)
+}
+
+func (s *PlexSource) Sessions(ctx context.Context) ([]Session, error) {
+	return s.client.Server.Sessions(ctx)
+}
+
+func (s *PlexSource) TerminateSession(ctx context.Context, sessionID, reason string) error {
+	return s.client.Server.TerminateSession(ctx, sessionID, reason)
+}
+
+func (s *PlexSource) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	return s.client.Server.Info(ctx)
+}
+
+func (s *PlexSource) Preferences(ctx context.Context) ([]ServerSetting, error) {
+	return s.client.Server.Preferences(ctx)
+}
+
+func (s *PlexSource) SetPreferences(ctx context.Context, values map[string]string) error {
+	return s.client.Server.SetPreferences(ctx, values)
+}
+
+func (s *PlexSource) DVRs(ctx context.Context) ([]DVR, error) {
+	return s.client.LiveTV.DVRs(ctx)
 }
 
-func (s *PlexSource) BuildTranscodeQuery(params TranscodeParams) url.Values {
-	return s.client.BuildTranscodeQuery(params)
+func (s *PlexSource) Channels(ctx context.Context, dvr *DVR) ([]Channel, error) {
+	return s.client.LiveTV.Channels(ctx, dvr)
 }
 
-func (s *PlexSource) MakeTranscodeDecision(ctx context.Context, q url.Values) error {
-	return s.client.MakeTranscodeDecision(ctx, q)
+func (s *PlexSource) Guide(ctx context.Context, dvr *DVR, start, end time.Time) ([]Program, error) {
+	return s.client.LiveTV.Grid(ctx, dvr, start, end)
 }
 
-func (s *PlexSource) TranscodeStartURL(q url.Values) string {
-	return s.client.TranscodeStartURL(q)
+func (s *PlexSource) TuneChannel(ctx context.Context, dvrKey, channel string) (*LiveSession, error) {
+	return s.client.LiveTV.Tune(ctx, dvrKey, channel)
 }
 
-func (s *PlexSource) Scrobble(ctx context.Context, ratingKey string) error {
-	return s.client.Timeline.Scrobble(ctx, ratingKey)
+func (s *PlexSource) Subscriptions(ctx context.Context) ([]Subscription, error) {
+	return s.client.LiveTV.Subscriptions(ctx)
 }
 
-func (s *PlexSource) Unscrobble(ctx context.Context, ratingKey string) error {
-	return s.client.Timeline.Unscrobble(ctx, ratingKey)
+func (s *PlexSource) ScheduleRecording(ctx context.Context, opts ScheduleOptions) (*Subscription, error) {
+	return s.client.LiveTV.Schedule(ctx, opts)
 }
 
-func (s *PlexSource) UpdateProgress(ctx context.Context, ratingKey string, state PlaybackState, timeMs, durationMs int) error {
-	return s.client.Timeline.UpdateProgress(ctx, ratingKey, state, timeMs, durationMs)
+func (s *PlexSource) CancelSubscription(ctx context.Context, key string) error {
+	return s.client.LiveTV.CancelSubscription(ctx, key)
 }
@@ -0,0 +1,250 @@
+package sources
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/0skillallluck/scanline/provider/plex/hubs"
+	"github.com/0skillallluck/scanline/provider/plex/library"
+)
+
+// Mapping between Plex API types and the provider-neutral model.
+
+func plexItems(items []library.Metadata) []Item {
+	if items == nil {
+		return nil
+	}
+	out := make([]Item, len(items))
+	for i := range items {
+		out[i] = plexItem(&items[i])
+	}
+	return out
+}
+
+func plexItem(m *library.Metadata) Item {
+	item := Item{
+		ID:                    ItemID(m.RatingKey),
+		GUID:                  m.GUID,
+		Type:                  m.Type,
+		Title:                 m.Title,
+		TitleSort:             m.TitleSort,
+		OriginalTitle:         m.OriginalTitle,
+		Summary:               m.Summary,
+		Tagline:               m.Tagline,
+		Year:                  m.Year,
+		Studio:                m.Studio,
+		OriginallyAvailableAt: m.OriginallyAvailableAt,
+		Thumb:                 m.Thumb,
+		Art:                   m.Art,
+		Duration:              m.Duration,
+		ViewOffset:            m.ViewOffset,
+		ViewCount:             m.ViewCount,
+		LastViewedAt:          m.LastViewedAt,
+		ChildCount:            m.ChildCount,
+		LeafCount:             m.LeafCount,
+		ViewedLeafCount:       m.ViewedLeafCount,
+		AddedAt:               m.AddedAt,
+		UpdatedAt:             m.UpdatedAt,
+		Index:                 m.Index,
+		ParentIndex:           m.ParentIndex,
+		ParentID:              ItemID(m.ParentRatingKey),
+		ParentTitle:           m.ParentTitle,
+		ParentYear:            m.ParentYear,
+		ParentThumb:           m.ParentThumb,
+		GrandparentID:         ItemID(m.GrandparentRatingKey),
+		GrandparentTitle:      m.GrandparentTitle,
+		GrandparentThumb:      m.GrandparentThumb,
+		ContentRating:         m.ContentRating,
+		Ratings:               plexRatings(m.Ratings),
+		UserRating:            m.UserRating,
+		Genre:                 plexTags(m.Genre),
+		Director:              plexTags(m.Director),
+		Writer:                plexTags(m.Writer),
+		Cast:                  plexTags(m.Role),
+	}
+	for _, g := range m.ExternalGUIDs {
+		item.ExternalIDs = append(item.ExternalIDs, g.ID)
+	}
+	if len(m.Media) > 0 {
+		item.Media = make([]MediaVersion, len(m.Media))
+		for i := range m.Media {
+			item.Media[i] = plexMedia(&m.Media[i])
+		}
+	}
+	if m.LibrarySectionID != 0 {
+		item.SectionID = strconv.Itoa(m.LibrarySectionID)
+	}
+	return item
+}
+
+func plexMedia(m *library.Media) MediaVersion {
+	media := MediaVersion{
+		ID:              m.ID,
+		Duration:        m.Duration,
+		Bitrate:         m.Bitrate,
+		Width:           m.Width,
+		Height:          m.Height,
+		AspectRatio:     m.AspectRatio,
+		AudioChannels:   m.AudioChannels,
+		AudioCodec:      m.AudioCodec,
+		VideoCodec:      m.VideoCodec,
+		VideoResolution: m.VideoResolution,
+		Container:       m.Container,
+		Aperture:        m.Aperture,
+		Exposure:        m.Exposure,
+		ISO:             m.ISO,
+		Lens:            m.Lens,
+		Make:            m.Make,
+		Model:           m.Model,
+	}
+	for i := range m.Part {
+		p := &m.Part[i]
+		part := MediaPart{
+			ID:        p.ID,
+			Key:       p.Key,
+			Duration:  p.Duration,
+			File:      p.File,
+			Size:      p.Size,
+			Container: p.Container,
+		}
+		for j := range p.Stream {
+			part.Stream = append(part.Stream, plexStream(&p.Stream[j]))
+		}
+		media.Part = append(media.Part, part)
+	}
+	return media
+}
+
+func plexStream(s *library.Stream) Stream {
+	return Stream{
+		ID:           s.ID,
+		StreamType:   s.StreamType,
+		Codec:        s.Codec,
+		Index:        s.Index,
+		Bitrate:      s.Bitrate,
+		Language:     s.Language,
+		LanguageCode: s.LanguageCode,
+		Title:        s.Title,
+		DisplayTitle: s.DisplayTitle,
+		Channels:     s.Channels,
+		SamplingRate: s.SamplingRate,
+		BitDepth:     s.BitDepth,
+		Width:        s.Width,
+		Height:       s.Height,
+		FrameRate:    s.FrameRate,
+		Selected:     s.Selected,
+		Default:      s.Default,
+		Forced:       s.Forced,
+		Gain:         float64(s.Gain),
+		AlbumGain:    float64(s.AlbumGain),
+		Peak:         float64(s.Peak),
+		AlbumPeak:    float64(s.AlbumPeak),
+		Key:          s.Key,
+		Format:       s.Format,
+	}
+}
+
+func plexTags(tags []library.Tag) []Tag {
+	if tags == nil {
+		return nil
+	}
+	out := make([]Tag, len(tags))
+	for i, t := range tags {
+		out[i] = Tag{ID: strconv.Itoa(t.ID), Name: t.Tag, Role: t.Role, Thumb: t.Thumb}
+	}
+	return out
+}
+
+// plexRatings maps ratings, identifying their source from the rating image
+// URI (e.g. "rottentomatoes://image.rating.ripe").
+func plexRatings(ratings library.Ratings) Ratings {
+	if ratings == nil {
+		return nil
+	}
+	out := make(Ratings, len(ratings))
+	for i, r := range ratings {
+		out[i] = Rating{Type: r.Type, Value: r.Value}
+		scheme, _, _ := strings.Cut(r.Image, "://")
+		switch scheme {
+		case "rottentomatoes":
+			out[i].Source = RatingRottenTomatoes
+		case "imdb":
+			out[i].Source = RatingIMDb
+		case "themoviedb":
+			out[i].Source = RatingTMDb
+		}
+	}
+	return out
+}
+
+func plexMarkers(markers []library.Marker) []Marker {
+	out := make([]Marker, len(markers))
+	for i, m := range markers {
+		out[i] = Marker{
+			Type:            m.Type,
+			Final:           m.Final,
+			StartTimeOffset: m.StartTimeOffset,
+			EndTimeOffset:   m.EndTimeOffset,
+		}
+	}
+	return out
+}
+
+func plexSection(s *library.LibrarySection) LibrarySection {
+	return LibrarySection{
+		Key:              s.Key,
+		Type:             s.Type,
+		Title:            s.Title,
+		Thumb:            s.Thumb,
+		Art:              s.Art,
+		UpdatedAt:        s.UpdatedAt,
+		ScannedAt:        s.ScannedAt,
+		ContentChangedAt: s.ContentChangedAt,
+	}
+}
+
+// plexSorts maps the Sort* constants to Plex sort parameters.
+var plexSorts = map[string]string{
+	SortTitle:      "titleSort",
+	SortAddedAt:    "addedAt:desc",
+	SortReleasedAt: "originallyAvailableAt:desc",
+}
+
+func plexContentOptions(opts *ContentOptions) *library.ContentOptions {
+	if opts == nil {
+		return nil
+	}
+	return &library.ContentOptions{
+		Start:        opts.Start,
+		Size:         opts.Size,
+		Sort:         plexSorts[opts.Sort],
+		Actor:        opts.Actor,
+		Director:     opts.Director,
+		Writer:       opts.Writer,
+		Genre:        opts.Genre,
+		UpdatedSince: opts.UpdatedSince,
+		IncludeGUIDs: opts.IncludeGUIDs,
+	}
+}
+
+// plexHubKinds maps Plex hub identifiers to the Hub* constants.
+var plexHubKinds = map[string]string{
+	"home.continue": HubContinueWatching,
+	"hub.ondeck":    HubOnDeck,
+}
+
+func plexHubs(hs []hubs.Hub) []Hub {
+	out := make([]Hub, len(hs))
+	for i := range hs {
+		h := &hs[i]
+		out[i] = Hub{
+			Key:   h.Key,
+			Title: h.Title,
+			Type:  h.Type,
+			Kind:  plexHubKinds[h.HubIdentifier],
+			More:  h.More,
+			Items: plexItems(h.Metadata),
+		}
+	}
+	return out
+}
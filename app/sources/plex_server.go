@@ -0,0 +1,146 @@
+package sources
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/0skillallluck/scanline/app/secrets"
+	"github.com/0skillallluck/scanline/provider/plex"
+	"github.com/0skillallluck/scanline/provider/plex/auth"
+	"github.com/google/uuid"
+)
+
+// ErrServerExists is returned when adding a server that is already
+// provided by another account.
+var ErrServerExists = errors.New("server is already added")
+
+// plexServerTokenKey returns the keyring key for the token of a Plex
+// server added by URL.
+func plexServerTokenKey(accountID string) string {
+	return "plex_url_token_" + accountID
+}
+
+// AddPlexServer adds a Plex server by URL as a standalone account that is
+// not linked to plex.tv. token may be empty for servers that allow access
+// without signing in. The server must have been validated by the caller.
+func (m *Manager) AddPlexServer(serverURL, token, clientID, machineID, name string) error {
+	m.mu.RLock()
+	exists := m.hasServer(machineID)
+	m.mu.RUnlock()
+	if exists {
+		return ErrServerExists
+	}
+
+	accountID := uuid.New().String()
+
+	// Keyring I/O outside lock
+	if token != "" {
+		secrets.SetToken(plexServerTokenKey(accountID), token)
+	}
+
+	srv := &Server{
+		ID:          machineID,
+		Name:        name,
+		Enabled:     true,
+		Owned:       true,
+		URL:         serverURL,
+		Connections: []string{serverURL},
+		Reachable:   true,
+
+		ConnectionPrefs: ConnectionPrefs{CustomURLs: []string{serverURL}},
+	}
+	acct := &Account{
+		ID:       accountID,
+		Type:     ProviderPlexServer,
+		Username: name,
+		ClientID: clientID,
+		Servers:  []*Server{srv},
+	}
+	slog.Debug("plex: adding server by URL", "server", name, "url", serverURL)
+
+	m.addAccount(acct, token)
+	return nil
+}
+
+// HasServer reports whether any account provides the server.
+func (m *Manager) HasServer(serverID string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hasServer(serverID)
+}
+
+// hasServer reports whether any account provides the server. The caller
+// must hold m.mu.
+func (m *Manager) hasServer(serverID string) bool {
+	for _, acct := range m.accounts {
+		for _, srv := range acct.Servers {
+			if srv.ID == serverID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// probePlexServer checks that a server added by URL still answers at one
+// of its custom URLs, returning a source for it when it is enabled and
+// reachable. Servers without custom URLs are probed at their last URL.
+func (m *Manager) probePlexServer(ctx context.Context, srv *Server, token, clientID string) Source {
+	var connections []auth.ResourceConnection
+	if len(srv.CustomURLs) == 0 {
+		connections = []auth.ResourceConnection{customConnection(srv.URL)}
+	}
+	ranked, err := rankConnections(ctx, newConnectionClient(), connections, nil, srv.ConnectionPrefs, token, clientID)
+	srv.Connections = ranked
+	if err != nil {
+		slog.Warn("plex: server unreachable", "server", srv.Name, "error", err)
+		srv.Reachable = false
+		return m.offlineServerSource(srv, plex.NewClient(srv.URL, token, clientID))
+	}
+	srv.URL = ranked[0]
+
+	ctx, cancel := context.WithTimeout(ctx, connectionTimeout)
+	defer cancel()
+
+	client := plex.NewClient(srv.URL, token, clientID)
+	identity, err := client.Server.Identity(ctx)
+	switch {
+	case err != nil:
+		slog.Warn("plex: server unreachable", "server", srv.Name, "url", srv.URL, "error", err)
+		srv.Reachable = false
+		return m.offlineServerSource(srv, client)
+	case identity.MachineIdentifier != srv.ID:
+		slog.Warn("plex: server at URL changed", "server", srv.Name, "url", srv.URL, "machine_id", identity.MachineIdentifier)
+		srv.Reachable = false
+	default:
+		srv.Reachable = true
+	}
+	if !srv.Reachable || !srv.Enabled {
+		return nil
+	}
+	return newServerSource(srv, client, m.connectionChanged)
+}
+
+// SetConnectionPrefs changes how a Plex server is connected to and
+// resolves its connections again.
+func (m *Manager) SetConnectionPrefs(ctx context.Context, serverID string, prefs ConnectionPrefs) {
+	m.mu.Lock()
+	found := false
+	for _, acct := range m.accounts {
+		for _, srv := range acct.Servers {
+			if srv.ID == serverID {
+				srv.ConnectionPrefs = prefs
+				found = true
+			}
+		}
+	}
+	if found {
+		saveConfig(m.accounts)
+	}
+	m.mu.Unlock()
+
+	if found {
+		m.RefreshServers(ctx)
+	}
+}
@@ -0,0 +1,281 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+
+	"github.com/0skillallluck/scanline/app/preference"
+	"github.com/0skillallluck/scanline/provider/plex"
+	"github.com/0skillallluck/scanline/utils/cacheutils"
+)
+
+// ErrOffline is returned for requests to a server that cannot be reached,
+// when the snapshot does not hold the requested content or the request
+// would change something on the server.
+var ErrOffline = errors.New("server is offline")
+
+// snapshotSource saves what is browsed on a source while its server is
+// online, so that it can be browsed read-only from the snapshot while the
+// server cannot be reached. Library sections, content and hubs are saved
+// with the cache-libraries preference, item metadata with cache-metadata.
+//
+// Sources with a connection failover are offline while the failover finds
+// no connection, and come back online when its re-probe succeeds; requests
+// are not sent meanwhile. Other sources are offline after a request fails
+// to connect, and come back online with the next request that succeeds.
+type snapshotSource struct {
+	Source
+	failover *connectionFailover
+	onChange func()
+
+	offline atomic.Bool
+}
+
+// withSnapshot wraps src so that its content is saved and served from the
+// snapshot while offline. onChange is called when a source without a
+// failover goes offline or comes back online.
+func withSnapshot(src Source, failover *connectionFailover, onChange func()) Source {
+	return &snapshotSource{Source: src, failover: failover, onChange: onChange}
+}
+
+// isOffline reports whether the server cannot be reached.
+func (s *snapshotSource) isOffline() bool {
+	if s.failover != nil {
+		return !s.failover.isReachable()
+	}
+	return s.offline.Load()
+}
+
+// unreachable reports whether requests are known to fail without being
+// sent. Sources without a failover always try the server, which is how
+// they notice it is back.
+func (s *snapshotSource) unreachable() bool {
+	return s.failover != nil && !s.failover.isReachable()
+}
+
+// setOffline records whether the last request reached the server, for
+// sources without a failover.
+func (s *snapshotSource) setOffline(offline bool) {
+	if s.failover != nil || s.offline.Swap(offline) == offline {
+		return
+	}
+	if offline {
+		slog.Warn("server offline, using snapshot", "source", s.Name())
+	} else {
+		slog.Info("server back online", "source", s.Name())
+	}
+	if s.onChange != nil {
+		s.onChange()
+	}
+}
+
+// snapshotRead fetches a value from the server while it is online, saving
+// it to the snapshot under key when save is set. While the server cannot
+// be reached, the value is loaded from the snapshot instead.
+func snapshotRead[T any](ctx context.Context, s *snapshotSource, key string, save bool, fetch func() (T, error)) (T, error) {
+	if !s.unreachable() {
+		v, err := fetch()
+		if err == nil {
+			s.setOffline(false)
+			if save {
+				storeSnapshot(key, v)
+			}
+			return v, nil
+		}
+		if !isConnectionError(ctx, err) {
+			return v, err
+		}
+		s.setOffline(true)
+	}
+
+	var v T
+	if !loadSnapshot(key, &v) {
+		return v, ErrOffline
+	}
+	return v, nil
+}
+
+// snapshotKey returns the cache key of saved content of a server.
+func snapshotKey(serverID, kind string, args ...any) string {
+	return "snapshot/" + serverID + "/" + kind + "/" + fmt.Sprint(args...)
+}
+
+// hasSnapshot reports whether content of a server was saved.
+func hasSnapshot(serverID string) bool {
+	_, ok := cacheutils.Get(snapshotKey(serverID, "sections"), cacheutils.Layered, 0)
+	return ok
+}
+
+func storeSnapshot(key string, v any) {
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = cacheutils.Store(key, data, cacheutils.Layered, 0)
+	}
+	if err != nil {
+		slog.Debug("failed to save snapshot", "key", key, "error", err)
+	}
+}
+
+func loadSnapshot(key string, v any) bool {
+	data, ok := cacheutils.Get(key, cacheutils.Layered, 0)
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		slog.Debug("failed to load snapshot", "key", key, "error", err)
+		return false
+	}
+	return true
+}
+
+// contentPage is a saved page of library content.
+type contentPage struct {
+	Items []Item
+	Total int
+}
+
+func (s *snapshotSource) LibrarySections(ctx context.Context) ([]LibrarySection, error) {
+	return snapshotRead(ctx, s, snapshotKey(s.ID(), "sections"), preference.Performance().ShouldCacheLibraries(), func() ([]LibrarySection, error) {
+		return s.Source.LibrarySections(ctx)
+	})
+}
+
+func (s *snapshotSource) LibrarySection(ctx context.Context, sectionID string) (*LibrarySection, error) {
+	return snapshotRead(ctx, s, snapshotKey(s.ID(), "section", sectionID), preference.Performance().ShouldCacheLibraries(), func() (*LibrarySection, error) {
+		return s.Source.LibrarySection(ctx, sectionID)
+	})
+}
+
+func (s *snapshotSource) LibraryContent(ctx context.Context, sectionID string, opts *ContentOptions) ([]Item, int, error) {
+	var key string
+	if opts != nil {
+		key = snapshotKey(s.ID(), "content", sectionID, *opts)
+	} else {
+		key = snapshotKey(s.ID(), "content", sectionID)
+	}
+	// Incremental fetches are only useful to the GUID index.
+	save := preference.Performance().ShouldCacheLibraries() && (opts == nil || opts.UpdatedSince == 0)
+	page, err := snapshotRead(ctx, s, key, save, func() (contentPage, error) {
+		items, total, err := s.Source.LibraryContent(ctx, sectionID, opts)
+		return contentPage{Items: items, Total: total}, err
+	})
+	return page.Items, page.Total, err
+}
+
+func (s *snapshotSource) HomeHubs(ctx context.Context, count int) ([]Hub, error) {
+	return snapshotRead(ctx, s, snapshotKey(s.ID(), "home", count), preference.Performance().ShouldCacheLibraries(), func() ([]Hub, error) {
+		return s.Source.HomeHubs(ctx, count)
+	})
+}
+
+func (s *snapshotSource) SectionHubs(ctx context.Context, sectionID string) ([]Hub, error) {
+	return snapshotRead(ctx, s, snapshotKey(s.ID(), "section-hubs", sectionID), preference.Performance().ShouldCacheLibraries(), func() ([]Hub, error) {
+		return s.Source.SectionHubs(ctx, sectionID)
+	})
+}
+
+func (s *snapshotSource) HubItems(ctx context.Context, hubKey string, start, size int) ([]Item, int, error) {
+	page, err := snapshotRead(ctx, s, snapshotKey(s.ID(), "hub", hubKey, start, size), preference.Performance().ShouldCacheLibraries(), func() (contentPage, error) {
+		items, total, err := s.Source.HubItems(ctx, hubKey, start, size)
+		return contentPage{Items: items, Total: total}, err
+	})
+	return page.Items, page.Total, err
+}
+
+func (s *snapshotSource) GetMetadata(ctx context.Context, id ItemID) (*Item, error) {
+	return snapshotRead(ctx, s, snapshotKey(s.ID(), "metadata", id), preference.Performance().ShouldCacheMetadata(), func() (*Item, error) {
+		return s.Source.GetMetadata(ctx, id)
+	})
+}
+
+func (s *snapshotSource) GetChildren(ctx context.Context, id ItemID) ([]Item, error) {
+	return snapshotRead(ctx, s, snapshotKey(s.ID(), "children", id), preference.Performance().ShouldCacheMetadata(), func() ([]Item, error) {
+		return s.Source.GetChildren(ctx, id)
+	})
+}
+
+func (s *snapshotSource) RelatedHubs(ctx context.Context, id ItemID) ([]Hub, error) {
+	return snapshotRead(ctx, s, snapshotKey(s.ID(), "related", id), preference.Performance().ShouldCacheMetadata(), func() ([]Hub, error) {
+		return s.Source.RelatedHubs(ctx, id)
+	})
+}
+
+// Requests that need the server, or change something on it, fail right
+// away while the failover finds no connection.
+
+func (s *snapshotSource) Search(ctx context.Context, query string, limit int) ([]Hub, error) {
+	if s.unreachable() {
+		return nil, ErrOffline
+	}
+	return s.Source.Search(ctx, query, limit)
+}
+
+func (s *snapshotSource) DecidePlayback(ctx context.Context, req PlaybackRequest) (*PlaybackDecision, error) {
+	if s.unreachable() {
+		return nil, ErrOffline
+	}
+	return s.Source.DecidePlayback(ctx, req)
+}
+
+func (s *snapshotSource) Scrobble(ctx context.Context, id ItemID) error {
+	if s.unreachable() {
+		return ErrOffline
+	}
+	return s.Source.Scrobble(ctx, id)
+}
+
+func (s *snapshotSource) Unscrobble(ctx context.Context, id ItemID) error {
+	if s.unreachable() {
+		return ErrOffline
+	}
+	return s.Source.Unscrobble(ctx, id)
+}
+
+func (s *snapshotSource) UpdateProgress(ctx context.Context, id ItemID, state PlaybackState, timeMs, durationMs int) error {
+	if s.unreachable() {
+		return ErrOffline
+	}
+	return s.Source.UpdateProgress(ctx, id, state, timeMs, durationMs)
+}
+
+// IsOffline reports whether an enabled server cannot be reached, so that
+// its saved snapshot is shown instead.
+func (m *Manager) IsOffline(serverID string) bool {
+	src := m.SourceForServer(serverID)
+	if src == nil {
+		return false
+	}
+	ss, ok := withoutLibraries(src).(*snapshotSource)
+	return ok && ss.isOffline()
+}
+
+// OfflineSources returns the enabled sources whose server cannot be
+// reached.
+func (m *Manager) OfflineSources() []Source {
+	var offline []Source
+	for _, src := range m.EnabledSources() {
+		if m.IsOffline(src.ID()) {
+			offline = append(offline, src)
+		}
+	}
+	return offline
+}
+
+// offlineServerSource returns a source for an enabled Plex server that
+// cannot be reached, serving its snapshot until a re-probe finds it again.
+// It returns nil if nothing of the server was saved.
+func (m *Manager) offlineServerSource(srv *Server, client *plex.Client) Source {
+	if !srv.Enabled || srv.URL == "" || !hasSnapshot(srv.ID) {
+		return nil
+	}
+	return newServerSource(srv, client, m.connectionChanged)
+}
+
+// offlineChanged reports that a source went offline or came back online.
+func (m *Manager) offlineChanged() {
+	m.SourcesChanged.Notify(struct{}{})
+}
@@ -2,7 +2,7 @@ package sources
 
 import (
 	"context"
-	"net/url"
+	"time"
 )
 
 // Source provides provider-agnostic access to a media server.
@@ -20,23 +20,32 @@ type Source interface {
 	LibrarySection(ctx context.Context, sectionID string) (*LibrarySection, error)
 
 	// LibraryContent returns items from a library section with optional pagination.
-	LibraryContent(ctx context.Context, sectionID string, opts *ContentOptions) ([]Metadata, int, error)
+	LibraryContent(ctx context.Context, sectionID string, opts *ContentOptions) ([]Item, int, error)
 
 	// GetMetadata returns detailed information about a specific media item.
-	GetMetadata(ctx context.Context, key string) (*Metadata, error)
+	GetMetadata(ctx context.Context, id ItemID) (*Item, error)
 
 	// GetChildren returns direct child items (show→seasons, season→episodes).
-	GetChildren(ctx context.Context, key string) ([]Metadata, error)
+	GetChildren(ctx context.Context, id ItemID) ([]Item, error)
 
 	// GetMarkers returns chapter markers (credits, intros) for a media item.
-	GetMarkers(ctx context.Context, key string) ([]Marker, error)
+	GetMarkers(ctx context.Context, id ItemID) ([]Marker, error)
+
+	// GetLyrics returns the content of a lyrics stream (see MediaPart.LyricsStream).
+	GetLyrics(ctx context.Context, stream *Stream) (*Lyrics, error)
 
 	// HomeHubs returns the hubs displayed on the home screen.
 	// The count parameter specifies the maximum number of items per hub (0 for server default).
 	HomeHubs(ctx context.Context, count int) ([]Hub, error)
 
+	// SectionHubs returns the hubs for a library section (recently added, by genre, ...).
+	SectionHubs(ctx context.Context, sectionID string) ([]Hub, error)
+
+	// HubItems returns a page of items from a hub by its key, with the total count available.
+	HubItems(ctx context.Context, hubKey string, start, size int) ([]Item, int, error)
+
 	// RelatedHubs returns content related to a specific item.
-	RelatedHubs(ctx context.Context, key string) ([]Hub, error)
+	RelatedHubs(ctx context.Context, id ItemID) ([]Hub, error)
 
 	// Search queries the source for matching content.
 	Search(ctx context.Context, query string, limit int) ([]Hub, error)
@@ -47,24 +56,90 @@ type Source interface {
 	// StreamURL returns a direct play URL for the given media part key.
 	StreamURL(partKey string) string
 
-	// ResolvePlaybackURL resolves a playback URL via the decision endpoint.
-	ResolvePlaybackURL(ctx context.Context, partKey, ratingKey, sessionID string) string
-
-	// BuildTranscodeQuery builds transcode query parameters from the given params.
-	BuildTranscodeQuery(params TranscodeParams) url.Values
-
-	// MakeTranscodeDecision calls the transcode decision endpoint to set up a session.
-	MakeTranscodeDecision(ctx context.Context, q url.Values) error
-
-	// TranscodeStartURL returns the URL for starting a transcode stream.
-	TranscodeStartURL(q url.Values) string
+	// DecidePlayback negotiates a stream for an item with the server.
+	DecidePlayback(ctx context.Context, req PlaybackRequest) (*PlaybackDecision, error)
 
 	// Scrobble marks an item as watched.
-	Scrobble(ctx context.Context, ratingKey string) error
+	Scrobble(ctx context.Context, id ItemID) error
 
 	// Unscrobble marks an item as unwatched.
-	Unscrobble(ctx context.Context, ratingKey string) error
+	Unscrobble(ctx context.Context, id ItemID) error
 
 	// UpdateProgress reports playback position to the server.
-	UpdateProgress(ctx context.Context, ratingKey string, state PlaybackState, timeMs, durationMs int) error
+	UpdateProgress(ctx context.Context, id ItemID, state PlaybackState, timeMs, durationMs int) error
+}
+
+// Optional capabilities. Sources implement these when their server
+// supports the feature; use the As* functions to look them up, since a
+// Source may be wrapped.
+
+// HistorySource is a source that keeps a server-side watch history.
+type HistorySource interface {
+	// History returns watch history entries, most recent first, with the total count available.
+	History(ctx context.Context, opts *HistoryOptions) ([]HistoryEntry, int, error)
+
+	// Accounts returns the server-local accounts, used to attribute history entries.
+	Accounts(ctx context.Context) ([]ServerAccount, error)
+}
+
+// AdminSource is a source whose server can be inspected and managed by
+// its owner.
+type AdminSource interface {
+	// ServerInfo returns version, platform and transcoder details of the source.
+	ServerInfo(ctx context.Context) (*ServerInfo, error)
+
+	// Preferences returns the server preferences.
+	Preferences(ctx context.Context) ([]ServerSetting, error)
+
+	// SetPreferences updates server preferences, keyed by setting ID.
+	SetPreferences(ctx context.Context, values map[string]string) error
+
+	// Sessions returns the playback sessions currently active on the source.
+	Sessions(ctx context.Context) ([]Session, error)
+
+	// TerminateSession stops a playback session, showing reason on the client.
+	TerminateSession(ctx context.Context, sessionID, reason string) error
+}
+
+// LiveTVSource is a source with Live TV and DVR support.
+type LiveTVSource interface {
+	// DVRs returns the Live TV DVRs configured on the source.
+	DVRs(ctx context.Context) ([]DVR, error)
+
+	// Channels returns the channels in a DVR's guide lineup.
+	Channels(ctx context.Context, dvr *DVR) ([]Channel, error)
+
+	// Guide returns the guide entries airing between start and end.
+	Guide(ctx context.Context, dvr *DVR, start, end time.Time) ([]Program, error)
+
+	// TuneChannel tunes a DVR to a channel. The session key is used as the
+	// path of a playback request (see PlaybackRequest.Path).
+	TuneChannel(ctx context.Context, dvrKey, channel string) (*LiveSession, error)
+
+	// Subscriptions returns the recording rules with their recordings.
+	Subscriptions(ctx context.Context) ([]Subscription, error)
+
+	// ScheduleRecording creates a recording rule for a guide entry.
+	ScheduleRecording(ctx context.Context, opts ScheduleOptions) (*Subscription, error)
+
+	// CancelSubscription deletes a recording rule.
+	CancelSubscription(ctx context.Context, key string) error
+}
+
+// AsHistory returns the watch history capability of src, if it has one.
+func AsHistory(src Source) (HistorySource, bool) {
+	h, ok := unwrapSource(src).(HistorySource)
+	return h, ok
+}
+
+// AsAdmin returns the server management capability of src, if it has one.
+func AsAdmin(src Source) (AdminSource, bool) {
+	a, ok := unwrapSource(src).(AdminSource)
+	return a, ok
+}
+
+// AsLiveTV returns the Live TV capability of src, if it has one.
+func AsLiveTV(src Source) (LiveTVSource, bool) {
+	l, ok := unwrapSource(src).(LiveTVSource)
+	return l, ok
 }
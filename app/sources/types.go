@@ -3,43 +3,62 @@
 package sources
 
 import (
-	"github.com/0skillallluck/scanline/provider/plex"
-	"github.com/0skillallluck/scanline/provider/plex/hubs"
-	"github.com/0skillallluck/scanline/provider/plex/library"
-	"github.com/0skillallluck/scanline/provider/plex/timeline"
+	"github.com/0skillallluck/scanline/provider/plex/history"
+	"github.com/0skillallluck/scanline/provider/plex/livetv"
+	"github.com/0skillallluck/scanline/provider/plex/server"
+	"github.com/0skillallluck/scanline/provider/plex/watchlist"
 )
 
-// Type aliases for provider-agnostic access to media types.
-// These decouple UI code from specific provider packages.
-//
-// NOTE: These are intentionally Plex-specific aliases for now.
-// When adding a second provider, define provider-agnostic domain
-// types here and have each provider adapter map to them.
-
-type Metadata = library.Metadata
-type Media = library.Media
-type Part = library.Part
-type Stream = library.Stream
-type Tag = library.Tag
-type Rating = library.Rating
-type Ratings = library.Ratings
-type LibrarySection = library.LibrarySection
-type ContentOptions = library.ContentOptions
-type Marker = library.Marker
-type Hub = hubs.Hub
-type TranscodeParams = plex.TranscodeParams
-type PlaybackState = timeline.PlaybackState
+// The domain model (Item, Hub, PlaybackRequest, ...) is defined in model.go
+// and mapped by each provider adapter. The aliases below are the types of
+// optional capabilities (see HistorySource, AdminSource and LiveTVSource)
+// and of account-level Plex features, which only the Plex provider offers.
+
+type HistoryEntry = history.Entry
+type HistoryOptions = history.Options
+type ServerAccount = server.Account
+type Session = server.Session
+type ServerInfo = server.ServerInfo
+type ServerSetting = server.Setting
+type DVR = livetv.DVR
+type Channel = livetv.Channel
+type Program = livetv.Program
+type Airing = livetv.Airing
+type LiveSession = livetv.Session
+type Subscription = livetv.Subscription
+type SubscriptionType = livetv.SubscriptionType
+type Recording = livetv.Recording
+type ScheduleOptions = livetv.ScheduleOptions
+type WatchlistItem = watchlist.Item
+type WatchlistMetadata = watchlist.Metadata
+type WatchlistTag = watchlist.Tag
+type WatchlistOptions = watchlist.ListOptions
+
+const (
+	SubscriptionMovie   = livetv.SubscriptionMovie
+	SubscriptionSeries  = livetv.SubscriptionSeries
+	SubscriptionEpisode = livetv.SubscriptionEpisode
+
+	RecordingScheduled  = livetv.RecordingScheduled
+	RecordingInProgress = livetv.RecordingInProgress
+	RecordingComplete   = livetv.RecordingComplete
+	RecordingError      = livetv.RecordingError
+)
 
 const (
-	StatePlaying = timeline.StatePlaying
-	StatePaused  = timeline.StatePaused
-	StateStopped = timeline.StateStopped
+	WatchlistFilterAll       = watchlist.FilterAll
+	WatchlistFilterAvailable = watchlist.FilterAvailable
+	WatchlistFilterReleased  = watchlist.FilterReleased
+
+	WatchlistSortDateAdded   = watchlist.SortDateAdded
+	WatchlistSortReleaseDate = watchlist.SortReleaseDate
+	WatchlistSortTitle       = watchlist.SortTitle
 )
 
-// ArtURL returns the best art URL for a metadata item, with fallbacks
+// ArtURL returns the best art URL for an item, with fallbacks
 // for types where the primary art may be missing (e.g. episodes falling
 // back to show poster).
-func ArtURL(meta *Metadata) string {
+func ArtURL(meta *Item) string {
 	if meta.Art != "" {
 		return meta.Art
 	}
@@ -0,0 +1,387 @@
+package sources
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+)
+
+// watchOffsetTolerance is how far apart, in milliseconds, resume offsets
+// may be and still count as the same.
+const watchOffsetTolerance = 10000
+
+// WatchAction is a change to the watch state of an item.
+type WatchAction string
+
+// Watch actions.
+const (
+	WatchMarkWatched   WatchAction = "watched"
+	WatchMarkUnwatched WatchAction = "unwatched"
+	WatchSetProgress   WatchAction = "progress"
+)
+
+// WatchChange is a change to a copy of an item that brings it in line with
+// the copy watched most recently.
+type WatchChange struct {
+	ServerID string
+	ItemID   ItemID
+
+	// Title describes the item, including the show and episode number for
+	// episodes.
+	Title string
+
+	Action WatchAction
+
+	// Offset and Duration are in milliseconds, set for WatchSetProgress.
+	Offset   int
+	Duration int
+
+	// FromServerID is the server whose watch state is copied.
+	FromServerID string
+
+	// Err is set when applying the change failed.
+	Err error
+}
+
+// WatchSyncReport lists the changes of a watch state sync.
+type WatchSyncReport struct {
+	Changes []WatchChange
+
+	// Applied reports whether the changes were made, as opposed to only
+	// planned.
+	Applied bool
+}
+
+// Failed returns the number of changes that could not be applied.
+func (r *WatchSyncReport) Failed() int {
+	failed := 0
+	for _, c := range r.Changes {
+		if c.Err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+// PlanWatchSync compares the watch state of the movies and episodes found
+// on several enabled sources, and returns the changes a sync would make
+// without making them. Where copies disagree, the copy watched most
+// recently wins.
+func (m *Manager) PlanWatchSync(ctx context.Context) (*WatchSyncReport, error) {
+	report := &WatchSyncReport{}
+
+	movies, err := m.MergedLibrary(ctx, "movie")
+	if err != nil {
+		return nil, err
+	}
+	for i := range movies {
+		report.Changes = append(report.Changes, planWatchChanges(movies[i].Copies, -1)...)
+	}
+
+	shows, err := m.MergedLibrary(ctx, "show")
+	if err != nil {
+		return nil, err
+	}
+	for i := range shows {
+		if len(shows[i].Copies) < 2 {
+			continue
+		}
+		for _, copies := range m.episodeCopies(ctx, shows[i].Copies) {
+			report.Changes = append(report.Changes, planWatchChanges(copies, -1)...)
+		}
+	}
+	return report, ctx.Err()
+}
+
+// ApplyWatchSync makes the changes of a report, recording failures in
+// the changes.
+func (m *Manager) ApplyWatchSync(ctx context.Context, report *WatchSyncReport) {
+	for i := range report.Changes {
+		c := &report.Changes[i]
+		src := m.SourceForServer(c.ServerID)
+		if src == nil {
+			c.Err = fmt.Errorf("server %s is not enabled", c.ServerID)
+			continue
+		}
+		switch c.Action {
+		case WatchMarkWatched:
+			c.Err = src.Scrobble(ctx, c.ItemID)
+		case WatchMarkUnwatched:
+			c.Err = src.Unscrobble(ctx, c.ItemID)
+		case WatchSetProgress:
+			c.Err = src.UpdateProgress(ctx, c.ItemID, StateStopped, c.Offset, c.Duration)
+		}
+		if c.Err != nil {
+			slog.Warn("failed to sync watch state", "server", c.ServerID, "item", c.ItemID, "action", c.Action, "error", c.Err)
+		}
+	}
+	report.Applied = true
+}
+
+// SyncItemWatchState copies the watch state of an item to its copies on
+// the other enabled sources. It is used after playback, when the played
+// copy is the most recent one.
+func (m *Manager) SyncItemWatchState(ctx context.Context, serverID string, id ItemID) (*WatchSyncReport, error) {
+	copies, err := m.itemCopies(ctx, serverID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Playback never marks copies unwatched: a copy that was only played
+	// briefly has no watch state of its own to copy.
+	changes := slices.DeleteFunc(planWatchChanges(copies, 0), func(c WatchChange) bool {
+		return c.Action == WatchMarkUnwatched
+	})
+	report := &WatchSyncReport{Changes: changes}
+	m.ApplyWatchSync(ctx, report)
+	return report, nil
+}
+
+// SyncMarkedWatchState copies the watch state of an item the user just
+// marked watched or unwatched to its copies on the other enabled sources.
+// The marked copy wins even though it may not be the latest watched: an
+// unwatched copy carries no time of its own.
+func (m *Manager) SyncMarkedWatchState(ctx context.Context, serverID string, id ItemID, watched bool) (*WatchSyncReport, error) {
+	copies, err := m.itemCopies(ctx, serverID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(copies) > 0 {
+		markWatched(&copies[0].Item, watched)
+	}
+
+	report := &WatchSyncReport{Changes: planWatchChanges(copies, 0)}
+	m.ApplyWatchSync(ctx, report)
+	return report, nil
+}
+
+// markWatched sets the watch state of an item as marked by the user,
+// which also clears its resume offset.
+func markWatched(item *Item, watched bool) {
+	item.ViewOffset = 0
+	if !watched {
+		item.ViewCount = 0
+	} else if item.ViewCount == 0 {
+		item.ViewCount = 1
+	}
+}
+
+// itemCopies returns the movie or episode of a server, first, and its
+// copies on the other enabled sources. Other items have no copies.
+func (m *Manager) itemCopies(ctx context.Context, serverID string, id ItemID) ([]ItemCopy, error) {
+	src := m.SourceForServer(serverID)
+	if src == nil {
+		return nil, fmt.Errorf("server %s is not enabled", serverID)
+	}
+	item, err := src.GetMetadata(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := []ItemCopy{{ServerID: serverID, Item: *item}}
+	switch item.Type {
+	case "movie":
+		for _, match := range m.MatchGUID(ctx, matchableGUIDs(item)...) {
+			if match.ServerID == serverID || match.Type != item.Type {
+				continue
+			}
+			if other := m.SourceForServer(match.ServerID); other != nil {
+				otherItem, err := other.GetMetadata(ctx, match.ItemID)
+				if err != nil {
+					slog.Warn("failed to fetch item for watch sync", "source", other.Name(), "item", match.ItemID, "error", err)
+					continue
+				}
+				copies = append(copies, ItemCopy{ServerID: match.ServerID, Item: *otherItem})
+			}
+		}
+	case "episode":
+		if item.GrandparentID == "" {
+			return nil, nil
+		}
+		show, err := src.GetMetadata(ctx, item.GrandparentID)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range m.MatchGUID(ctx, matchableGUIDs(show)...) {
+			if match.ServerID == serverID || match.Type != "show" {
+				continue
+			}
+			other := m.SourceForServer(match.ServerID)
+			if other == nil {
+				continue
+			}
+			episode, err := findEpisode(ctx, other, match.ItemID, item.ParentIndex, item.Index)
+			if err != nil {
+				slog.Warn("failed to fetch episode for watch sync", "source", other.Name(), "show", match.ItemID, "error", err)
+				continue
+			}
+			if episode != nil {
+				copies = append(copies, ItemCopy{ServerID: match.ServerID, Item: *episode})
+			}
+		}
+	default:
+		return nil, nil
+	}
+	return copies, nil
+}
+
+// findEpisode returns the episode of a show with the given season and
+// episode number, or nil if the show has none. Only the children of the
+// matching season are fetched.
+func findEpisode(ctx context.Context, src Source, showID ItemID, season, index int) (*Item, error) {
+	seasons, err := src.GetChildren(ctx, showID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range seasons {
+		if s.Type != "season" || s.Index != season {
+			continue
+		}
+		episodes, err := src.GetChildren(ctx, s.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range episodes {
+			if episodes[i].Type == "episode" && episodes[i].Index == index {
+				return &episodes[i], nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// episodeCopies lists the episodes of the copies of a show, grouped by
+// season and episode number.
+func (m *Manager) episodeCopies(ctx context.Context, shows []ItemCopy) [][]ItemCopy {
+	var groups [][]ItemCopy
+	byNumber := make(map[string]int)
+	for _, show := range shows {
+		src := m.SourceForServer(show.ServerID)
+		if src == nil {
+			continue
+		}
+		seasons, err := src.GetChildren(ctx, show.Item.ID)
+		if err != nil {
+			slog.Warn("failed to fetch seasons for watch sync", "source", src.Name(), "show", show.Item.ID, "error", err)
+			continue
+		}
+		for _, season := range seasons {
+			if season.Type != "season" {
+				continue
+			}
+			episodes, err := src.GetChildren(ctx, season.ID)
+			if err != nil {
+				slog.Warn("failed to fetch episodes for watch sync", "source", src.Name(), "season", season.ID, "error", err)
+				continue
+			}
+			for _, episode := range episodes {
+				if episode.Type != "episode" || episode.Index == 0 {
+					continue
+				}
+				key := strconv.Itoa(episode.ParentIndex) + "/" + strconv.Itoa(episode.Index)
+				idx, ok := byNumber[key]
+				if !ok {
+					idx = len(groups)
+					byNumber[key] = idx
+					groups = append(groups, nil)
+				}
+				groups[idx] = append(groups[idx], ItemCopy{ServerID: show.ServerID, Item: episode})
+			}
+		}
+	}
+	return groups
+}
+
+// planWatchChanges returns the changes that give every copy the watch
+// state of the winning copy. A negative winner picks the copy watched most
+// recently.
+func planWatchChanges(copies []ItemCopy, winner int) []WatchChange {
+	if len(copies) < 2 {
+		return nil
+	}
+	if winner < 0 {
+		winner = latestWatched(copies)
+	}
+	from := copies[winner]
+	state := &from.Item
+
+	var changes []WatchChange
+	for i, c := range copies {
+		if i == winner {
+			continue
+		}
+		item := &c.Item
+		change := WatchChange{
+			ServerID:     c.ServerID,
+			ItemID:       item.ID,
+			Title:        watchTitle(item),
+			FromServerID: from.ServerID,
+		}
+		switch {
+		case state.ViewOffset > 0:
+			if abs(item.ViewOffset-state.ViewOffset) < watchOffsetTolerance {
+				continue
+			}
+			change.Action = WatchSetProgress
+			change.Offset = state.ViewOffset
+			change.Duration = cmp.Or(item.Duration, state.Duration)
+		case state.ViewCount > 0:
+			if item.ViewCount > 0 && item.ViewOffset == 0 {
+				continue
+			}
+			change.Action = WatchMarkWatched
+		default:
+			if item.ViewCount == 0 && item.ViewOffset == 0 {
+				continue
+			}
+			change.Action = WatchMarkUnwatched
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// latestWatched returns the index of the copy watched most recently. When
+// that is unknown, watched copies win over copies in progress, and those
+// over unwatched ones.
+func latestWatched(copies []ItemCopy) int {
+	progress := func(item *Item) int {
+		switch {
+		case item.ViewCount > 0:
+			return 2
+		case item.ViewOffset > 0:
+			return 1
+		default:
+			return 0
+		}
+	}
+	latest := 0
+	for i := 1; i < len(copies); i++ {
+		a, b := &copies[i].Item, &copies[latest].Item
+		if cmp.Or(cmp.Compare(a.LastViewedAt, b.LastViewedAt), cmp.Compare(progress(a), progress(b))) > 0 {
+			latest = i
+		}
+	}
+	return latest
+}
+
+// watchTitle describes an item in a watch sync report.
+func watchTitle(item *Item) string {
+	if item.Type == "episode" {
+		return fmt.Sprintf("%s S%02dE%02d %s", item.GrandparentTitle, item.ParentIndex, item.Index, item.Title)
+	}
+	if item.Year > 0 {
+		return fmt.Sprintf("%s (%d)", item.Title, item.Year)
+	}
+	return item.Title
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,141 @@
+package sources
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLatestWatched(t *testing.T) {
+	tests := []struct {
+		name   string
+		copies []Item
+		want   int
+	}{
+		{
+			name:   "latest view wins",
+			copies: []Item{{ViewCount: 1, LastViewedAt: 100}, {ViewCount: 1, LastViewedAt: 200}},
+			want:   1,
+		},
+		{
+			name:   "tie goes to the watched copy",
+			copies: []Item{{ViewOffset: 60000, LastViewedAt: 100}, {ViewCount: 1, LastViewedAt: 100}},
+			want:   1,
+		},
+		{
+			name:   "tie between equal copies keeps the first",
+			copies: []Item{{ViewCount: 1, LastViewedAt: 100}, {ViewCount: 1, LastViewedAt: 100}},
+			want:   0,
+		},
+		{
+			name:   "progress only wins over unwatched",
+			copies: []Item{{}, {ViewOffset: 60000}},
+			want:   1,
+		},
+		{
+			name:   "progress only loses to an earlier view",
+			copies: []Item{{ViewOffset: 60000}, {ViewCount: 1, LastViewedAt: 100}},
+			want:   1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copies := make([]ItemCopy, len(tt.copies))
+			for i, item := range tt.copies {
+				copies[i] = ItemCopy{ServerID: string(rune('a' + i)), Item: item}
+			}
+			if got := latestWatched(copies); got != tt.want {
+				t.Errorf("latestWatched() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlanWatchChanges(t *testing.T) {
+	unwatched := Item{ID: "1"}
+	watched := Item{ID: "1", ViewCount: 1, LastViewedAt: 100}
+	progress := Item{ID: "1", ViewOffset: 600000, Duration: 3600000, LastViewedAt: 200}
+	marked := watched
+	markWatched(&marked, false)
+
+	tests := []struct {
+		name       string
+		copies     []Item
+		winner     int
+		wantAction WatchAction
+		wantOffset int
+	}{
+		{name: "watched copy marks the other watched", copies: []Item{watched, unwatched}, winner: -1, wantAction: WatchMarkWatched},
+		{name: "progress only sets the offset", copies: []Item{unwatched, progress}, winner: -1, wantAction: WatchSetProgress, wantOffset: 600000},
+		{name: "close offsets are in sync", copies: []Item{progress, {ID: "1", ViewOffset: 605000}}, winner: -1},
+		{name: "marked unwatched wins over a watched copy", copies: []Item{marked, watched}, winner: 0, wantAction: WatchMarkUnwatched},
+		{name: "unwatched copies are in sync", copies: []Item{marked, unwatched}, winner: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copies := []ItemCopy{{ServerID: "a", Item: tt.copies[0]}, {ServerID: "b", Item: tt.copies[1]}}
+			changes := planWatchChanges(copies, tt.winner)
+			if tt.wantAction == "" {
+				if len(changes) != 0 {
+					t.Fatalf("planWatchChanges() = %+v, want no changes", changes)
+				}
+				return
+			}
+			if len(changes) != 1 {
+				t.Fatalf("planWatchChanges() = %+v, want one change", changes)
+			}
+			if c := changes[0]; c.Action != tt.wantAction || c.Offset != tt.wantOffset {
+				t.Errorf("planWatchChanges() = %s at %d, want %s at %d", c.Action, c.Offset, tt.wantAction, tt.wantOffset)
+			}
+		})
+	}
+}
+
+// seasonsSource serves a show with two seasons of two episodes each.
+type seasonsSource struct {
+	Source
+	fetched []ItemID
+}
+
+func (s *seasonsSource) GetChildren(ctx context.Context, id ItemID) ([]Item, error) {
+	s.fetched = append(s.fetched, id)
+	switch id {
+	case "show":
+		return []Item{{ID: "s1", Type: "season", Index: 1}, {ID: "s2", Type: "season", Index: 2}}, nil
+	case "s1":
+		return []Item{{ID: "e11", Type: "episode", ParentIndex: 1, Index: 1}, {ID: "e12", Type: "episode", ParentIndex: 1, Index: 2}}, nil
+	case "s2":
+		return []Item{{ID: "e21", Type: "episode", ParentIndex: 2, Index: 1}, {ID: "e22", Type: "episode", ParentIndex: 2, Index: 2}}, nil
+	}
+	return nil, nil
+}
+
+func TestFindEpisode(t *testing.T) {
+	tests := []struct {
+		season, index int
+		want          ItemID
+	}{
+		{season: 1, index: 2, want: "e12"},
+		{season: 2, index: 1, want: "e21"},
+		{season: 2, index: 3},
+		{season: 3, index: 1},
+	}
+	for _, tt := range tests {
+		src := &seasonsSource{}
+		episode, err := findEpisode(context.Background(), src, "show", tt.season, tt.index)
+		if err != nil {
+			t.Fatalf("findEpisode(%d, %d) error = %v", tt.season, tt.index, err)
+		}
+		var got ItemID
+		if episode != nil {
+			got = episode.ID
+		}
+		if got != tt.want {
+			t.Errorf("findEpisode(%d, %d) = %q, want %q", tt.season, tt.index, got, tt.want)
+		}
+		for _, id := range src.fetched {
+			if id != "show" && id != ItemID("s"+string(rune('0'+tt.season))) {
+				t.Errorf("findEpisode(%d, %d) fetched the children of %s", tt.season, tt.index, id)
+			}
+		}
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"codeberg.org/puregotk/puregotk/v4/gobject"
 	"codeberg.org/puregotk/puregotk/v4/gtk"
 	"github.com/0skillallluck/scanline/app/appctx"
+	"github.com/0skillallluck/scanline/app/components/audio"
 	"github.com/0skillallluck/scanline/app/dialogs/about"
+	"github.com/0skillallluck/scanline/app/dialogs/profiles"
 	"github.com/0skillallluck/scanline/app/dialogs/sources"
 	"github.com/0skillallluck/scanline/app/preference"
 	"github.com/0skillallluck/scanline/app/router"
@@ -83,6 +85,9 @@ func NewWindow(app *adw.Application, appCtx *appctx.AppContext) *Window {
 }
 
 func (w *Window) showWelcomeContent() {
+	// Queued tracks may belong to a removed account.
+	audio.Stop()
+
 	toolbarView := adw.NewToolbarView()
 
 	headerbar := HeaderBar().
@@ -132,6 +137,10 @@ func (w *Window) showMainContent() {
 
 	router.NavigateClearing("home")
 
+	if preference.General().PickProfileAtStartup() {
+		profiles.PresentAtStartup(w.appCtx.Ctx, &w.Widget, mgr, w.onProfileSwitched)
+	}
+
 	// Monitor for all accounts being removed → transition back to welcome
 	accountSub := mgr.SourcesChanged.On(func(_ struct{}) bool {
 		if !mgr.HasAccounts() {
@@ -149,6 +158,13 @@ func (w *Window) showMainContent() {
 	_ = accountSub
 }
 
+// onProfileSwitched reloads the app for a newly selected Plex Home user.
+func (w *Window) onProfileSwitched() {
+	// Queued tracks belong to the previous profile.
+	audio.Stop()
+	router.NavigateClearing("home")
+}
+
 func (w *Window) setWindowContent(content *gtk.Widget) {
 	toastLayout := adw.NewToastOverlay()
 	toastLayout.SetChild(content)
@@ -184,6 +200,10 @@ func (w *Window) buildMainView() (*gtk.Widget, *signals.Subscription) {
 func (w *Window) buildContentLayout() *gtk.Widget {
 	toolbarView := adw.NewToolbarView()
 	toolbarView.AddTopBar(w.buildContentHeader())
+	toolbarView.AddTopBar(w.buildOfflineBanner())
+	// The mini-player lives outside the routed content so music keeps
+	// playing and stays controllable while navigating.
+	toolbarView.AddBottomBar(audio.NewMiniPlayer())
 
 	var navStartedSub, navCompletedSub *signals.Subscription
 	navStartedSub = router.NavigationStarted.On(func(path string) bool { //nolint:staticcheck // SA4006 - used in closure
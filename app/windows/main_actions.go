@@ -10,8 +10,10 @@ import (
 	"codeberg.org/puregotk/puregotk/v4/gtk"
 	"github.com/0skillallluck/scanline/app/dialogs/about"
 	"github.com/0skillallluck/scanline/app/dialogs/preferences"
+	"github.com/0skillallluck/scanline/app/dialogs/profiles"
 	"github.com/0skillallluck/scanline/app/dialogs/shortcuts"
 	"github.com/0skillallluck/scanline/app/dialogs/sources"
+	"github.com/0skillallluck/scanline/app/dialogs/watchsync"
 	"github.com/0skillallluck/scanline/app/router"
 )
 
@@ -64,6 +66,12 @@ func (w *Window) installWindowActions() {
 	}))
 	w.AddAction(selectSourcesAction)
 
+	switchProfileAction := gio.NewSimpleAction("switch-profile", nil)
+	switchProfileAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		profiles.NewProfilePicker(w.appCtx.Ctx, w.appCtx.Manager, w.onProfileSwitched).Present(&w.Widget)
+	}))
+	w.AddAction(switchProfileAction)
+
 	navigateBackAction := gio.NewSimpleAction("navigate-back", nil)
 	navigateBackAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
 		router.Back()
@@ -82,6 +90,30 @@ func (w *Window) installWindowActions() {
 	w.AddAction(searchAction)
 	w.GetApplication().SetAccelsForAction("win.search", []string{"<Ctrl>f"})
 
+	historyAction := gio.NewSimpleAction("history", nil)
+	historyAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		router.Navigate("history")
+	}))
+	w.AddAction(historyAction)
+
+	watchSyncAction := gio.NewSimpleAction("sync-watch-state", nil)
+	watchSyncAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		watchsync.NewWatchSync(w.appCtx.Ctx, w.appCtx.Manager).Present(&w.Widget)
+	}))
+	w.AddAction(watchSyncAction)
+
+	liveTVAction := gio.NewSimpleAction("livetv", nil)
+	liveTVAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		router.Navigate("livetv")
+	}))
+	w.AddAction(liveTVAction)
+
+	sessionsAction := gio.NewSimpleAction("sessions", nil)
+	sessionsAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		router.Navigate("sessions")
+	}))
+	w.AddAction(sessionsAction)
+
 	routeMovieAction := gio.NewSimpleAction("route.movie", glib.NewVariantType("s"))
 	routeMovieAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
 		variant := (*glib.Variant)(unsafe.Pointer(parameter))
@@ -110,6 +142,48 @@ func (w *Window) installWindowActions() {
 	}))
 	w.AddAction(routeEpisodeAction)
 
+	routeArtistAction := gio.NewSimpleAction("route.artist", glib.NewVariantType("s"))
+	routeArtistAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		variant := (*glib.Variant)(unsafe.Pointer(parameter))
+		router.Navigate("artist/" + variant.GetString(nil))
+	}))
+	w.AddAction(routeArtistAction)
+
+	routeAlbumAction := gio.NewSimpleAction("route.album", glib.NewVariantType("s"))
+	routeAlbumAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		variant := (*glib.Variant)(unsafe.Pointer(parameter))
+		router.Navigate("album/" + variant.GetString(nil))
+	}))
+	w.AddAction(routeAlbumAction)
+
+	routeTrackAction := gio.NewSimpleAction("route.track", glib.NewVariantType("s"))
+	routeTrackAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		variant := (*glib.Variant)(unsafe.Pointer(parameter))
+		router.Navigate("track/" + variant.GetString(nil))
+	}))
+	w.AddAction(routeTrackAction)
+
+	routePhotoAlbumAction := gio.NewSimpleAction("route.photoalbum", glib.NewVariantType("s"))
+	routePhotoAlbumAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		variant := (*glib.Variant)(unsafe.Pointer(parameter))
+		router.Navigate("photos/" + variant.GetString(nil))
+	}))
+	w.AddAction(routePhotoAlbumAction)
+
+	routePhotoAction := gio.NewSimpleAction("route.photo", glib.NewVariantType("s"))
+	routePhotoAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		variant := (*glib.Variant)(unsafe.Pointer(parameter))
+		router.Navigate("photo/" + variant.GetString(nil))
+	}))
+	w.AddAction(routePhotoAction)
+
+	routeWatchlistAction := gio.NewSimpleAction("route.watchlist", glib.NewVariantType("s"))
+	routeWatchlistAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
+		variant := (*glib.Variant)(unsafe.Pointer(parameter))
+		router.Navigate("watchlist/" + variant.GetString(nil))
+	}))
+	w.AddAction(routeWatchlistAction)
+
 	routeCastAction := gio.NewSimpleAction("route.cast", glib.NewVariantType("s"))
 	routeCastAction.ConnectActivate(new(func(action gio.SimpleAction, parameter uintptr) {
 		variant := (*glib.Variant)(unsafe.Pointer(parameter))
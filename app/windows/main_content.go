@@ -44,6 +44,11 @@ func updateDecorationLayout() {
 func (w *Window) buildMainMenu() *gio.Menu {
 	mainMenu := gio.NewMenu()
 	mainMenu.Append(gettext.Get("Select Sources"), "win.select-sources")
+	mainMenu.Append(gettext.Get("Switch Profile"), "win.switch-profile")
+	mainMenu.Append(gettext.Get("Watch History"), "win.history")
+	mainMenu.Append(gettext.Get("Sync Watch State"), "win.sync-watch-state")
+	mainMenu.Append(gettext.Get("Live TV"), "win.livetv")
+	mainMenu.Append(gettext.Get("Active Sessions"), "win.sessions")
 	mainMenu.Append(gettext.Get("Preferences"), "app.preferences")
 	mainMenu.Append(gettext.Get("Keyboard Shortcuts"), "app.shortcuts")
 	mainMenu.Append(gettext.Get("About Scanline"), "app.about")
@@ -65,6 +70,15 @@ func iconForSectionType(sectionType string) string {
 	}
 }
 
+// mergedLibraryTitle returns the title of the merged library of a section
+// type.
+func mergedLibraryTitle(sectionType string) string {
+	if sectionType == "show" {
+		return gettext.Get("All Shows")
+	}
+	return gettext.Get("All Movies")
+}
+
 func (w *Window) buildContentHeader() *gtk.Widget {
 	mgr := w.appCtx.Manager
 
@@ -124,10 +138,31 @@ func (w *Window) buildContentHeader() *gtk.Widget {
 				}
 			}
 
+			// With unified libraries, movie and show sections found on several
+			// servers are replaced by one merged library per type
+			merged := make(map[string]bool)
+			if preference.General().UnifyLibraries() {
+				servers := make(map[string]map[string]bool)
+				for _, si := range allSections {
+					if !sources.IsMergedType(si.section.Type) {
+						continue
+					}
+					if servers[si.section.Type] == nil {
+						servers[si.section.Type] = make(map[string]bool)
+					}
+					servers[si.section.Type][si.serverID] = true
+				}
+				for sectionType, ids := range servers {
+					merged[sectionType] = len(ids) > 1
+				}
+			}
+
 			// Check for duplicate section names across servers
 			nameCounts := make(map[string]int)
 			for _, si := range allSections {
-				nameCounts[si.section.Title]++
+				if !merged[si.section.Type] {
+					nameCounts[si.section.Title]++
+				}
 			}
 
 			schwifty.OnMainThreadOncePure(func() {
@@ -139,7 +174,21 @@ func (w *Window) buildContentHeader() *gtk.Widget {
 				}
 				libraryButtons = nil
 
+				for _, sectionType := range []string{"movie", "show"} {
+					if !merged[sectionType] {
+						continue
+					}
+					btn := components.NewRouteButton("libraries/" + sectionType)
+					btn.Title(mergedLibraryTitle(sectionType))
+					btn.Icon(iconForSectionType(sectionType))
+					defaultToolbar.Append(&btn.Widget)
+					libraryButtons = append(libraryButtons, btn)
+				}
+
 				for _, si := range allSections {
+					if merged[si.section.Type] {
+						continue
+					}
 					title := si.section.Title
 					if nameCounts[title] > 1 {
 						// Find the source name for disambiguation
@@ -173,6 +222,10 @@ func (w *Window) buildContentHeader() *gtk.Widget {
 		return signals.Continue
 	})
 
+	preference.General().OnUnifyLibrariesChanged(func() {
+		refreshLibraryButtons()
+	})
+
 	preference.Experimental().OnEnableWatchlistChanged(func() {
 		schwifty.OnMainThreadOncePure(func() {
 			watchlistButton.SetVisible(
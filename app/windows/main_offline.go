@@ -0,0 +1,66 @@
+package windows
+
+import (
+	"codeberg.org/dergs/tonearm/pkg/schwifty"
+	"codeberg.org/puregotk/puregotk/v4/adw"
+	"codeberg.org/puregotk/puregotk/v4/gtk"
+	"github.com/0skillallluck/scanline/app/router"
+	"github.com/0skillallluck/scanline/internal/gettext"
+	"github.com/0skillallluck/scanline/internal/signals"
+)
+
+// buildOfflineBanner creates the banner shown while servers are offline and
+// their saved content is browsed. The current page is reloaded when a
+// server comes back online.
+func (w *Window) buildOfflineBanner() *gtk.Widget {
+	mgr := w.appCtx.Manager
+	banner := adw.NewBanner("")
+
+	offline := make(map[string]bool)
+	update := func() {
+		sources := mgr.OfflineSources()
+		schwifty.OnMainThreadOncePure(func() {
+			now := make(map[string]bool, len(sources))
+			for _, src := range sources {
+				now[src.ID()] = true
+			}
+			backOnline := false
+			for id := range offline {
+				if !now[id] {
+					backOnline = true
+				}
+			}
+			offline = now
+
+			switch len(sources) {
+			case 0:
+				banner.SetRevealed(false)
+			case 1:
+				banner.SetTitle(gettext.Getf("%s is offline, showing saved content", sources[0].Name()))
+				banner.SetRevealed(true)
+			default:
+				banner.SetTitle(gettext.GetN("%d server is offline, showing saved content", "%d servers are offline, showing saved content", len(sources), len(sources)))
+				banner.SetRevealed(true)
+			}
+			if backOnline && router.Current() != nil {
+				router.Refresh()
+			}
+		})
+	}
+
+	var sourcesSub *signals.Subscription
+	sourcesSub = mgr.SourcesChanged.On(func(_ struct{}) bool { //nolint:staticcheck // SA4006 - used in closure
+		update()
+		return signals.Continue
+	})
+	update()
+
+	banner.ConnectDestroy(new(func(gtk.Widget) {
+		if sourcesSub != nil {
+			mgr.SourcesChanged.Unsubscribe(sourcesSub)
+			sourcesSub = nil
+		}
+	}))
+
+	return &banner.Widget
+}
@@ -0,0 +1,39 @@
+package emby
+
+import (
+	"context"
+	"log/slog"
+)
+
+// PublicInfo returns the server's name, ID and version. It does not
+// require signing in and is used to check that a URL points to a server.
+func (c *Client) PublicInfo(ctx context.Context) (*PublicSystemInfo, error) {
+	var info PublicSystemInfo
+	if err := c.get(ctx, "/System/Info/Public", nil).DoAndDecode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// AuthenticateByName signs in with a username and password.
+func (c *Client) AuthenticateByName(ctx context.Context, username, password string) (*AuthenticationResult, error) {
+	body := map[string]string{"Username": username, "Pw": password}
+	var result AuthenticationResult
+	if err := c.post(ctx, "/Users/AuthenticateByName", body).DoAndDecode(&result); err != nil {
+		return nil, err
+	}
+	slog.Debug("emby: signed in", "user", result.User.Name)
+	return &result, nil
+}
+
+// ExchangeConnectToken exchanges the access key of a server linked to an
+// Emby Connect account for an access token of the linked server user. The
+// client must be created with the access key as its token.
+func (c *Client) ExchangeConnectToken(ctx context.Context, connectUserID string) (*ConnectExchange, error) {
+	query := map[string]string{"format": "json", "ConnectUserId": connectUserID}
+	var exchange ConnectExchange
+	if err := c.get(ctx, "/Connect/Exchange", query).DoAndDecode(&exchange); err != nil {
+		return nil, err
+	}
+	return &exchange, nil
+}
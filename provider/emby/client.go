@@ -0,0 +1,109 @@
+package emby
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/0skillallluck/scanline/utils/httputils/request"
+)
+
+// Client identification sent with every request.
+const (
+	clientName    = "Scanline"
+	clientVersion = "1.0.0"
+)
+
+// Client provides access to an Emby server API on behalf of a user.
+//
+// Create a new client using [NewClient]:
+//
+//	client := emby.NewClient("http://localhost:8096", "token", "user-id", "device-id")
+//
+// Sign in first with [Client.AuthenticateByName] or Emby Connect to obtain
+// the token and user ID.
+type Client struct {
+	baseURL  string
+	token    string
+	userID   string
+	deviceID string
+}
+
+// NewClient creates a new Emby client.
+//
+// The serverURL is the base URL of the server (e.g., "http://localhost:8096").
+// The token and userID are empty until the user signs in. The deviceID should
+// be a unique identifier for the application instance (typically a UUID).
+func NewClient(serverURL, token, userID, deviceID string) *Client {
+	return &Client{
+		baseURL:  strings.TrimRight(serverURL, "/"),
+		token:    token,
+		userID:   userID,
+		deviceID: deviceID,
+	}
+}
+
+// ServerURL returns the base URL of the server.
+func (c *Client) ServerURL() string {
+	return c.baseURL
+}
+
+// Token returns the access token.
+func (c *Client) Token() string {
+	return c.token
+}
+
+// UserID returns the ID of the signed-in user.
+func (c *Client) UserID() string {
+	return c.userID
+}
+
+// authorization returns the X-Emby-Authorization header identifying the
+// client. Unlike Jellyfin, Emby expects the token in its own header.
+func (c *Client) authorization() string {
+	header := fmt.Sprintf(`MediaBrowser Client="%s", Device="%s", DeviceId="%s", Version="%s"`,
+		clientName, clientName, c.deviceID, clientVersion)
+	if c.userID != "" {
+		header += fmt.Sprintf(`, UserId="%s"`, c.userID)
+	}
+	return header
+}
+
+// request builds a new request with Emby headers pre-configured.
+func (c *Client) request(ctx context.Context, method, path string) *request.Request {
+	headers := map[string]string{
+		"X-Emby-Authorization": c.authorization(),
+		"Accept":               "application/json",
+	}
+	if c.token != "" {
+		headers["X-Emby-Token"] = c.token
+	}
+	return request.NewRequest(method, c.baseURL+path).
+		WithContext(ctx).
+		WithHeaders(headers).
+		WithLogging("X-Emby-Token")
+}
+
+// get returns a GET request with query parameters.
+func (c *Client) get(ctx context.Context, path string, query map[string]string) *request.Request {
+	return c.request(ctx, http.MethodGet, path).WithQuery(query)
+}
+
+// post returns a POST request with a JSON body. A nil body sends none.
+func (c *Client) post(ctx context.Context, path string, body any) *request.Request {
+	req := c.request(ctx, http.MethodPost, path)
+	if body != nil {
+		req = req.WithJSONBody(body)
+	}
+	return req
+}
+
+// do executes a request that returns no content.
+func do(req *request.Request) error {
+	resp, err := req.Do()
+	if err != nil {
+		return err
+	}
+	return resp.CheckStatus()
+}
@@ -0,0 +1,56 @@
+package emby
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/0skillallluck/scanline/utils/httputils/request"
+)
+
+// connectURL is the base URL of the Emby Connect service.
+var connectURL = "https://connect.emby.media/service"
+
+// connectRequest builds a request to Emby Connect. The token is the Connect
+// access token, empty before signing in.
+func connectRequest(ctx context.Context, method, path, token string) *request.Request {
+	headers := map[string]string{
+		"X-Application": clientName + "/" + clientVersion,
+		"Accept":        "application/json",
+	}
+	if token != "" {
+		headers["X-Connect-UserToken"] = token
+	}
+	return request.NewRequest(method, connectURL+path).
+		WithContext(ctx).
+		WithHeaders(headers).
+		WithLogging("X-Connect-UserToken")
+}
+
+// ConnectAuthenticate signs in to Emby Connect with the name or email
+// address and password of an Emby Connect account.
+func ConnectAuthenticate(ctx context.Context, nameOrEmail, password string) (*ConnectAuthenticationResult, error) {
+	form := url.Values{"nameOrEmail": {nameOrEmail}, "rawpw": {password}}
+	var result ConnectAuthenticationResult
+	err := connectRequest(ctx, http.MethodPost, "/user/authenticate", "").
+		WithHeader("Content-Type", "application/x-www-form-urlencoded").
+		WithBody(strings.NewReader(form.Encode())).
+		DoAndDecode(&result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ConnectServers lists the servers linked to an Emby Connect account.
+func ConnectServers(ctx context.Context, userID, token string) ([]ConnectServer, error) {
+	var servers []ConnectServer
+	err := connectRequest(ctx, http.MethodGet, "/servers", token).
+		WithQuery(map[string]string{"userId": userID}).
+		DoAndDecode(&servers)
+	if err != nil {
+		return nil, err
+	}
+	return servers, nil
+}
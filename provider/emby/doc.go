@@ -0,0 +1,30 @@
+// Package emby provides a client for interacting with Emby server APIs.
+//
+// Emby and Jellyfin share their item and playback objects, which this
+// package reuses from the jellyfin package, along with its path helpers
+// ([jellyfin.ImagePath], [jellyfin.VideoStreamPath], ...). The clients
+// differ in authentication: Emby takes the access token in the
+// X-Emby-Token header, and supports Emby Connect accounts.
+//
+// # Signing In
+//
+// A [Client] without a token signs in to a server with a username and
+// password:
+//
+//	client := emby.NewClient("http://localhost:8096", "", "", deviceID)
+//	result, err := client.AuthenticateByName(ctx, "user", "password")
+//
+// With Emby Connect, the user signs in once and gets access to every
+// server linked to the account. Each server's access key is exchanged for
+// a token of the linked server user:
+//
+//	connect, err := emby.ConnectAuthenticate(ctx, "name@example.com", "password")
+//	servers, err := emby.ConnectServers(ctx, connect.User.ID, connect.AccessToken)
+//	client := emby.NewClient(servers[0].URL, servers[0].AccessKey, "", deviceID)
+//	exchange, err := client.ExchangeConnectToken(ctx, connect.User.ID)
+//
+// Create a new client with the returned token and user ID for the user's
+// requests:
+//
+//	client = emby.NewClient(serverURL, exchange.AccessToken, exchange.LocalUserID, deviceID)
+package emby
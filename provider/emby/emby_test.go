@@ -0,0 +1,334 @@
+package emby
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0skillallluck/scanline/provider/jellyfin"
+)
+
+// serveFixture answers with a recorded Emby response from testdata.
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Errorf("reading fixture: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// checkAuthorization reports an error unless the request carries the Emby
+// authorization headers of the test client, with the given token. Unlike
+// Jellyfin, Emby takes the token in a header of its own.
+func checkAuthorization(t *testing.T, r *http.Request, token string) {
+	t.Helper()
+	auth := r.Header.Get("X-Emby-Authorization")
+	if !strings.HasPrefix(auth, "MediaBrowser ") || !strings.Contains(auth, `DeviceId="client"`) {
+		t.Errorf("X-Emby-Authorization = %q, want MediaBrowser with the device ID", auth)
+	}
+	if got := r.Header.Get("X-Emby-Token"); got != token {
+		t.Errorf("X-Emby-Token = %q, want %q", got, token)
+	}
+}
+
+func TestPublicInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/System/Info/Public" {
+			t.Errorf("path = %s, want /System/Info/Public", r.URL.Path)
+		}
+		checkAuthorization(t, r, "")
+		serveFixture(t, w, "public_info.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "", "user", "client")
+	info, err := c.PublicInfo(context.Background())
+	if err != nil {
+		t.Fatalf("PublicInfo() error = %v", err)
+	}
+	if info.ID != "e0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5" || info.ServerName != "Basement" || info.Version != "4.8.10.0" {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+func TestAuthenticateByName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/Users/AuthenticateByName" {
+			t.Errorf("request = %s %s, want POST /Users/AuthenticateByName", r.Method, r.URL.Path)
+		}
+		checkAuthorization(t, r, "")
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		if body["Username"] != "bob" || body["Pw"] != "secret" {
+			t.Errorf("body = %v", body)
+		}
+		serveFixture(t, w, "authenticate.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "", "user", "client")
+	result, err := c.AuthenticateByName(context.Background(), "bob", "secret")
+	if err != nil {
+		t.Fatalf("AuthenticateByName() error = %v", err)
+	}
+	if result.AccessToken != "token" || result.User.ID != "b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0" || result.User.Policy.IsAdministrator {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Emby lists items under the user rather than with a userId
+		// parameter.
+		if r.URL.Path != "/Users/user/Items" {
+			t.Errorf("path = %s, want /Users/user/Items", r.URL.Path)
+		}
+		checkAuthorization(t, r, "token")
+		if auth := r.Header.Get("X-Emby-Authorization"); !strings.Contains(auth, `UserId="user"`) {
+			t.Errorf("X-Emby-Authorization = %q, want the user ID", auth)
+		}
+		q := r.URL.Query()
+		if q.Get("parentId") != "12" || q.Get("includeItemTypes") != "Movie" || q.Get("recursive") != "true" || q.Get("limit") != "20" {
+			t.Errorf("query = %v", q)
+		}
+		serveFixture(t, w, "items.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	items, total, err := c.Items(context.Background(), &ItemsOptions{
+		ParentID:         "12",
+		Recursive:        true,
+		IncludeItemTypes: []string{jellyfin.TypeMovie},
+		Limit:            20,
+	})
+	if err != nil {
+		t.Fatalf("Items() error = %v", err)
+	}
+	if total != 41 || len(items) != 1 {
+		t.Fatalf("total = %d, len(items) = %d, want 41 and 1", total, len(items))
+	}
+	if items[0].Name != "Alien" || items[0].UserData == nil || items[0].UserData.PlayCount != 2 {
+		t.Errorf("items[0] = %+v", items[0])
+	}
+}
+
+func TestItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Users/user/Items/4012" {
+			t.Errorf("path = %s, want /Users/user/Items/4012", r.URL.Path)
+		}
+		serveFixture(t, w, "item.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	item, err := c.Item(context.Background(), "4012")
+	if err != nil {
+		t.Fatalf("Item() error = %v", err)
+	}
+	if item.ProviderIDs["Imdb"] != "tt0078748" || len(item.People) != 2 || len(item.GenreItems) != 2 {
+		t.Errorf("item = %+v", item)
+	}
+	if len(item.MediaSources) != 1 || len(item.MediaSources[0].MediaStreams) != 2 {
+		t.Fatalf("MediaSources = %+v", item.MediaSources)
+	}
+}
+
+func TestResumePaging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Users/user/Items/Resume" {
+			t.Errorf("path = %s, want /Users/user/Items/Resume", r.URL.Path)
+		}
+		q := r.URL.Query()
+		if q.Get("startIndex") != "24" || q.Get("limit") != "12" {
+			t.Errorf("query = %v", q)
+		}
+		serveFixture(t, w, "items.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	if _, total, err := c.Resume(context.Background(), 24, 12); err != nil || total != 41 {
+		t.Fatalf("Resume() total = %d, error = %v", total, err)
+	}
+}
+
+func TestStreamURLs(t *testing.T) {
+	c := NewClient("http://emby.local:8096/", "token", "user", "client")
+
+	if got, want := c.StreamURL(jellyfin.VideoStreamPath("4012", "ms1")), "http://emby.local:8096/Videos/4012/stream?static=true&mediaSourceId=ms1&api_key=token"; got != want {
+		t.Errorf("StreamURL() = %q, want %q", got, want)
+	}
+	if got, want := c.ImageURL(jellyfin.ImagePath("4012", jellyfin.ImagePrimary, "a1"), 240, 360), "http://emby.local:8096/Items/4012/Images/Primary?tag=a1&fillHeight=360&fillWidth=240&quality=90"; got != want {
+		t.Errorf("ImageURL() = %q, want %q", got, want)
+	}
+}
+
+func TestPlaybackInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/Items/4012/PlaybackInfo" {
+			t.Errorf("request = %s %s, want POST /Items/4012/PlaybackInfo", r.Method, r.URL.Path)
+		}
+		// Emby takes the user in the query, Jellyfin in the body.
+		if q := r.URL.Query(); q.Get("UserId") != "user" {
+			t.Errorf("query = %v, want the user ID", q)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		if body["MediaSourceId"] != "mediasource_4012" || body["AudioStreamIndex"] != float64(1) {
+			t.Errorf("body = %v", body)
+		}
+		serveFixture(t, w, "playback_info.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	audio := 1
+	info, err := c.PlaybackInfo(context.Background(), "4012", &PlaybackInfoRequest{
+		MediaSourceID:     "mediasource_4012",
+		AudioStreamIndex:  &audio,
+		EnableTranscoding: true,
+		DeviceProfile:     jellyfin.NewDeviceProfile(false),
+	})
+	if err != nil {
+		t.Fatalf("PlaybackInfo() error = %v", err)
+	}
+	if info.PlaySessionID != "9a8b7c" || len(info.MediaSources) != 1 {
+		t.Fatalf("info = %+v", info)
+	}
+	u, err := url.Parse(c.TranscodeURL(&info.MediaSources[0], 1280, 720))
+	if err != nil {
+		t.Fatalf("TranscodeURL() is not a URL: %v", err)
+	}
+	q := u.Query()
+	if u.Path != "/videos/4012/master.m3u8" || q.Get("MaxWidth") != "1280" || q.Get("MaxHeight") != "720" || q.Get("api_key") != "token" {
+		t.Errorf("TranscodeURL() = %q", u)
+	}
+}
+
+func TestPlaybackReporting(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.URL.Path == "/Sessions/Playing/Progress" {
+			var body map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding request body: %v", err)
+			}
+			if body["ItemId"] != "4012" || body["PositionTicks"] != float64(6000000000) || body["IsPaused"] != true {
+				t.Errorf("body = %v", body)
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	ctx := context.Background()
+	progress := &PlaybackProgress{ItemID: "4012", PlaySessionID: "9a8b7c", PositionTicks: 6000000000, IsPaused: true}
+	if err := c.ReportPlaybackProgress(ctx, progress); err != nil {
+		t.Fatalf("ReportPlaybackProgress() error = %v", err)
+	}
+	if err := c.MarkPlayed(ctx, "4012"); err != nil {
+		t.Errorf("MarkPlayed() error = %v", err)
+	}
+	if err := c.MarkUnplayed(ctx, "4012"); err != nil {
+		t.Errorf("MarkUnplayed() error = %v", err)
+	}
+
+	want := []string{
+		"POST /Sessions/Playing/Progress",
+		"POST /Users/user/PlayedItems/4012",
+		"DELETE /Users/user/PlayedItems/4012",
+	}
+	if strings.Join(requests, ", ") != strings.Join(want, ", ") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestConnect(t *testing.T) {
+	connect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Application"); got != "Scanline/1.0.0" {
+			t.Errorf("X-Application = %q, want Scanline/1.0.0", got)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "POST /service/user/authenticate":
+			body, _ := io.ReadAll(r.Body)
+			form, err := url.ParseQuery(string(body))
+			if err != nil {
+				t.Errorf("parsing request body: %v", err)
+			}
+			if form.Get("nameOrEmail") != "bob@example.com" || form.Get("rawpw") != "secret" {
+				t.Errorf("form = %v", form)
+			}
+			serveFixture(t, w, "connect_authenticate.json")
+		case "GET /service/servers":
+			if r.Header.Get("X-Connect-UserToken") != "connect-token" || r.URL.Query().Get("userId") != "314159" {
+				t.Errorf("request = %v %v", r.Header, r.URL)
+			}
+			serveFixture(t, w, "connect_servers.json")
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer connect.Close()
+	saved := connectURL
+	connectURL = connect.URL + "/service"
+	defer func() { connectURL = saved }()
+
+	ctx := context.Background()
+	result, err := ConnectAuthenticate(ctx, "bob@example.com", "secret")
+	if err != nil {
+		t.Fatalf("ConnectAuthenticate() error = %v", err)
+	}
+	if result.AccessToken != "connect-token" || result.User.ID != "314159" {
+		t.Errorf("result = %+v", result)
+	}
+
+	servers, err := ConnectServers(ctx, result.User.ID, result.AccessToken)
+	if err != nil {
+		t.Fatalf("ConnectServers() error = %v", err)
+	}
+	if len(servers) != 1 || servers[0].SystemID != "e0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5" || servers[0].AccessKey != "access-key" || servers[0].LocalAddress != "http://192.168.1.30:8096" {
+		t.Fatalf("servers = %+v", servers)
+	}
+
+	// The server's access key is exchanged for a token of the local user.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Connect/Exchange" {
+			t.Errorf("path = %s, want /Connect/Exchange", r.URL.Path)
+		}
+		checkAuthorization(t, r, "access-key")
+		if q := r.URL.Query(); q.Get("ConnectUserId") != "314159" {
+			t.Errorf("query = %v", q)
+		}
+		serveFixture(t, w, "connect_exchange.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", servers[0].AccessKey, "user", "client")
+	exchange, err := c.ExchangeConnectToken(ctx, result.User.ID)
+	if err != nil {
+		t.Fatalf("ExchangeConnectToken() error = %v", err)
+	}
+	if exchange.AccessToken != "token" || exchange.LocalUserID != "b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0" {
+		t.Errorf("exchange = %+v", exchange)
+	}
+}
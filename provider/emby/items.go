@@ -0,0 +1,90 @@
+package emby
+
+import (
+	"context"
+)
+
+// Views returns the library views (top-level folders) of the user.
+func (c *Client) Views(ctx context.Context) ([]Item, error) {
+	var resp ItemsResult
+	err := c.get(ctx, "/Users/"+c.userID+"/Views", nil).DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// Items queries items with optional pagination and filtering.
+//
+// Returns the items and the total count available (for pagination).
+func (c *Client) Items(ctx context.Context, opts *ItemsOptions) ([]Item, int, error) {
+	return c.list(ctx, "/Users/"+c.userID+"/Items", opts)
+}
+
+// Item returns detailed information about an item, including its cast and
+// media sources.
+func (c *Client) Item(ctx context.Context, id string) (*Item, error) {
+	var item Item
+	if err := c.get(ctx, "/Users/"+c.userID+"/Items/"+id, nil).DoAndDecode(&item); err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// Resume returns a page of the partially watched items of the user, most
+// recent first, and the total count available.
+func (c *Client) Resume(ctx context.Context, start, limit int) ([]Item, int, error) {
+	return c.list(ctx, "/Users/"+c.userID+"/Items/Resume", &ItemsOptions{StartIndex: start, Limit: limit})
+}
+
+// NextUp returns a page of the next unwatched episode of each series in
+// progress, and the total count available.
+func (c *Client) NextUp(ctx context.Context, start, limit int) ([]Item, int, error) {
+	return c.list(ctx, "/Shows/NextUp", &ItemsOptions{StartIndex: start, Limit: limit})
+}
+
+// AlbumArtists queries the artists credited on albums. Options that only
+// apply to items, such as IncludeItemTypes, are ignored by the server.
+func (c *Client) AlbumArtists(ctx context.Context, opts *ItemsOptions) ([]Item, int, error) {
+	return c.list(ctx, "/Artists/AlbumArtists", opts)
+}
+
+// Similar returns items similar to the given item.
+func (c *Client) Similar(ctx context.Context, id string, limit int) ([]Item, error) {
+	items, _, err := c.list(ctx, "/Items/"+id+"/Similar", &ItemsOptions{Limit: limit})
+	return items, err
+}
+
+// Seasons returns the seasons of a series.
+func (c *Client) Seasons(ctx context.Context, seriesID string) ([]Item, error) {
+	items, _, err := c.list(ctx, "/Shows/"+seriesID+"/Seasons", &ItemsOptions{})
+	return items, err
+}
+
+// Episodes returns the episodes of a season of a series. An empty seasonID
+// returns the episodes of all seasons.
+func (c *Client) Episodes(ctx context.Context, seriesID, seasonID string) ([]Item, error) {
+	query := (&ItemsOptions{Fields: []string{"MediaSources"}}).Query(c.userID)
+	if seasonID != "" {
+		query["seasonId"] = seasonID
+	}
+	var resp ItemsResult
+	err := c.get(ctx, "/Shows/"+seriesID+"/Episodes", query).DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// list fetches a listing endpoint with the query parameters of opts.
+func (c *Client) list(ctx context.Context, path string, opts *ItemsOptions) ([]Item, int, error) {
+	if opts == nil {
+		opts = &ItemsOptions{}
+	}
+	var resp ItemsResult
+	err := c.get(ctx, path, opts.Query(c.userID)).DoAndDecode(&resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Items, resp.TotalRecordCount, nil
+}
@@ -0,0 +1,63 @@
+package emby
+
+import "github.com/0skillallluck/scanline/provider/jellyfin"
+
+// Jellyfin is a fork of Emby and both servers still exchange the same item,
+// user and playback objects, so they are shared with the jellyfin package.
+// Only authentication and some endpoints differ.
+type (
+	Item                 = jellyfin.Item
+	ItemsOptions         = jellyfin.ItemsOptions
+	ItemsResult          = jellyfin.ItemsResult
+	MediaSource          = jellyfin.MediaSource
+	User                 = jellyfin.User
+	AuthenticationResult = jellyfin.AuthenticationResult
+	PublicSystemInfo     = jellyfin.PublicSystemInfo
+	PlaybackInfoRequest  = jellyfin.PlaybackInfoRequest
+	PlaybackInfo         = jellyfin.PlaybackInfo
+	PlaybackError        = jellyfin.PlaybackError
+	PlaybackProgress     = jellyfin.PlaybackProgress
+)
+
+// ConnectUser is an Emby Connect account.
+type ConnectUser struct {
+	ID          string `json:"Id"`
+	Name        string `json:"Name"`
+	DisplayName string `json:"DisplayName,omitempty"`
+	Email       string `json:"Email,omitempty"`
+}
+
+// ConnectAuthenticationResult is the result of signing in to Emby Connect.
+type ConnectAuthenticationResult struct {
+	// AccessToken authenticates requests to Emby Connect.
+	AccessToken string      `json:"AccessToken"`
+	User        ConnectUser `json:"User"`
+}
+
+// ConnectServer is a server linked to an Emby Connect account.
+type ConnectServer struct {
+	// SystemID is the ID of the server (PublicSystemInfo.ID).
+	SystemID string `json:"SystemId"`
+	Name     string `json:"Name"`
+
+	// URL is the remote address of the server, LocalAddress its address
+	// on the server's network.
+	URL          string `json:"Url"`
+	LocalAddress string `json:"LocalAddress,omitempty"`
+
+	// AccessKey is exchanged for a server access token (see
+	// [Client.ExchangeConnectToken]).
+	AccessKey string `json:"AccessKey"`
+
+	// UserType is "Linked" for servers the user was invited to, "Guest"
+	// for guests.
+	UserType string `json:"UserType,omitempty"`
+}
+
+// ConnectExchange is a server access token issued for an Emby Connect
+// user.
+type ConnectExchange struct {
+	// LocalUserID is the ID of the server user linked to the Connect user.
+	LocalUserID string `json:"LocalUserId"`
+	AccessToken string `json:"AccessToken"`
+}
@@ -0,0 +1,72 @@
+package emby
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ImageURL returns a URL for an image path (see jellyfin.ImagePath) scaled
+// to fill the given dimensions.
+func (c *Client) ImageURL(path string, width, height int) string {
+	if path == "" {
+		return ""
+	}
+	return c.baseURL + appendQuery(path, url.Values{
+		"fillWidth":  {strconv.Itoa(width)},
+		"fillHeight": {strconv.Itoa(height)},
+		"quality":    {"90"},
+	})
+}
+
+// StreamURL returns an authenticated URL for a stream path, such as a
+// direct play path (see jellyfin.VideoStreamPath) or the transcoding URL of
+// a media source.
+//
+// This URL includes authentication and can be used directly by media players.
+func (c *Client) StreamURL(path string) string {
+	return c.baseURL + appendQuery(path, url.Values{"api_key": {c.token}})
+}
+
+// PlaybackInfo negotiates playback of an item with the server. It also
+// opens the play session that progress reports refer to.
+func (c *Client) PlaybackInfo(ctx context.Context, itemID string, req *PlaybackInfoRequest) (*PlaybackInfo, error) {
+	var info PlaybackInfo
+	err := c.post(ctx, "/Items/"+itemID+"/PlaybackInfo", req).
+		WithQuery(map[string]string{"UserId": c.userID}).
+		DoAndDecode(&info)
+	if err != nil {
+		return nil, err
+	}
+	if info.ErrorCode != "" {
+		return nil, &PlaybackError{Code: info.ErrorCode}
+	}
+	return &info, nil
+}
+
+// TranscodeURL returns an authenticated URL for the transcoded stream of a
+// media source, limited to the given dimensions (0 for no limit).
+func (c *Client) TranscodeURL(source *MediaSource, maxWidth, maxHeight int) string {
+	path := source.TranscodingURL
+	values := url.Values{}
+	if maxWidth > 0 {
+		values.Set("MaxWidth", strconv.Itoa(maxWidth))
+	}
+	if maxHeight > 0 {
+		values.Set("MaxHeight", strconv.Itoa(maxHeight))
+	}
+	if len(values) > 0 {
+		path = appendQuery(path, values)
+	}
+	return c.StreamURL(path)
+}
+
+// appendQuery adds query parameters to a path that may already have some.
+func appendQuery(path string, values url.Values) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + values.Encode()
+}
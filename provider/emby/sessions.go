@@ -0,0 +1,33 @@
+package emby
+
+import (
+	"context"
+	"net/http"
+)
+
+// ReportPlaybackStart reports that playback of an item started.
+func (c *Client) ReportPlaybackStart(ctx context.Context, progress *PlaybackProgress) error {
+	return do(c.post(ctx, "/Sessions/Playing", progress))
+}
+
+// ReportPlaybackProgress reports the position of an item being played.
+func (c *Client) ReportPlaybackProgress(ctx context.Context, progress *PlaybackProgress) error {
+	return do(c.post(ctx, "/Sessions/Playing/Progress", progress))
+}
+
+// ReportPlaybackStopped reports that playback of an item stopped. The
+// server saves the position as the resume point, or marks the item as
+// played near its end.
+func (c *Client) ReportPlaybackStopped(ctx context.Context, progress *PlaybackProgress) error {
+	return do(c.post(ctx, "/Sessions/Playing/Stopped", progress))
+}
+
+// MarkPlayed marks an item as watched.
+func (c *Client) MarkPlayed(ctx context.Context, itemID string) error {
+	return do(c.post(ctx, "/Users/"+c.userID+"/PlayedItems/"+itemID, nil))
+}
+
+// MarkUnplayed marks an item as unwatched.
+func (c *Client) MarkUnplayed(ctx context.Context, itemID string) error {
+	return do(c.request(ctx, http.MethodDelete, "/Users/"+c.userID+"/PlayedItems/"+itemID))
+}
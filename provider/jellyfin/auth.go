@@ -0,0 +1,109 @@
+package jellyfin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// ErrQuickConnectDisabled is returned by [Client.InitiateQuickConnect] when
+// Quick Connect is turned off on the server.
+var ErrQuickConnectDisabled = errors.New("quick connect is disabled on the server")
+
+// PublicInfo returns the server's name, ID and version. It does not
+// require signing in and is used to check that a URL points to a server.
+func (c *Client) PublicInfo(ctx context.Context) (*PublicSystemInfo, error) {
+	var info PublicSystemInfo
+	if err := c.get(ctx, "/System/Info/Public", nil).DoAndDecode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// AuthenticateByName signs in with a username and password.
+func (c *Client) AuthenticateByName(ctx context.Context, username, password string) (*AuthenticationResult, error) {
+	body := map[string]string{"Username": username, "Pw": password}
+	var result AuthenticationResult
+	if err := c.post(ctx, "/Users/AuthenticateByName", body).DoAndDecode(&result); err != nil {
+		return nil, err
+	}
+	slog.Debug("jellyfin: signed in", "user", result.User.Name)
+	return &result, nil
+}
+
+// QuickConnect is a pending Quick Connect request. The user approves it by
+// entering Code in another signed-in client.
+type QuickConnect struct {
+	// Secret identifies the request when polling and signing in.
+	Secret string `json:"Secret"`
+
+	// Code is the code shown to the user.
+	Code string `json:"Code"`
+
+	// Authenticated is set once the user approved the request.
+	Authenticated bool `json:"Authenticated"`
+}
+
+// InitiateQuickConnect starts a Quick Connect request.
+func (c *Client) InitiateQuickConnect(ctx context.Context) (*QuickConnect, error) {
+	var enabled bool
+	if err := c.get(ctx, "/QuickConnect/Enabled", nil).DoAndDecode(&enabled); err != nil {
+		return nil, err
+	}
+	if !enabled {
+		return nil, ErrQuickConnectDisabled
+	}
+
+	var qc QuickConnect
+	if err := c.post(ctx, "/QuickConnect/Initiate", nil).DoAndDecode(&qc); err != nil {
+		return nil, err
+	}
+	slog.Debug("jellyfin: quick connect initiated")
+	return &qc, nil
+}
+
+// QuickConnectState returns the current state of a Quick Connect request.
+func (c *Client) QuickConnectState(ctx context.Context, secret string) (*QuickConnect, error) {
+	var qc QuickConnect
+	err := c.get(ctx, "/QuickConnect/Connect", map[string]string{"secret": secret}).DoAndDecode(&qc)
+	if err != nil {
+		return nil, err
+	}
+	return &qc, nil
+}
+
+// AuthenticateWithQuickConnect signs in with an approved Quick Connect
+// request.
+func (c *Client) AuthenticateWithQuickConnect(ctx context.Context, secret string) (*AuthenticationResult, error) {
+	body := map[string]string{"Secret": secret}
+	var result AuthenticationResult
+	if err := c.post(ctx, "/Users/AuthenticateWithQuickConnect", body).DoAndDecode(&result); err != nil {
+		return nil, err
+	}
+	slog.Debug("jellyfin: signed in with quick connect", "user", result.User.Name)
+	return &result, nil
+}
+
+// PollQuickConnect polls a Quick Connect request until the user approves it
+// or the context is cancelled, then signs in.
+func (c *Client) PollQuickConnect(ctx context.Context, secret string, interval time.Duration) (*AuthenticationResult, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("quick connect canceled: %w", ctx.Err())
+		case <-ticker.C:
+			qc, err := c.QuickConnectState(ctx, secret)
+			if err != nil {
+				return nil, fmt.Errorf("checking quick connect state: %w", err)
+			}
+			if qc.Authenticated {
+				return c.AuthenticateWithQuickConnect(ctx, secret)
+			}
+		}
+	}
+}
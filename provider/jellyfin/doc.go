@@ -0,0 +1,38 @@
+// Package jellyfin provides a client for interacting with Jellyfin server APIs.
+//
+// # Signing In
+//
+// A [Client] without a token can sign in with a username and password, or
+// with Quick Connect, where the user approves a code in another client:
+//
+//	client := jellyfin.NewClient("http://localhost:8096", "", "", deviceID)
+//	result, err := client.AuthenticateByName(ctx, "user", "password")
+//
+//	qc, err := client.InitiateQuickConnect(ctx)
+//	fmt.Printf("Enter code %s in another client\n", qc.Code)
+//	result, err := client.PollQuickConnect(ctx, qc.Secret, 2*time.Second)
+//
+// Create a new client with the returned token and user ID for the user's
+// requests:
+//
+//	client = jellyfin.NewClient(serverURL, result.AccessToken, result.User.ID, deviceID)
+//
+// # Browsing
+//
+// Libraries are the user's views ([Client.Views]). Their content is
+// queried with [Client.Items], series are browsed with [Client.Seasons]
+// and [Client.Episodes]:
+//
+//	views, err := client.Views(ctx)
+//	items, total, err := client.Items(ctx, &jellyfin.ItemsOptions{ParentID: views[0].ID, Limit: 50})
+//
+// # Playback
+//
+// Media sources are played directly from [VideoStreamPath] or
+// [AudioStreamPath] via [Client.StreamURL], or transcoded after
+// negotiating with [Client.PlaybackInfo]. Progress is reported to the
+// /Sessions/Playing endpoints with [Client.ReportPlaybackStart] and its
+// siblings.
+//
+// Times are expressed in ticks of 100 nanoseconds (see [TicksPerMillisecond]).
+package jellyfin
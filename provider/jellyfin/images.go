@@ -0,0 +1,39 @@
+package jellyfin
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ImagePath returns the path of an item image. The tag identifies the
+// image version, so that cached copies are replaced when it changes.
+func ImagePath(itemID, imageType, tag string) string {
+	path := "/Items/" + itemID + "/Images/" + imageType
+	if tag != "" {
+		path += "?tag=" + url.QueryEscape(tag)
+	}
+	return path
+}
+
+// ImageURL returns a URL for an image path (see [ImagePath]) scaled to fill
+// the given dimensions.
+func (c *Client) ImageURL(path string, width, height int) string {
+	if path == "" {
+		return ""
+	}
+	return c.baseURL + appendQuery(path, url.Values{
+		"fillWidth":  {strconv.Itoa(width)},
+		"fillHeight": {strconv.Itoa(height)},
+		"quality":    {"90"},
+	})
+}
+
+// appendQuery adds query parameters to a path that may already have some.
+func appendQuery(path string, values url.Values) string {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + values.Encode()
+}
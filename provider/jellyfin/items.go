@@ -0,0 +1,243 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Item fields requested in listings. Jellyfin only returns the fields it
+// is asked for beyond a small default set.
+var listFields = []string{
+	"DateCreated", "DateLastSaved", "PremiereDate", "SortName", "Overview",
+	"ChildCount", "RecursiveItemCount", "ParentId",
+}
+
+// Item fields requested in addition to listFields for a single item.
+var detailFields = []string{
+	"OriginalTitle", "Taglines", "Genres", "Studios", "People",
+	"ProviderIds", "MediaSources", "MediaStreams",
+}
+
+// Sort orders accepted by [ItemsOptions.SortBy].
+const (
+	SortByName         = "SortName"
+	SortByDateCreated  = "DateCreated"
+	SortByPremiereDate = "PremiereDate"
+
+	// SortByIndex orders the children of albums and seasons by disc or
+	// season, then track or episode number.
+	SortByIndex = "ParentIndexNumber,IndexNumber,SortName"
+)
+
+// ItemsOptions specifies options for item queries.
+type ItemsOptions struct {
+	// ParentID limits results to the children of an item or library view.
+	ParentID string
+
+	// IDs limits results to the given items.
+	IDs []string
+
+	// Recursive includes all descendants of ParentID, not only its direct
+	// children.
+	Recursive bool
+
+	// IncludeItemTypes filters by item type (see the Type* constants).
+	IncludeItemTypes []string
+
+	// StartIndex is the starting offset for pagination.
+	StartIndex int
+
+	// Limit is the maximum number of items to return.
+	Limit int
+
+	// SortBy is one of the SortBy* constants.
+	SortBy string
+
+	// Descending reverses the sort order.
+	Descending bool
+
+	// SearchTerm filters by name.
+	SearchTerm string
+
+	// GenreIDs filters by genre ID.
+	GenreIDs []string
+
+	// PersonIDs filters by the ID of a credited person.
+	PersonIDs []string
+
+	// PersonTypes limits PersonIDs to credits of the given types (see the
+	// Person* constants).
+	PersonTypes []string
+
+	// AlbumArtistIDs filters albums and tracks by album artist.
+	AlbumArtistIDs []string
+
+	// MinDateLastSaved limits results to items updated at or after this
+	// time.
+	MinDateLastSaved time.Time
+
+	// Fields adds item fields to the default listing fields (e.g.,
+	// "ProviderIds", "MediaSources").
+	Fields []string
+}
+
+// Query returns the query parameters for the options. Emby servers accept
+// the same parameters.
+func (o *ItemsOptions) Query(userID string) map[string]string {
+	query := map[string]string{
+		"userId": userID,
+		"fields": strings.Join(slices.Concat(listFields, o.Fields), ","),
+	}
+	if o.ParentID != "" {
+		query["parentId"] = o.ParentID
+	}
+	if len(o.IDs) > 0 {
+		query["ids"] = strings.Join(o.IDs, ",")
+	}
+	if o.Recursive {
+		query["recursive"] = "true"
+	}
+	if len(o.IncludeItemTypes) > 0 {
+		query["includeItemTypes"] = strings.Join(o.IncludeItemTypes, ",")
+	}
+	if o.StartIndex > 0 {
+		query["startIndex"] = strconv.Itoa(o.StartIndex)
+	}
+	if o.Limit > 0 {
+		query["limit"] = strconv.Itoa(o.Limit)
+	}
+	if o.SortBy != "" {
+		query["sortBy"] = o.SortBy
+		query["sortOrder"] = "Ascending"
+		if o.Descending {
+			query["sortOrder"] = "Descending"
+		}
+	}
+	if o.SearchTerm != "" {
+		query["searchTerm"] = o.SearchTerm
+	}
+	if len(o.GenreIDs) > 0 {
+		query["genreIds"] = strings.Join(o.GenreIDs, "|")
+	}
+	if len(o.PersonIDs) > 0 {
+		query["personIds"] = strings.Join(o.PersonIDs, "|")
+	}
+	if len(o.PersonTypes) > 0 {
+		query["personTypes"] = strings.Join(o.PersonTypes, ",")
+	}
+	if len(o.AlbumArtistIDs) > 0 {
+		query["albumArtistIds"] = strings.Join(o.AlbumArtistIDs, "|")
+	}
+	if !o.MinDateLastSaved.IsZero() {
+		query["minDateLastSaved"] = o.MinDateLastSaved.UTC().Format(time.RFC3339)
+	}
+	return query
+}
+
+// Views returns the library views (top-level folders) of the user.
+func (c *Client) Views(ctx context.Context) ([]Item, error) {
+	var resp ItemsResult
+	err := c.get(ctx, "/Users/"+c.userID+"/Views", nil).DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// Items queries items with optional pagination and filtering.
+//
+// Returns the items and the total count available (for pagination).
+func (c *Client) Items(ctx context.Context, opts *ItemsOptions) ([]Item, int, error) {
+	if opts == nil {
+		opts = &ItemsOptions{}
+	}
+	var resp ItemsResult
+	err := c.get(ctx, "/Items", opts.Query(c.userID)).DoAndDecode(&resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Items, resp.TotalRecordCount, nil
+}
+
+// Item returns detailed information about an item, including its cast and
+// media sources.
+func (c *Client) Item(ctx context.Context, id string) (*Item, error) {
+	opts := &ItemsOptions{IDs: []string{id}, Fields: detailFields}
+	items, _, err := c.Items(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("item %s not found", id)
+	}
+	return &items[0], nil
+}
+
+// Resume returns a page of the partially watched items of the user, most
+// recent first, and the total count available.
+func (c *Client) Resume(ctx context.Context, start, limit int) ([]Item, int, error) {
+	query := pageQuery(start, limit)
+	var resp ItemsResult
+	err := c.get(ctx, "/Users/"+c.userID+"/Items/Resume", query).DoAndDecode(&resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Items, resp.TotalRecordCount, nil
+}
+
+// NextUp returns a page of the next unwatched episode of each series in
+// progress, and the total count available.
+func (c *Client) NextUp(ctx context.Context, start, limit int) ([]Item, int, error) {
+	query := pageQuery(start, limit)
+	query["userId"] = c.userID
+	var resp ItemsResult
+	err := c.get(ctx, "/Shows/NextUp", query).DoAndDecode(&resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Items, resp.TotalRecordCount, nil
+}
+
+// AlbumArtists queries the artists credited on albums. Options that only
+// apply to items, such as IncludeItemTypes, are ignored by the server.
+func (c *Client) AlbumArtists(ctx context.Context, opts *ItemsOptions) ([]Item, int, error) {
+	if opts == nil {
+		opts = &ItemsOptions{}
+	}
+	var resp ItemsResult
+	err := c.get(ctx, "/Artists/AlbumArtists", opts.Query(c.userID)).DoAndDecode(&resp)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.Items, resp.TotalRecordCount, nil
+}
+
+// Similar returns items similar to the given item.
+func (c *Client) Similar(ctx context.Context, id string, limit int) ([]Item, error) {
+	query := pageQuery(0, limit)
+	query["userId"] = c.userID
+	var resp ItemsResult
+	err := c.get(ctx, "/Items/"+id+"/Similar", query).DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// pageQuery returns the query parameters of a page of a listing.
+func pageQuery(start, limit int) map[string]string {
+	query := map[string]string{
+		"fields": strings.Join(listFields, ","),
+	}
+	if start > 0 {
+		query["startIndex"] = strconv.Itoa(start)
+	}
+	if limit > 0 {
+		query["limit"] = strconv.Itoa(limit)
+	}
+	return query
+}
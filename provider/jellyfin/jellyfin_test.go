@@ -0,0 +1,529 @@
+package jellyfin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveFixture answers with a recorded Jellyfin response from testdata.
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Errorf("reading fixture: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// checkAuthorization reports an error unless the request carries the
+// MediaBrowser authorization of the test client, with the given token.
+func checkAuthorization(t *testing.T, r *http.Request, token string) {
+	t.Helper()
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "MediaBrowser ") || !strings.Contains(auth, `DeviceId="client"`) {
+		t.Errorf("Authorization = %q, want MediaBrowser with the device ID", auth)
+	}
+	if token == "" && strings.Contains(auth, "Token=") {
+		t.Errorf("Authorization = %q, want no token before signing in", auth)
+	}
+	if token != "" && !strings.Contains(auth, `Token="`+token+`"`) {
+		t.Errorf("Authorization = %q, want the token", auth)
+	}
+}
+
+func TestPublicInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/System/Info/Public" {
+			t.Errorf("path = %s, want /System/Info/Public", r.URL.Path)
+		}
+		checkAuthorization(t, r, "")
+		serveFixture(t, w, "public_info.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "", "user", "client")
+	info, err := c.PublicInfo(context.Background())
+	if err != nil {
+		t.Fatalf("PublicInfo() error = %v", err)
+	}
+	if info.ID != "6c4f6b1e2a3d4c5b8e9f0a1b2c3d4e5f" || info.ServerName != "Living Room" || info.Version != "10.10.3" {
+		t.Errorf("info = %+v", info)
+	}
+}
+
+func TestAuthenticateByName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/Users/AuthenticateByName" {
+			t.Errorf("request = %s %s, want POST /Users/AuthenticateByName", r.Method, r.URL.Path)
+		}
+		checkAuthorization(t, r, "")
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		if body["Username"] != "alice" || body["Pw"] != "secret" {
+			t.Errorf("body = %v", body)
+		}
+		serveFixture(t, w, "authenticate.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "", "user", "client")
+	result, err := c.AuthenticateByName(context.Background(), "alice", "secret")
+	if err != nil {
+		t.Fatalf("AuthenticateByName() error = %v", err)
+	}
+	if result.AccessToken != "token" || result.User.ID != "a1b2c3d4e5f60718293a4b5c6d7e8f90" || !result.User.Policy.IsAdministrator {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestAuthenticatedRequestsSendToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checkAuthorization(t, r, "token")
+		serveFixture(t, w, "views.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	if _, err := c.Views(context.Background()); err != nil {
+		t.Fatalf("Views() error = %v", err)
+	}
+}
+
+func TestQuickConnect(t *testing.T) {
+	const secret = "8f1d3b6e0c2a4f9b7d5e3c1a0b9f8e7d"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /QuickConnect/Enabled":
+			serveFixture(t, w, "quickconnect_enabled.json")
+		case "POST /QuickConnect/Initiate":
+			serveFixture(t, w, "quickconnect_initiate.json")
+		case "GET /QuickConnect/Connect":
+			if got := r.URL.Query().Get("secret"); got != secret {
+				t.Errorf("secret = %q, want %q", got, secret)
+			}
+			serveFixture(t, w, "quickconnect_authenticated.json")
+		case "POST /Users/AuthenticateWithQuickConnect":
+			var body map[string]string
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding request body: %v", err)
+			}
+			if body["Secret"] != secret {
+				t.Errorf("Secret = %q, want %q", body["Secret"], secret)
+			}
+			serveFixture(t, w, "authenticate.json")
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "", "user", "client")
+	qc, err := c.InitiateQuickConnect(context.Background())
+	if err != nil {
+		t.Fatalf("InitiateQuickConnect() error = %v", err)
+	}
+	if qc.Code != "482913" || qc.Secret != secret {
+		t.Errorf("qc = %+v", qc)
+	}
+
+	result, err := c.PollQuickConnect(context.Background(), qc.Secret, time.Millisecond)
+	if err != nil {
+		t.Fatalf("PollQuickConnect() error = %v", err)
+	}
+	if result.AccessToken != "token" {
+		t.Errorf("AccessToken = %q, want token", result.AccessToken)
+	}
+}
+
+func TestQuickConnectDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/QuickConnect/Enabled" {
+			t.Errorf("path = %s, want only the enabled check", r.URL.Path)
+		}
+		serveFixture(t, w, "quickconnect_disabled.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "", "user", "client")
+	if _, err := c.InitiateQuickConnect(context.Background()); !errors.Is(err, ErrQuickConnectDisabled) {
+		t.Errorf("InitiateQuickConnect() error = %v, want ErrQuickConnectDisabled", err)
+	}
+}
+
+func TestViews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Users/user/Views" {
+			t.Errorf("path = %s, want /Users/user/Views", r.URL.Path)
+		}
+		serveFixture(t, w, "views.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	views, err := c.Views(context.Background())
+	if err != nil {
+		t.Fatalf("Views() error = %v", err)
+	}
+	if len(views) != 2 {
+		t.Fatalf("len(views) = %d, want 2", len(views))
+	}
+	if views[0].Name != "Movies" || views[0].CollectionType != CollectionMovies || views[0].ImageTags[ImagePrimary] != "3a1c" {
+		t.Errorf("views[0] = %+v", views[0])
+	}
+}
+
+func TestItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Items" {
+			t.Errorf("path = %s, want /Items", r.URL.Path)
+		}
+		query := r.URL.Query()
+		want := map[string]string{
+			"userId":           "user",
+			"parentId":         "f137a2dd21bbc1b99aa5c0f6bf02a805",
+			"recursive":        "true",
+			"includeItemTypes": "Movie",
+			"startIndex":       "20",
+			"limit":            "50",
+			"sortBy":           "DateCreated",
+			"sortOrder":        "Descending",
+			"genreIds":         "g1|g2",
+			"personIds":        "p1",
+			"personTypes":      "Actor",
+			"minDateLastSaved": "2026-01-01T00:00:00Z",
+		}
+		for key, value := range want {
+			if got := query.Get(key); got != value {
+				t.Errorf("%s = %q, want %q", key, got, value)
+			}
+		}
+		if fields := query.Get("fields"); !strings.Contains(fields, "DateCreated") || !strings.Contains(fields, "ProviderIds") {
+			t.Errorf("fields = %q, want the listing fields and ProviderIds", fields)
+		}
+		serveFixture(t, w, "items.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	items, total, err := c.Items(context.Background(), &ItemsOptions{
+		ParentID:         "f137a2dd21bbc1b99aa5c0f6bf02a805",
+		Recursive:        true,
+		IncludeItemTypes: []string{TypeMovie},
+		StartIndex:       20,
+		Limit:            50,
+		SortBy:           SortByDateCreated,
+		Descending:       true,
+		GenreIDs:         []string{"g1", "g2"},
+		PersonIDs:        []string{"p1"},
+		PersonTypes:      []string{PersonActor},
+		MinDateLastSaved: time.Unix(1767225600, 0),
+		Fields:           []string{"ProviderIds"},
+	})
+	if err != nil {
+		t.Fatalf("Items() error = %v", err)
+	}
+	if total != 120 || len(items) != 2 {
+		t.Fatalf("total = %d, len(items) = %d, want 120 and 2", total, len(items))
+	}
+	movie := items[0]
+	if movie.Name != "The Matrix" || movie.RunTimeTicks != 81720000000 || movie.CriticRating != 83 {
+		t.Errorf("items[0] = %+v", movie)
+	}
+	if movie.UserData == nil || movie.UserData.PlaybackPositionTicks != 36000000000 {
+		t.Errorf("UserData = %+v", movie.UserData)
+	}
+	if got := movie.PremiereDate.Format("2006-01-02"); got != "1999-03-30" {
+		t.Errorf("PremiereDate = %s, want 1999-03-30", got)
+	}
+	// Dates without a time zone are taken as UTC.
+	if got := items[1].DateCreated.Unix(); got != 1762160400 {
+		t.Errorf("DateCreated = %d, want 1762160400", got)
+	}
+}
+
+func TestItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if got := query.Get("ids"); got != "5e7f9a1b3c5d7e9f1a3b5c7d9e1f3a5b" {
+			t.Errorf("ids = %q", got)
+		}
+		if fields := query.Get("fields"); !strings.Contains(fields, "People") || !strings.Contains(fields, "MediaSources") {
+			t.Errorf("fields = %q, want the detail fields", fields)
+		}
+		serveFixture(t, w, "item.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	item, err := c.Item(context.Background(), "5e7f9a1b3c5d7e9f1a3b5c7d9e1f3a5b")
+	if err != nil {
+		t.Fatalf("Item() error = %v", err)
+	}
+	if item.Type != TypeEpisode || item.SeriesName != "Example Show" || item.IndexNumber != 1 {
+		t.Errorf("item = %+v", item)
+	}
+	if len(item.People) != 2 || item.People[0].Role != "Ann" || item.People[1].Type != PersonDirector {
+		t.Errorf("People = %+v", item.People)
+	}
+	if item.ProviderIDs["Imdb"] != "tt0000001" {
+		t.Errorf("ProviderIDs = %v", item.ProviderIDs)
+	}
+	if len(item.MediaSources) != 1 || len(item.MediaSources[0].MediaStreams) != 3 {
+		t.Fatalf("MediaSources = %+v", item.MediaSources)
+	}
+	if audio := item.MediaSources[0].MediaStreams[1]; audio.Type != StreamAudio || audio.Channels != 6 {
+		t.Errorf("audio stream = %+v", audio)
+	}
+}
+
+func TestItemNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(t, w, "items_empty.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	if _, err := c.Item(context.Background(), "missing"); err == nil {
+		t.Error("Item() error = nil, want an error for an unknown item")
+	}
+}
+
+func TestSeasonsAndEpisodes(t *testing.T) {
+	const show = "7a9b1c3d5e7f9a1b3c5d7e9f1a3b5c7d"
+	var seasonID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Shows/" + show + "/Seasons":
+			serveFixture(t, w, "seasons.json")
+		case "/Shows/" + show + "/Episodes":
+			if got := r.URL.Query().Get("seasonId"); got != seasonID {
+				t.Errorf("seasonId = %q, want %q", got, seasonID)
+			}
+			serveFixture(t, w, "episodes.json")
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	seasons, err := c.Seasons(context.Background(), show)
+	if err != nil {
+		t.Fatalf("Seasons() error = %v", err)
+	}
+	if len(seasons) != 1 || seasons[0].ChildCount != 10 || seasons[0].UserData.UnplayedItemCount != 4 {
+		t.Fatalf("seasons = %+v", seasons)
+	}
+
+	seasonID = seasons[0].ID
+	episodes, err := c.Episodes(context.Background(), show, seasonID)
+	if err != nil {
+		t.Fatalf("Episodes() error = %v", err)
+	}
+	if len(episodes) != 2 || episodes[1].Name != "The Second One" {
+		t.Errorf("episodes = %+v", episodes)
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	c := NewClient("http://jellyfin:8096/", "token", "user", "client")
+
+	path := ImagePath("abc", ImagePrimary, "b7e2")
+	if path != "/Items/abc/Images/Primary?tag=b7e2" {
+		t.Errorf("ImagePath() = %q", path)
+	}
+	want := "http://jellyfin:8096/Items/abc/Images/Primary?tag=b7e2&fillHeight=300&fillWidth=200&quality=90"
+	if got := c.ImageURL(path, 200, 300); got != want {
+		t.Errorf("ImageURL() = %q, want %q", got, want)
+	}
+	if got := c.ImageURL("", 200, 300); got != "" {
+		t.Errorf("ImageURL(\"\") = %q, want empty", got)
+	}
+}
+
+func TestStreamURL(t *testing.T) {
+	c := NewClient("http://jellyfin:8096", "token", "user", "client")
+
+	want := "http://jellyfin:8096/Videos/abc/stream?static=true&mediaSourceId=def&api_key=token"
+	if got := c.StreamURL(VideoStreamPath("abc", "def")); got != want {
+		t.Errorf("StreamURL() = %q, want %q", got, want)
+	}
+	want = "http://jellyfin:8096/Audio/abc/stream?static=true&mediaSourceId=def&api_key=token"
+	if got := c.StreamURL(AudioStreamPath("abc", "def")); got != want {
+		t.Errorf("StreamURL() = %q, want %q", got, want)
+	}
+}
+
+func TestPlaybackInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/Items/5e7f9a1b3c5d7e9f1a3b5c7d9e1f3a5b/PlaybackInfo" {
+			t.Errorf("request = %s %s, want POST PlaybackInfo", r.Method, r.URL.Path)
+		}
+		var body struct {
+			UserID              string `json:"UserId"`
+			MaxStreamingBitrate int
+			StartTimeTicks      int64
+			AudioStreamIndex    *int
+			SubtitleStreamIndex *int
+			EnableDirectPlay    bool
+			DeviceProfile       DeviceProfile
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		if body.UserID != "user" || body.MaxStreamingBitrate != 4000000 || body.StartTimeTicks != 600000000 || body.EnableDirectPlay {
+			t.Errorf("body = %+v", body)
+		}
+		if body.AudioStreamIndex == nil || *body.AudioStreamIndex != 1 || body.SubtitleStreamIndex == nil || *body.SubtitleStreamIndex != 2 {
+			t.Errorf("stream indexes = %v, %v", body.AudioStreamIndex, body.SubtitleStreamIndex)
+		}
+		if len(body.DeviceProfile.SubtitleProfiles) == 0 || body.DeviceProfile.SubtitleProfiles[0].Method != "Encode" {
+			t.Errorf("SubtitleProfiles = %+v, want burn-in", body.DeviceProfile.SubtitleProfiles)
+		}
+		serveFixture(t, w, "playback_info.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	audio, subtitle := 1, 2
+	info, err := c.PlaybackInfo(context.Background(), "5e7f9a1b3c5d7e9f1a3b5c7d9e1f3a5b", &PlaybackInfoRequest{
+		MediaSourceID:       "5e7f9a1b3c5d7e9f1a3b5c7d9e1f3a5b",
+		MaxStreamingBitrate: 4000000,
+		StartTimeTicks:      600000000,
+		AudioStreamIndex:    &audio,
+		SubtitleStreamIndex: &subtitle,
+		EnableTranscoding:   true,
+		DeviceProfile:       NewDeviceProfile(true),
+	})
+	if err != nil {
+		t.Fatalf("PlaybackInfo() error = %v", err)
+	}
+	if info.PlaySessionID != "3e0c1f8a" || len(info.MediaSources) != 1 {
+		t.Fatalf("info = %+v", info)
+	}
+	got := c.TranscodeURL(&info.MediaSources[0], 1280, 720)
+	if !strings.HasPrefix(got, c.ServerURL()+"/videos/5e7f9a1b3c5d7e9f1a3b5c7d9e1f3a5b/master.m3u8?") {
+		t.Errorf("TranscodeURL() = %q", got)
+	}
+	for _, param := range []string{"PlaySessionId=3e0c1f8a", "maxWidth=1280", "maxHeight=720", "api_key=token"} {
+		if !strings.Contains(got, param) {
+			t.Errorf("TranscodeURL() = %q, want %s", got, param)
+		}
+	}
+}
+
+func TestPlaybackInfoRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(t, w, "playback_info_error.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	_, err := c.PlaybackInfo(context.Background(), "abc", &PlaybackInfoRequest{})
+	var perr *PlaybackError
+	if !errors.As(err, &perr) || perr.Code != "NotAllowed" {
+		t.Errorf("PlaybackInfo() error = %v, want PlaybackError NotAllowed", err)
+	}
+}
+
+func TestPlaybackReporting(t *testing.T) {
+	var (
+		paths []string
+		last  PlaybackProgress
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		paths = append(paths, r.URL.Path)
+		if err := json.NewDecoder(r.Body).Decode(&last); err != nil {
+			t.Errorf("decoding request body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	ctx := context.Background()
+	progress := &PlaybackProgress{ItemID: "abc", PlaySessionID: "3e0c1f8a", PositionTicks: 120 * 1000 * TicksPerMillisecond}
+
+	if err := c.ReportPlaybackStart(ctx, progress); err != nil {
+		t.Fatalf("ReportPlaybackStart() error = %v", err)
+	}
+	progress.IsPaused = true
+	if err := c.ReportPlaybackProgress(ctx, progress); err != nil {
+		t.Fatalf("ReportPlaybackProgress() error = %v", err)
+	}
+	if last != *progress {
+		t.Errorf("body = %+v, want %+v", last, *progress)
+	}
+	if err := c.ReportPlaybackStopped(ctx, progress); err != nil {
+		t.Fatalf("ReportPlaybackStopped() error = %v", err)
+	}
+
+	want := []string{"/Sessions/Playing", "/Sessions/Playing/Progress", "/Sessions/Playing/Stopped"}
+	if strings.Join(paths, " ") != strings.Join(want, " ") {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestMarkPlayed(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Users/user/PlayedItems/abc" {
+			t.Errorf("path = %s, want /Users/user/PlayedItems/abc", r.URL.Path)
+		}
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	if err := c.MarkPlayed(context.Background(), "abc"); err != nil {
+		t.Fatalf("MarkPlayed() error = %v", err)
+	}
+	if err := c.MarkUnplayed(context.Background(), "abc"); err != nil {
+		t.Fatalf("MarkUnplayed() error = %v", err)
+	}
+	if len(methods) != 2 || methods[0] != http.MethodPost || methods[1] != http.MethodDelete {
+		t.Errorf("methods = %v, want POST then DELETE", methods)
+	}
+}
+
+func TestLyrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Audio/abc/Lyrics" {
+			t.Errorf("path = %s, want /Audio/abc/Lyrics", r.URL.Path)
+		}
+		serveFixture(t, w, "lyrics.json")
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL+"/", "token", "user", "client")
+	lyrics, err := c.Lyrics(context.Background(), LyricsPath("abc"))
+	if err != nil {
+		t.Fatalf("Lyrics() error = %v", err)
+	}
+	if !lyrics.Synced() {
+		t.Error("Synced() = false, want true")
+	}
+	want := "[00:12.50]First line\n[01:07.12]Second line\n"
+	if got := lyrics.LRC(); got != want {
+		t.Errorf("LRC() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package jellyfin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LyricLine is a line of lyrics.
+type LyricLine struct {
+	Text string `json:"Text"`
+
+	// Start is the start time in ticks; nil for unsynced lyrics.
+	Start *int64 `json:"Start,omitempty"`
+}
+
+// Lyrics are the lyrics of a track.
+type Lyrics struct {
+	Lines []LyricLine `json:"Lyrics"`
+}
+
+// Synced reports whether the lyrics have timestamps.
+func (l *Lyrics) Synced() bool {
+	for _, line := range l.Lines {
+		if line.Start != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// LRC formats synced lyrics in the LRC format, and unsynced lyrics as
+// plain text.
+func (l *Lyrics) LRC() string {
+	synced := l.Synced()
+	var b strings.Builder
+	for _, line := range l.Lines {
+		if synced && line.Start != nil {
+			ms := *line.Start / TicksPerMillisecond
+			fmt.Fprintf(&b, "[%02d:%02d.%02d]", ms/60000, ms/1000%60, ms/10%100)
+		}
+		b.WriteString(line.Text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// LyricsPath returns the path of the lyrics of a track.
+func LyricsPath(itemID string) string {
+	return "/Audio/" + itemID + "/Lyrics"
+}
+
+// Lyrics returns the lyrics at a path (see [LyricsPath]).
+func (c *Client) Lyrics(ctx context.Context, path string) (*Lyrics, error) {
+	var lyrics Lyrics
+	if err := c.get(ctx, path, nil).DoAndDecode(&lyrics); err != nil {
+		return nil, err
+	}
+	return &lyrics, nil
+}
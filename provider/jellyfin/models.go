@@ -0,0 +1,373 @@
+package jellyfin
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TicksPerMillisecond converts Jellyfin ticks (100 ns units) to milliseconds.
+const TicksPerMillisecond = 10000
+
+// Item types.
+const (
+	TypeMovie            = "Movie"
+	TypeSeries           = "Series"
+	TypeSeason           = "Season"
+	TypeEpisode          = "Episode"
+	TypeMusicArtist      = "MusicArtist"
+	TypeMusicAlbum       = "MusicAlbum"
+	TypeAudio            = "Audio"
+	TypePhoto            = "Photo"
+	TypePhotoAlbum       = "PhotoAlbum"
+	TypeVideo            = "Video"
+	TypeFolder           = "Folder"
+	TypeCollectionFolder = "CollectionFolder"
+)
+
+// Collection types of library views.
+const (
+	CollectionMovies      = "movies"
+	CollectionTVShows     = "tvshows"
+	CollectionMusic       = "music"
+	CollectionHomeVideos  = "homevideos"
+	CollectionMusicVideos = "musicvideos"
+	CollectionPhotos      = "photos"
+)
+
+// Image types.
+const (
+	ImagePrimary  = "Primary"
+	ImageBackdrop = "Backdrop"
+	ImageThumb    = "Thumb"
+)
+
+// Media stream types.
+const (
+	StreamVideo    = "Video"
+	StreamAudio    = "Audio"
+	StreamSubtitle = "Subtitle"
+	StreamLyric    = "Lyric"
+)
+
+// Person types.
+const (
+	PersonActor    = "Actor"
+	PersonDirector = "Director"
+	PersonWriter   = "Writer"
+)
+
+// Item represents a media item or folder (BaseItemDto).
+type Item struct {
+	// ID is the unique identifier for this item.
+	ID string `json:"Id"`
+
+	// ServerID is the ID of the server the item belongs to.
+	ServerID string `json:"ServerId,omitempty"`
+
+	// Type is the item type (see the Type* constants).
+	Type string `json:"Type"`
+
+	// CollectionType is the content type of library views (see the
+	// Collection* constants).
+	CollectionType string `json:"CollectionType,omitempty"`
+
+	// Name is the display title.
+	Name string `json:"Name"`
+
+	// SortName is the title used for sorting.
+	SortName string `json:"SortName,omitempty"`
+
+	// OriginalTitle is the original title (for foreign content).
+	OriginalTitle string `json:"OriginalTitle,omitempty"`
+
+	// Overview is the plot summary or description.
+	Overview string `json:"Overview,omitempty"`
+
+	// Taglines are the promotional taglines.
+	Taglines []string `json:"Taglines,omitempty"`
+
+	// ProductionYear is the release year.
+	ProductionYear int `json:"ProductionYear,omitempty"`
+
+	// PremiereDate is the original release date.
+	PremiereDate Date `json:"PremiereDate,omitempty"`
+
+	// DateCreated is when the item was added to the library.
+	DateCreated Date `json:"DateCreated,omitempty"`
+
+	// DateLastSaved is when the item was last updated.
+	DateLastSaved Date `json:"DateLastSaved,omitempty"`
+
+	// OfficialRating is the content rating (PG-13, TV-MA, etc.).
+	OfficialRating string `json:"OfficialRating,omitempty"`
+
+	// CommunityRating is the audience rating on a 10-point scale.
+	CommunityRating float64 `json:"CommunityRating,omitempty"`
+
+	// CriticRating is the critic rating in percent (Rotten Tomatoes).
+	CriticRating float64 `json:"CriticRating,omitempty"`
+
+	// RunTimeTicks is the runtime in ticks.
+	RunTimeTicks int64 `json:"RunTimeTicks,omitempty"`
+
+	// UserData is the user's playback state of the item.
+	UserData *UserData `json:"UserData,omitempty"`
+
+	// ChildCount is the number of direct children (seasons for series,
+	// episodes for seasons, tracks for albums).
+	ChildCount int `json:"ChildCount,omitempty"`
+
+	// RecursiveItemCount is the number of playable descendants.
+	RecursiveItemCount int `json:"RecursiveItemCount,omitempty"`
+
+	// IndexNumber is the item's position (episode number, track number).
+	IndexNumber int `json:"IndexNumber,omitempty"`
+
+	// ParentIndexNumber is the parent's position (season number, or disc
+	// number for tracks).
+	ParentIndexNumber int `json:"ParentIndexNumber,omitempty"`
+
+	// ParentID is the ID of the containing folder or item.
+	ParentID string `json:"ParentId,omitempty"`
+
+	// SeriesID and SeriesName identify the series of seasons and episodes.
+	SeriesID   string `json:"SeriesId,omitempty"`
+	SeriesName string `json:"SeriesName,omitempty"`
+
+	// SeasonID and SeasonName identify the season of episodes.
+	SeasonID   string `json:"SeasonId,omitempty"`
+	SeasonName string `json:"SeasonName,omitempty"`
+
+	// AlbumID and Album identify the album of tracks.
+	AlbumID string `json:"AlbumId,omitempty"`
+	Album   string `json:"Album,omitempty"`
+
+	// AlbumArtist is the album artist of albums and tracks.
+	AlbumArtist  string   `json:"AlbumArtist,omitempty"`
+	AlbumArtists []NameID `json:"AlbumArtists,omitempty"`
+
+	// Artists are the track artists.
+	Artists []string `json:"Artists,omitempty"`
+
+	// ImageTags maps image types to the tags of the item's images.
+	ImageTags map[string]string `json:"ImageTags,omitempty"`
+
+	// BackdropImageTags are the tags of the item's backdrops.
+	BackdropImageTags []string `json:"BackdropImageTags,omitempty"`
+
+	// ParentBackdropItemID and ParentBackdropImageTags identify the
+	// backdrop inherited from an ancestor.
+	ParentBackdropItemID    string   `json:"ParentBackdropItemId,omitempty"`
+	ParentBackdropImageTags []string `json:"ParentBackdropImageTags,omitempty"`
+
+	// SeriesPrimaryImageTag is the tag of the series poster of episodes
+	// and seasons.
+	SeriesPrimaryImageTag string `json:"SeriesPrimaryImageTag,omitempty"`
+
+	// AlbumPrimaryImageTag is the tag of the album cover of tracks.
+	AlbumPrimaryImageTag string `json:"AlbumPrimaryImageTag,omitempty"`
+
+	// Studios are the production studios (record labels for albums).
+	Studios []NameID `json:"Studios,omitempty"`
+
+	// GenreItems are the genres.
+	GenreItems []NameID `json:"GenreItems,omitempty"`
+
+	// People are the cast and crew.
+	People []Person `json:"People,omitempty"`
+
+	// ProviderIDs maps external databases to the item's identifiers in
+	// them (e.g., "Imdb": "tt0133093").
+	ProviderIDs map[string]string `json:"ProviderIds,omitempty"`
+
+	// MediaSources contains the available media versions.
+	MediaSources []MediaSource `json:"MediaSources,omitempty"`
+
+	// Width and Height are the dimensions of photos.
+	Width  int `json:"Width,omitempty"`
+	Height int `json:"Height,omitempty"`
+
+	// Photo capture details from EXIF data.
+	CameraMake     string  `json:"CameraMake,omitempty"`
+	CameraModel    string  `json:"CameraModel,omitempty"`
+	Aperture       float64 `json:"Aperture,omitempty"`
+	ExposureTime   float64 `json:"ExposureTime,omitempty"`
+	IsoSpeedRating int     `json:"IsoSpeedRating,omitempty"`
+	FocalLength    float64 `json:"FocalLength,omitempty"`
+
+	// HasLyrics indicates that lyrics are available for a track.
+	HasLyrics bool `json:"HasLyrics,omitempty"`
+
+	// NormalizationGain is the track replay gain in dB.
+	NormalizationGain float64 `json:"NormalizationGain,omitempty"`
+}
+
+// Date is a timestamp. Dates without a time zone are taken as UTC.
+type Date struct {
+	time.Time
+}
+
+// UnmarshalJSON implements custom unmarshaling for Date.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s == "" {
+		d.Time = time.Time{}
+		return nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			d.Time = t
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid date %q", s)
+}
+
+// UserData is the playback state of an item for the signed-in user.
+type UserData struct {
+	// PlaybackPositionTicks is the resume position in ticks.
+	PlaybackPositionTicks int64 `json:"PlaybackPositionTicks"`
+
+	// PlayCount is the number of times the item was played.
+	PlayCount int `json:"PlayCount"`
+
+	// Played indicates the item is marked as watched.
+	Played bool `json:"Played"`
+
+	// LastPlayedDate is when the item was last played.
+	LastPlayedDate Date `json:"LastPlayedDate,omitempty"`
+
+	// UnplayedItemCount is the number of unwatched descendants of folders.
+	UnplayedItemCount int `json:"UnplayedItemCount,omitempty"`
+
+	// IsFavorite indicates the item is a favorite.
+	IsFavorite bool `json:"IsFavorite"`
+}
+
+// NameID is a named reference to another item (genre, studio, artist).
+type NameID struct {
+	Name string `json:"Name"`
+	ID   string `json:"Id"`
+}
+
+// Person is a cast or crew member.
+type Person struct {
+	ID   string `json:"Id"`
+	Name string `json:"Name"`
+
+	// Role is the character name (for actors).
+	Role string `json:"Role,omitempty"`
+
+	// Type is the credit type (see the Person* constants).
+	Type string `json:"Type"`
+
+	// PrimaryImageTag is the tag of the person's photo.
+	PrimaryImageTag string `json:"PrimaryImageTag,omitempty"`
+}
+
+// MediaSource is a media version of an item.
+type MediaSource struct {
+	ID string `json:"Id"`
+
+	// Path is the filesystem path to the file.
+	Path string `json:"Path,omitempty"`
+
+	// Container is the file container format (mkv, mp4, etc.).
+	Container string `json:"Container,omitempty"`
+
+	// Size is the file size in bytes.
+	Size int64 `json:"Size,omitempty"`
+
+	// Bitrate is the overall bitrate in bits per second.
+	Bitrate int `json:"Bitrate,omitempty"`
+
+	// RunTimeTicks is the runtime in ticks.
+	RunTimeTicks int64 `json:"RunTimeTicks,omitempty"`
+
+	// MediaStreams contains the individual streams.
+	MediaStreams []MediaStream `json:"MediaStreams,omitempty"`
+
+	// TranscodingURL is the path of the transcoded stream, set in playback
+	// info responses when the source must be transcoded.
+	TranscodingURL string `json:"TranscodingUrl,omitempty"`
+
+	// SupportsDirectPlay and SupportsTranscoding are set in playback info
+	// responses.
+	SupportsDirectPlay  bool `json:"SupportsDirectPlay,omitempty"`
+	SupportsTranscoding bool `json:"SupportsTranscoding,omitempty"`
+}
+
+// MediaStream is a video, audio, subtitle or lyrics stream.
+type MediaStream struct {
+	// Type is the stream type (see the Stream* constants).
+	Type string `json:"Type"`
+
+	// Index is the stream index within the media source.
+	Index int `json:"Index"`
+
+	Codec        string `json:"Codec,omitempty"`
+	Language     string `json:"Language,omitempty"`
+	Title        string `json:"Title,omitempty"`
+	DisplayTitle string `json:"DisplayTitle,omitempty"`
+
+	// BitRate is in bits per second.
+	BitRate int `json:"BitRate,omitempty"`
+
+	Channels   int `json:"Channels,omitempty"`
+	SampleRate int `json:"SampleRate,omitempty"`
+	BitDepth   int `json:"BitDepth,omitempty"`
+
+	Width         int     `json:"Width,omitempty"`
+	Height        int     `json:"Height,omitempty"`
+	AspectRatio   string  `json:"AspectRatio,omitempty"`
+	RealFrameRate float64 `json:"RealFrameRate,omitempty"`
+	IsDefault     bool    `json:"IsDefault,omitempty"`
+	IsForced      bool    `json:"IsForced,omitempty"`
+	IsExternal    bool    `json:"IsExternal,omitempty"`
+	DeliveryURL   string  `json:"DeliveryUrl,omitempty"`
+}
+
+// ItemsResult is a page of items.
+type ItemsResult struct {
+	Items            []Item `json:"Items"`
+	TotalRecordCount int    `json:"TotalRecordCount"`
+	StartIndex       int    `json:"StartIndex"`
+}
+
+// User is a Jellyfin user account.
+type User struct {
+	ID       string `json:"Id"`
+	Name     string `json:"Name"`
+	ServerID string `json:"ServerId,omitempty"`
+
+	// PrimaryImageTag is the tag of the user's avatar.
+	PrimaryImageTag string `json:"PrimaryImageTag,omitempty"`
+
+	Policy struct {
+		// IsAdministrator indicates the user can manage the server.
+		IsAdministrator bool `json:"IsAdministrator"`
+	} `json:"Policy"`
+}
+
+// AuthenticationResult is the response to a successful sign-in.
+type AuthenticationResult struct {
+	User        User   `json:"User"`
+	AccessToken string `json:"AccessToken"`
+	ServerID    string `json:"ServerId"`
+}
+
+// PublicSystemInfo describes a server. It is available without signing in.
+type PublicSystemInfo struct {
+	ID         string `json:"Id"`
+	ServerName string `json:"ServerName"`
+	Version    string `json:"Version"`
+
+	// ProductName is "Jellyfin Server" for Jellyfin.
+	ProductName string `json:"ProductName,omitempty"`
+
+	LocalAddress string `json:"LocalAddress,omitempty"`
+}
@@ -0,0 +1,178 @@
+package jellyfin
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// VideoStreamPath returns the direct play path of a video media source.
+func VideoStreamPath(itemID, mediaSourceID string) string {
+	return "/Videos/" + itemID + "/stream?static=true&mediaSourceId=" + url.QueryEscape(mediaSourceID)
+}
+
+// AudioStreamPath returns the direct play path of an audio media source.
+func AudioStreamPath(itemID, mediaSourceID string) string {
+	return "/Audio/" + itemID + "/stream?static=true&mediaSourceId=" + url.QueryEscape(mediaSourceID)
+}
+
+// StreamURL returns an authenticated URL for a stream path, such as a
+// direct play path or the transcoding URL of a media source.
+//
+// This URL includes authentication and can be used directly by media players.
+func (c *Client) StreamURL(path string) string {
+	return c.baseURL + appendQuery(path, url.Values{"api_key": {c.token}})
+}
+
+// DeviceProfile describes the formats the client can play. The server
+// transcodes everything else.
+type DeviceProfile struct {
+	Name                string               `json:"Name"`
+	MaxStreamingBitrate int                  `json:"MaxStreamingBitrate,omitempty"`
+	DirectPlayProfiles  []DirectPlayProfile  `json:"DirectPlayProfiles"`
+	TranscodingProfiles []TranscodingProfile `json:"TranscodingProfiles"`
+	SubtitleProfiles    []SubtitleProfile    `json:"SubtitleProfiles"`
+}
+
+// DirectPlayProfile lists containers and codecs that can be played as is.
+type DirectPlayProfile struct {
+	Type       string `json:"Type"` // "Video" or "Audio"
+	Container  string `json:"Container,omitempty"`
+	VideoCodec string `json:"VideoCodec,omitempty"`
+	AudioCodec string `json:"AudioCodec,omitempty"`
+}
+
+// TranscodingProfile is the target format of transcoded streams.
+type TranscodingProfile struct {
+	Type       string `json:"Type"` // "Video" or "Audio"
+	Container  string `json:"Container"`
+	VideoCodec string `json:"VideoCodec,omitempty"`
+	AudioCodec string `json:"AudioCodec"`
+	Protocol   string `json:"Protocol"` // "hls" or "http"
+	Context    string `json:"Context"`  // "Streaming" or "Static"
+}
+
+// SubtitleProfile tells the server how to deliver a subtitle format.
+type SubtitleProfile struct {
+	Format string `json:"Format"`
+	Method string `json:"Method"` // "Encode" to burn in, "External", "Embed"
+}
+
+// subtitleFormats are the subtitle formats Jellyfin can burn in.
+var subtitleFormats = []string{"srt", "subrip", "ass", "ssa", "vtt", "webvtt", "pgssub", "dvdsub", "dvbsub"}
+
+// NewDeviceProfile returns the profile of a player that streams HLS. With
+// burnSubtitles, selected subtitles are burned into the video.
+func NewDeviceProfile(burnSubtitles bool) *DeviceProfile {
+	profile := &DeviceProfile{
+		Name: clientName,
+		TranscodingProfiles: []TranscodingProfile{
+			{
+				Type:       "Video",
+				Container:  "ts",
+				VideoCodec: "h264,hevc",
+				AudioCodec: "aac,ac3,eac3,opus",
+				Protocol:   "hls",
+				Context:    "Streaming",
+			},
+			{
+				Type:       "Audio",
+				Container:  "mp3",
+				AudioCodec: "mp3",
+				Protocol:   "http",
+				Context:    "Streaming",
+			},
+		},
+	}
+	if burnSubtitles {
+		for _, format := range subtitleFormats {
+			profile.SubtitleProfiles = append(profile.SubtitleProfiles, SubtitleProfile{Format: format, Method: "Encode"})
+		}
+	}
+	return profile
+}
+
+// PlaybackInfoRequest asks the server how to play an item.
+type PlaybackInfoRequest struct {
+	// MediaSourceID selects the media version to play.
+	MediaSourceID string `json:"MediaSourceId,omitempty"`
+
+	// MaxStreamingBitrate is in bits per second, 0 for no limit.
+	MaxStreamingBitrate int `json:"MaxStreamingBitrate,omitempty"`
+
+	// StartTimeTicks is the start position of transcoded streams.
+	StartTimeTicks int64 `json:"StartTimeTicks,omitempty"`
+
+	// AudioStreamIndex and SubtitleStreamIndex select streams by
+	// MediaStream.Index; nil for the defaults.
+	AudioStreamIndex    *int `json:"AudioStreamIndex,omitempty"`
+	SubtitleStreamIndex *int `json:"SubtitleStreamIndex,omitempty"`
+
+	// EnableDirectPlay and EnableDirectStream allow the server to skip
+	// transcoding. Both are turned off to force a transcode.
+	EnableDirectPlay   bool `json:"EnableDirectPlay"`
+	EnableDirectStream bool `json:"EnableDirectStream"`
+	EnableTranscoding  bool `json:"EnableTranscoding"`
+
+	// DeviceProfile describes the formats the client can play.
+	DeviceProfile *DeviceProfile `json:"DeviceProfile,omitempty"`
+}
+
+// PlaybackInfo is the server's answer to a PlaybackInfoRequest.
+type PlaybackInfo struct {
+	// MediaSources lists the playable media sources. TranscodingURL is set
+	// on sources that must be transcoded.
+	MediaSources []MediaSource `json:"MediaSources"`
+
+	// PlaySessionID identifies the playback session for progress reports.
+	PlaySessionID string `json:"PlaySessionId"`
+
+	// ErrorCode explains why the item cannot be played (e.g.,
+	// "NotAllowed", "NoCompatibleStream").
+	ErrorCode string `json:"ErrorCode,omitempty"`
+}
+
+// PlaybackInfo negotiates playback of an item with the server. It also
+// opens the play session that progress reports refer to.
+func (c *Client) PlaybackInfo(ctx context.Context, itemID string, req *PlaybackInfoRequest) (*PlaybackInfo, error) {
+	body := struct {
+		UserID string `json:"UserId"`
+		*PlaybackInfoRequest
+	}{c.userID, req}
+
+	var info PlaybackInfo
+	if err := c.post(ctx, "/Items/"+itemID+"/PlaybackInfo", body).DoAndDecode(&info); err != nil {
+		return nil, err
+	}
+	if info.ErrorCode != "" {
+		return nil, &PlaybackError{Code: info.ErrorCode}
+	}
+	return &info, nil
+}
+
+// PlaybackError is returned by [Client.PlaybackInfo] when the server
+// refuses to play an item.
+type PlaybackError struct {
+	Code string
+}
+
+func (e *PlaybackError) Error() string {
+	return "playback refused: " + e.Code
+}
+
+// TranscodeURL returns an authenticated URL for the transcoded stream of a
+// media source, limited to the given dimensions (0 for no limit).
+func (c *Client) TranscodeURL(source *MediaSource, maxWidth, maxHeight int) string {
+	path := source.TranscodingURL
+	values := url.Values{}
+	if maxWidth > 0 {
+		values.Set("maxWidth", strconv.Itoa(maxWidth))
+	}
+	if maxHeight > 0 {
+		values.Set("maxHeight", strconv.Itoa(maxHeight))
+	}
+	if len(values) > 0 {
+		path = appendQuery(path, values)
+	}
+	return c.StreamURL(path)
+}
@@ -0,0 +1,39 @@
+package jellyfin
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Seasons returns the seasons of a series.
+func (c *Client) Seasons(ctx context.Context, seriesID string) ([]Item, error) {
+	query := map[string]string{
+		"userId": c.userID,
+		"fields": strings.Join(listFields, ","),
+	}
+	var resp ItemsResult
+	err := c.get(ctx, "/Shows/"+seriesID+"/Seasons", query).DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
+
+// Episodes returns the episodes of a season of a series. An empty seasonID
+// returns the episodes of all seasons.
+func (c *Client) Episodes(ctx context.Context, seriesID, seasonID string) ([]Item, error) {
+	query := map[string]string{
+		"userId": c.userID,
+		"fields": strings.Join(slices.Concat(listFields, []string{"MediaSources"}), ","),
+	}
+	if seasonID != "" {
+		query["seasonId"] = seasonID
+	}
+	var resp ItemsResult
+	err := c.get(ctx, "/Shows/"+seriesID+"/Episodes", query).DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Items, nil
+}
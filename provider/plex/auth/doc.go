@@ -32,4 +32,11 @@
 //
 //	plextv := auth.NewPlexTV(clientID)
 //	servers, err := plextv.DiscoverServers(ctx, token)
+//
+// # Plex Home
+//
+// Use [PlexTV.HomeUsers] to list the members of a Plex Home and
+// [PlexTV.SwitchHomeUser] to obtain the token of another member. Server
+// access tokens are issued per user, so servers must be rediscovered with
+// the returned token.
 package auth
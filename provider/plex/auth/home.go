@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+// ErrInvalidPIN is returned by [PlexTV.SwitchHomeUser] when the PIN of a
+// protected user is wrong or missing.
+var ErrInvalidPIN = errors.New("invalid PIN")
+
+// HomeUser represents a member of a Plex Home.
+type HomeUser struct {
+	// ID is the numeric user identifier.
+	ID int `json:"id"`
+
+	// UUID is the user's unique identifier, used to switch to the user.
+	UUID string `json:"uuid"`
+
+	// Title is the display name.
+	Title string `json:"title"`
+
+	// Username is the plex.tv username; empty for managed users.
+	Username string `json:"username,omitempty"`
+
+	// Thumb is the URL of the user's avatar.
+	Thumb string `json:"thumb,omitempty"`
+
+	// Admin indicates the Home owner.
+	Admin bool `json:"admin"`
+
+	// Guest indicates the Home guest user.
+	Guest bool `json:"guest"`
+
+	// Restricted indicates a managed user with content restrictions.
+	Restricted bool `json:"restricted"`
+
+	// Protected indicates the user requires a PIN to switch to.
+	Protected bool `json:"protected"`
+}
+
+// homeUsersResponse is the JSON envelope of /api/v2/home/users.
+type homeUsersResponse struct {
+	Users []HomeUser `json:"users"`
+}
+
+// HomeUsers lists the members of the Plex Home the token's account belongs
+// to. Accounts without a Home get only themselves.
+func (p *PlexTV) HomeUsers(ctx context.Context, token string) ([]HomeUser, error) {
+	slog.Debug("plex: fetching home users")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, plexTVBaseURL+"/api/v2/home/users", nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Plex-Token", token)
+	req.Header.Set("X-Plex-Client-Identifier", p.clientIdentifier)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get home users: %s", resp.Status)
+	}
+
+	var result homeUsersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	slog.Debug("plex: home users fetched", "user_count", len(result.Users))
+	return result.Users, nil
+}
+
+// HomeUsers is a convenience function that lists Home users using the default client.
+func HomeUsers(ctx context.Context, token, clientIdentifier string) ([]HomeUser, error) {
+	return NewPlexTV(clientIdentifier).HomeUsers(ctx, token)
+}
+
+// switchResponse is the JSON body of a successful user switch.
+type switchResponse struct {
+	AuthToken string `json:"authToken"`
+}
+
+// SwitchHomeUser switches to another member of the token's Plex Home and
+// returns that user's account token. The pin is required for protected
+// users and ignored otherwise. Server access tokens must be rediscovered
+// with the returned token, since they are issued per user.
+func (p *PlexTV) SwitchHomeUser(ctx context.Context, token, userUUID, pin string) (string, error) {
+	slog.Debug("plex: switching home user")
+	endpoint := plexTVBaseURL + "/api/v2/home/users/" + url.PathEscape(userUUID) + "/switch"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("X-Plex-Token", token)
+	req.Header.Set("X-Plex-Client-Identifier", p.clientIdentifier)
+
+	if pin != "" {
+		params := req.URL.Query()
+		params.Set("pin", pin)
+		req.URL.RawQuery = params.Encode()
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("executing request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return "", ErrInvalidPIN
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		return "", fmt.Errorf("failed to switch home user: %s", resp.Status)
+	}
+
+	var result switchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("decoding response: %w", err)
+	}
+	if result.AuthToken == "" {
+		return "", fmt.Errorf("switch response contained no token")
+	}
+	return result.AuthToken, nil
+}
+
+// SwitchHomeUser is a convenience function that switches Home users using the default client.
+func SwitchHomeUser(ctx context.Context, token, clientIdentifier, userUUID, pin string) (string, error) {
+	return NewPlexTV(clientIdentifier).SwitchHomeUser(ctx, token, userUUID, pin)
+}
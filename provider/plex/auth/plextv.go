@@ -43,9 +43,17 @@ type Resource struct {
 	// AccessToken is the access token for this resource.
 	AccessToken string `json:"accessToken,omitempty"`
 
-	// SourceTitle is the title of the source (for shared resources).
+	// SourceTitle is the title of the source (for shared resources): the
+	// username of the friend sharing the server.
 	SourceTitle string `json:"sourceTitle,omitempty"`
 
+	// OwnerID is the plex.tv user ID of the resource owner (for shared resources).
+	OwnerID int `json:"ownerId,omitempty"`
+
+	// Home indicates the resource is shared through a Plex Home rather than
+	// with a friend.
+	Home bool `json:"home"`
+
 	// HTTPSRequired indicates if HTTPS is required for connections.
 	HTTPSRequired bool `json:"httpsRequired"`
 
@@ -4,6 +4,7 @@ package base
 import (
 	"context"
 	"net/http"
+	"sync"
 
 	"github.com/0skillallluck/scanline/utils/httputils/request"
 )
@@ -13,16 +14,37 @@ type Base struct {
 	BaseURL  string
 	Token    string
 	ClientID string
+
+	// HTTPClient executes the requests; nil uses the default client.
+	HTTPClient *http.Client
+
+	// mu guards BaseURL, which can change while requests are made.
+	mu sync.RWMutex
+}
+
+// URL returns the base URL of the server.
+func (b *Base) URL() string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.BaseURL
+}
+
+// SetURL changes the base URL of the server.
+func (b *Base) SetURL(url string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.BaseURL = url
 }
 
 // Request builds a new request with Plex headers pre-configured.
 func (b *Base) Request(method, path string) *request.Request {
-	return request.NewRequest(method, b.BaseURL+path).
+	return request.NewRequest(method, b.URL()+path).
 		WithHeaders(map[string]string{
 			"X-Plex-Token":             b.Token,
 			"X-Plex-Client-Identifier": b.ClientID,
 			"Accept":                   "application/json",
 		}).
+		WithClient(b.HTTPClient).
 		WithLogging("X-Plex-Token")
 }
 
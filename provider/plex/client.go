@@ -8,8 +8,10 @@ import (
 	"net/url"
 
 	"github.com/0skillallluck/scanline/provider/plex/base"
+	"github.com/0skillallluck/scanline/provider/plex/history"
 	"github.com/0skillallluck/scanline/provider/plex/hubs"
 	"github.com/0skillallluck/scanline/provider/plex/library"
+	"github.com/0skillallluck/scanline/provider/plex/livetv"
 	"github.com/0skillallluck/scanline/provider/plex/playlists"
 	"github.com/0skillallluck/scanline/provider/plex/search"
 	"github.com/0skillallluck/scanline/provider/plex/server"
@@ -47,6 +49,12 @@ type Client struct {
 
 	// Timeline provides access to playback progress and scrobbling endpoints.
 	Timeline *timeline.Timeline
+
+	// History provides access to watch history endpoints.
+	History *history.History
+
+	// LiveTV provides access to Live TV and DVR endpoints.
+	LiveTV *livetv.LiveTV
 }
 
 // NewClient creates a new Plex Media Server client.
@@ -69,12 +77,14 @@ func NewClient(serverURL, token, clientID string) *Client {
 		Search:    search.New(b),
 		Playlists: playlists.New(b),
 		Timeline:  timeline.New(b),
+		History:   history.New(b),
+		LiveTV:    livetv.New(b),
 	}
 }
 
 // ServerURL returns the base URL of the Plex server.
 func (c *Client) ServerURL() string {
-	return c.base.BaseURL
+	return c.base.URL()
 }
 
 // Token returns the authentication token.
@@ -89,7 +99,13 @@ func (c *Client) ClientID() string {
 
 // SetServerURL updates the server URL.
 func (c *Client) SetServerURL(url string) {
-	c.base.BaseURL = url
+	c.base.SetURL(url)
+}
+
+// SetHTTPClient sets the HTTP client that executes the client's requests,
+// for example to route them through a custom transport.
+func (c *Client) SetHTTPClient(client *http.Client) {
+	c.base.HTTPClient = client
 }
 
 // SetToken updates the authentication token.
@@ -102,6 +118,10 @@ type TranscodeParams struct {
 	// RatingKey is the unique identifier for the item.
 	RatingKey string
 
+	// Path overrides the item path derived from RatingKey, e.g. to stream
+	// a live TV session.
+	Path string
+
 	// SessionID is the unique session identifier for playback tracking.
 	SessionID string
 
@@ -136,7 +156,7 @@ func (c *Client) PhotoTranscodeURL(thumb string, width, height int) string {
 	if thumb == "" {
 		return ""
 	}
-	return c.base.BaseURL + "/photo/:/transcode?width=" + fmt.Sprint(width) +
+	return c.base.URL() + "/photo/:/transcode?width=" + fmt.Sprint(width) +
 		"&height=" + fmt.Sprint(height) +
 		"&minSize=1&upscale=1&url=" + url.QueryEscape(thumb+"?X-Plex-Token="+c.base.Token) +
 		"&X-Plex-Token=" + c.base.Token
@@ -146,14 +166,18 @@ func (c *Client) PhotoTranscodeURL(thumb string, width, height int) string {
 //
 // The partKey is the key from a Part in the Media array of a Metadata item.
 func (c *Client) StreamURL(partKey string) string {
-	return c.base.BaseURL + partKey + "?X-Plex-Token=" + c.base.Token
+	return c.base.URL() + partKey + "?X-Plex-Token=" + c.base.Token
 }
 
 // BuildTranscodeQuery builds Plex universal transcode query parameters.
 func (c *Client) BuildTranscodeQuery(params TranscodeParams) url.Values {
 	q := url.Values{}
 	q.Set("hasMDE", "1")
-	q.Set("path", "/library/metadata/"+params.RatingKey)
+	if params.Path != "" {
+		q.Set("path", params.Path)
+	} else {
+		q.Set("path", "/library/metadata/"+params.RatingKey)
+	}
 	q.Set("mediaIndex", "0")
 	q.Set("partIndex", "0")
 	q.Set("protocol", "http")
@@ -198,7 +222,7 @@ func (c *Client) BuildTranscodeQuery(params TranscodeParams) url.Values {
 // The query parameters should be built using BuildTranscodeQuery.
 // This URL includes authentication and can be used directly by media players.
 func (c *Client) TranscodeStartURL(q url.Values) string {
-	return c.base.BaseURL + "/video/:/transcode/universal/start.mkv?" + q.Encode() +
+	return c.base.URL() + "/video/:/transcode/universal/start.mkv?" + q.Encode() +
 		"&X-Plex-Token=" + url.QueryEscape(c.base.Token) +
 		"&X-Plex-Client-Identifier=" + url.QueryEscape(c.base.ClientID)
 }
@@ -207,8 +231,9 @@ func (c *Client) TranscodeStartURL(q url.Values) string {
 //
 // This should be called before playback to set up the session.
 func (c *Client) MakeTranscodeDecision(ctx context.Context, q url.Values) error {
-	resp, err := request.NewRequest(http.MethodGet, c.base.BaseURL+"/video/:/transcode/universal/decision").
+	resp, err := request.NewRequest(http.MethodGet, c.base.URL()+"/video/:/transcode/universal/decision").
 		WithContext(ctx).
+		WithClient(c.base.HTTPClient).
 		WithHeaders(map[string]string{
 			"X-Plex-Token":             c.base.Token,
 			"X-Plex-Client-Identifier": c.base.ClientID,
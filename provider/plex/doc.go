@@ -9,6 +9,8 @@
 //   - [search.Search]: Search functionality
 //   - [playlists.Playlists]: Playlist management
 //   - [timeline.Timeline]: Playback progress and scrobbling
+//   - [history.History]: Watch history
+//   - [livetv.LiveTV]: Live TV, guide data and DVR recordings
 //
 // # Creating a Client
 //
@@ -0,0 +1,25 @@
+// Package gdm discovers Plex Media Servers on the local network with the
+// G'Day Mate (GDM) protocol.
+//
+// Servers listen for UDP probes on port 32414 of the multicast group
+// 239.0.0.250 and answer each "M-SEARCH * HTTP/1.1" probe with an
+// HTTP-like response describing themselves:
+//
+//	HTTP/1.0 200 OK
+//	Content-Type: plex/media-server
+//	Resource-Identifier: 0123456789abcdef
+//	Name: Living Room
+//	Port: 32400
+//	Version: 1.40.0.7998
+//
+// [Discover] sends a probe and collects the answering servers as
+// [Resource] values until the wait time has passed:
+//
+//	resources, err := gdm.Discover(ctx, gdm.DefaultWait)
+//	for _, r := range resources {
+//	    fmt.Println(r.Name, r.URI())
+//	}
+//
+// [DiscoverAt] probes a specific address instead of the multicast group,
+// for example a single host or a stand-in listener in tests.
+package gdm
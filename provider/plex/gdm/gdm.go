@@ -0,0 +1,169 @@
+package gdm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MulticastAddress is the address Plex Media Servers listen on for
+	// GDM probes.
+	MulticastAddress = "239.0.0.250:32414"
+
+	// DefaultWait is how long Discover collects responses by default.
+	DefaultWait = time.Second
+
+	// ContentTypeServer is the content type announced by Plex Media
+	// Servers. Players answer GDM probes on other ports with other types.
+	ContentTypeServer = "plex/media-server"
+)
+
+// probe is the GDM search request.
+var probe = []byte("M-SEARCH * HTTP/1.1\r\n\r\n")
+
+// Resource is a Plex Media Server that answered a GDM probe.
+type Resource struct {
+	// Name is the user-configured name of the server.
+	Name string
+
+	// ResourceIdentifier is the machine identifier of the server, which
+	// matches the client identifier of its plex.tv resource.
+	ResourceIdentifier string
+
+	// ContentType is the type of the resource ("plex/media-server").
+	ContentType string
+
+	// Host is the IP address the response came from.
+	Host string
+
+	// Port is the port the server's HTTP API listens on.
+	Port int
+
+	// Version is the Plex Media Server version.
+	Version string
+}
+
+// Address returns the host and port of the server's HTTP API.
+func (r *Resource) Address() string {
+	return net.JoinHostPort(r.Host, strconv.Itoa(r.Port))
+}
+
+// URI returns the HTTP URL of the server.
+func (r *Resource) URI() string {
+	return "http://" + r.Address()
+}
+
+// Discover probes the GDM multicast group and returns the servers that
+// answer within wait.
+func Discover(ctx context.Context, wait time.Duration) ([]Resource, error) {
+	return DiscoverAt(ctx, MulticastAddress, wait)
+}
+
+// DiscoverAt probes address and returns the servers that answer within
+// wait. Servers answering more than once, for example on several network
+// interfaces, are returned once.
+func DiscoverAt(ctx context.Context, address string, wait time.Duration) ([]Resource, error) {
+	raddr, err := net.ResolveUDPAddr("udp4", address)
+	if err != nil {
+		return nil, fmt.Errorf("resolving GDM address: %w", err)
+	}
+	conn, err := net.ListenUDP("udp4", nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening GDM socket: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(wait)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	// Unblock the read when ctx is canceled before the deadline.
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err := conn.WriteToUDP(probe, raddr); err != nil {
+		return nil, fmt.Errorf("sending GDM probe: %w", err)
+	}
+
+	var resources []Resource
+	seen := make(map[string]bool)
+	buf := make([]byte, 4096)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				break
+			}
+			return nil, fmt.Errorf("reading GDM response: %w", err)
+		}
+
+		r, err := parseResponse(buf[:n], from.IP.String())
+		if err != nil {
+			slog.Debug("gdm: ignoring response", "from", from, "error", err)
+			continue
+		}
+		if r.ContentType != ContentTypeServer || seen[r.ResourceIdentifier] {
+			continue
+		}
+		seen[r.ResourceIdentifier] = true
+		resources = append(resources, *r)
+	}
+
+	slog.Debug("gdm: discovery finished", "address", address, "server_count", len(resources))
+	return resources, nil
+}
+
+// parseResponse parses a GDM response received from host.
+func parseResponse(data []byte, host string) (*Resource, error) {
+	lines := strings.Split(string(bytes.TrimSpace(data)), "\n")
+	status := strings.Fields(lines[0])
+	if len(status) < 2 || !strings.HasPrefix(status[0], "HTTP/") {
+		return nil, fmt.Errorf("malformed status line %q", lines[0])
+	}
+	if status[1] != "200" {
+		return nil, fmt.Errorf("unexpected status %s", status[1])
+	}
+
+	headers := make(textproto.MIMEHeader)
+	for _, line := range lines[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		headers.Set(strings.TrimSpace(key), strings.TrimSpace(value))
+	}
+
+	r := &Resource{
+		Name:               headers.Get("Name"),
+		ResourceIdentifier: headers.Get("Resource-Identifier"),
+		ContentType:        headers.Get("Content-Type"),
+		Host:               host,
+		Version:            headers.Get("Version"),
+	}
+	if r.ResourceIdentifier == "" {
+		return nil, errors.New("missing resource identifier")
+	}
+	port, err := strconv.Atoi(headers.Get("Port"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", headers.Get("Port"))
+	}
+	r.Port = port
+	return r, nil
+}
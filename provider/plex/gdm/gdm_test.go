@@ -0,0 +1,140 @@
+package gdm
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+const serverResponse = "HTTP/1.0 200 OK\r\n" +
+	"Content-Type: plex/media-server\r\n" +
+	"Resource-Identifier: 0123456789abcdef\r\n" +
+	"Name: Living Room\r\n" +
+	"Port: 32400\r\n" +
+	"Updated-At: 1700000000\r\n" +
+	"Version: 1.40.0.7998\r\n\r\n"
+
+const playerResponse = "HTTP/1.0 200 OK\r\n" +
+	"Content-Type: plex/media-player\r\n" +
+	"Resource-Identifier: player\r\n" +
+	"Name: Television\r\n" +
+	"Port: 32500\r\n\r\n"
+
+// newResponder starts a loopback UDP listener standing in for servers on
+// the network. It answers every probe with responses and records the
+// probes it received.
+func newResponder(t *testing.T, responses ...string) (string, <-chan string) {
+	t.Helper()
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	probes := make(chan string, 1)
+	go func() {
+		buf := make([]byte, 1024)
+		for {
+			n, from, err := conn.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			select {
+			case probes <- string(buf[:n]):
+			default:
+			}
+			for _, resp := range responses {
+				conn.WriteToUDP([]byte(resp), from)
+			}
+		}
+	}()
+	return conn.LocalAddr().String(), probes
+}
+
+func TestDiscoverAt(t *testing.T) {
+	// The server answers twice, as it would on two interfaces.
+	addr, probes := newResponder(t, serverResponse, playerResponse, "garbage", serverResponse)
+
+	resources, err := DiscoverAt(context.Background(), addr, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("DiscoverAt: %v", err)
+	}
+
+	if probe := <-probes; probe != "M-SEARCH * HTTP/1.1\r\n\r\n" {
+		t.Errorf("probe = %q", probe)
+	}
+	if len(resources) != 1 {
+		t.Fatalf("got %d resources, want 1: %+v", len(resources), resources)
+	}
+	r := resources[0]
+	if r.Name != "Living Room" || r.ResourceIdentifier != "0123456789abcdef" || r.Version != "1.40.0.7998" {
+		t.Errorf("resource = %+v", r)
+	}
+	if r.Host != "127.0.0.1" || r.Port != 32400 {
+		t.Errorf("host, port = %s, %d", r.Host, r.Port)
+	}
+	if got, want := r.URI(), "http://127.0.0.1:32400"; got != want {
+		t.Errorf("URI = %q, want %q", got, want)
+	}
+}
+
+func TestDiscoverAtNoServers(t *testing.T) {
+	addr, _ := newResponder(t)
+
+	resources, err := DiscoverAt(context.Background(), addr, 100*time.Millisecond)
+	if err != nil {
+		t.Fatalf("DiscoverAt: %v", err)
+	}
+	if len(resources) != 0 {
+		t.Errorf("got %d resources, want none", len(resources))
+	}
+}
+
+func TestDiscoverAtCanceled(t *testing.T) {
+	addr, _ := newResponder(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := DiscoverAt(ctx, addr, 10*time.Second)
+	if err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("discovery kept waiting for %v after cancel", elapsed)
+	}
+}
+
+func TestParseResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "server", data: serverResponse},
+		{name: "bare newlines", data: "HTTP/1.0 200 OK\nResource-Identifier: abc\nPort: 32400\n"},
+		{name: "not http", data: "hello", wantErr: true},
+		{name: "error status", data: "HTTP/1.0 404 Not Found\r\n\r\n", wantErr: true},
+		{name: "missing identifier", data: "HTTP/1.0 200 OK\r\nPort: 32400\r\n", wantErr: true},
+		{name: "invalid port", data: "HTTP/1.0 200 OK\r\nResource-Identifier: abc\r\nPort: x\r\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := parseResponse([]byte(tt.data), "192.168.1.2")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseResponse = %+v, want error", r)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseResponse: %v", err)
+			}
+			if r.Port != 32400 || r.Host != "192.168.1.2" {
+				t.Errorf("resource = %+v", r)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Package history provides access to the Plex watch history endpoints.
+package history
+
+import "github.com/0skillallluck/scanline/provider/plex/base"
+
+// History provides access to watch history endpoints.
+type History struct {
+	*base.Base
+}
+
+// New creates a new History service.
+func New(b *base.Base) *History {
+	return &History{Base: b}
+}
+
+// Options specifies pagination and filtering for history queries.
+type Options struct {
+	// Start is the starting offset for pagination.
+	Start int
+
+	// Size is the maximum number of entries to return.
+	Size int
+
+	// AccountID filters by the server-local account ID (0 for all accounts).
+	AccountID int
+
+	// SectionID filters by library section ID.
+	SectionID string
+
+	// ViewedAfter filters to entries viewed at or after this Unix timestamp.
+	ViewedAfter int64
+
+	// ViewedBefore filters to entries viewed at or before this Unix timestamp.
+	ViewedBefore int64
+}
+
+// Entry represents a single watch history record.
+type Entry struct {
+	// HistoryKey is the API path identifying this history record.
+	HistoryKey string `json:"historyKey"`
+
+	// Key is the API path of the watched item.
+	Key string `json:"key"`
+
+	// RatingKey is the unique identifier of the watched item.
+	// Empty if the item has since been removed from the library.
+	RatingKey string `json:"ratingKey,omitempty"`
+
+	// LibrarySectionID is the library section the item belongs to.
+	LibrarySectionID string `json:"librarySectionID,omitempty"`
+
+	// ParentKey is the API path of the parent item (season for episodes).
+	ParentKey string `json:"parentKey,omitempty"`
+
+	// GrandparentKey is the API path of the grandparent item (show for episodes).
+	GrandparentKey string `json:"grandparentKey,omitempty"`
+
+	// Type is the item type (movie, episode, track).
+	Type string `json:"type"`
+
+	// Title is the display title of the watched item.
+	Title string `json:"title"`
+
+	// ParentTitle is the title of the parent item.
+	ParentTitle string `json:"parentTitle,omitempty"`
+
+	// GrandparentTitle is the title of the grandparent item (show title for episodes).
+	GrandparentTitle string `json:"grandparentTitle,omitempty"`
+
+	// Thumb is the URL path to the item thumbnail.
+	Thumb string `json:"thumb,omitempty"`
+
+	// ParentThumb is the URL path to the parent's thumbnail.
+	ParentThumb string `json:"parentThumb,omitempty"`
+
+	// GrandparentThumb is the URL path to the grandparent's thumbnail.
+	GrandparentThumb string `json:"grandparentThumb,omitempty"`
+
+	// Index is the item's position (episode number, track number).
+	Index int `json:"index,omitempty"`
+
+	// ParentIndex is the parent's position (season number).
+	ParentIndex int `json:"parentIndex,omitempty"`
+
+	// OriginallyAvailableAt is the original release date (YYYY-MM-DD).
+	OriginallyAvailableAt string `json:"originallyAvailableAt,omitempty"`
+
+	// ViewedAt is the Unix timestamp when the item was watched.
+	ViewedAt int64 `json:"viewedAt"`
+
+	// AccountID is the server-local account that watched the item.
+	AccountID int `json:"accountID"`
+
+	// DeviceID is the server-local device the item was watched on.
+	DeviceID int `json:"deviceID,omitempty"`
+}
+
+// Container types for JSON unmarshaling.
+
+type historyContainer struct {
+	Size      int     `json:"size"`
+	TotalSize int     `json:"totalSize,omitempty"`
+	Offset    int     `json:"offset,omitempty"`
+	Metadata  []Entry `json:"Metadata"`
+}
+
+type mediaContainerResponse[T any] struct {
+	MediaContainer T `json:"MediaContainer"`
+}
@@ -0,0 +1,50 @@
+package history
+
+import (
+	"context"
+	"strconv"
+)
+
+// List returns watch history entries, most recent first.
+//
+// Returns the entries and the total count available (for pagination).
+func (h *History) List(ctx context.Context, opts *Options) ([]Entry, int, error) {
+	query := map[string]string{"sort": "viewedAt:desc"}
+	if opts != nil {
+		if opts.Start > 0 {
+			query["X-Plex-Container-Start"] = strconv.Itoa(opts.Start)
+		}
+		if opts.Size > 0 {
+			query["X-Plex-Container-Size"] = strconv.Itoa(opts.Size)
+		}
+		if opts.AccountID > 0 {
+			query["accountID"] = strconv.Itoa(opts.AccountID)
+		}
+		if opts.SectionID != "" {
+			query["librarySectionID"] = opts.SectionID
+		}
+		// The "=" separating the key from the value completes the operator:
+		// the keys "viewedAt>" and "viewedAt<" form the inclusive filters
+		// "viewedAt>=" and "viewedAt<=". Exclusive bounds would use the keys
+		// "viewedAt>>" and "viewedAt<<".
+		if opts.ViewedAfter > 0 {
+			query["viewedAt>"] = strconv.FormatInt(opts.ViewedAfter, 10)
+		}
+		if opts.ViewedBefore > 0 {
+			query["viewedAt<"] = strconv.FormatInt(opts.ViewedBefore, 10)
+		}
+	}
+
+	var resp mediaContainerResponse[historyContainer]
+	err := h.GetWithQuery(ctx, "/status/sessions/history/all", query).
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total := resp.MediaContainer.TotalSize
+	if total == 0 {
+		total = resp.MediaContainer.Size
+	}
+	return resp.MediaContainer.Metadata, total, nil
+}
@@ -0,0 +1,50 @@
+package history
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/0skillallluck/scanline/provider/plex/base"
+)
+
+func TestList_FiltersAreInclusive(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Plex splits "viewedAt>=100" into the key "viewedAt>" and the
+		// value "100", so the encoded key must not carry its own "=".
+		for _, want := range []string{"viewedAt%3E=100", "viewedAt%3C=200"} {
+			if !strings.Contains(r.URL.RawQuery, want) {
+				t.Errorf("query = %s, want %s", r.URL.RawQuery, want)
+			}
+		}
+		if r.URL.Query().Get("accountID") != "3" {
+			t.Errorf("accountID = %s, want 3", r.URL.Query().Get("accountID"))
+		}
+		if r.URL.Query().Get("X-Plex-Container-Start") != "50" {
+			t.Errorf("X-Plex-Container-Start = %s, want 50", r.URL.Query().Get("X-Plex-Container-Start"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"MediaContainer":{"size":1,"totalSize":51,"Metadata":[{"title":"Heat","type":"movie","viewedAt":150,"accountID":3}]}}`))
+	}))
+	defer server.Close()
+
+	h := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	entries, total, err := h.List(context.Background(), &Options{
+		Start:        50,
+		Size:         50,
+		AccountID:    3,
+		ViewedAfter:  100,
+		ViewedBefore: 200,
+	})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if total != 51 {
+		t.Errorf("total = %d, want 51", total)
+	}
+	if len(entries) != 1 || entries[0].Title != "Heat" || entries[0].AccountID != 3 {
+		t.Errorf("entries = %+v", entries)
+	}
+}
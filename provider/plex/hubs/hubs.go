@@ -58,6 +58,13 @@ type hubsContainer struct {
 	Hub []Hub `json:"Hub"`
 }
 
+type hubItemsContainer struct {
+	Size      int                `json:"size"`
+	TotalSize int                `json:"totalSize"`
+	Metadata  []library.Metadata `json:"Metadata"`
+	Hub       []Hub              `json:"Hub"`
+}
+
 type mediaContainerResponse[T any] struct {
 	MediaContainer T `json:"MediaContainer"`
 }
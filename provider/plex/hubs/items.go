@@ -0,0 +1,47 @@
+package hubs
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/0skillallluck/scanline/provider/plex/library"
+)
+
+// Items returns a page of items from a hub, using the hub's Key.
+//
+// Returns the items and the total count available (for pagination). When
+// the server does not report the total, it is estimated from the page.
+func (h *Hubs) Items(ctx context.Context, hubKey string, start, size int) ([]library.Metadata, int, error) {
+	query := map[string]string{
+		"X-Plex-Container-Start": strconv.Itoa(start),
+	}
+	if size > 0 {
+		query["X-Plex-Container-Size"] = strconv.Itoa(size)
+	}
+
+	var resp mediaContainerResponse[hubItemsContainer]
+	err := h.GetWithQuery(ctx, hubKey, query).
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	mc := resp.MediaContainer
+	items := mc.Metadata
+	// Some hub keys point at hub endpoints that wrap the items in a single hub.
+	if len(items) == 0 && len(mc.Hub) > 0 {
+		items = mc.Hub[0].Metadata
+	}
+
+	// Without a total size, a full page may be followed by more items: one
+	// more is counted so that the next page is requested, until a short or
+	// empty page ends the hub.
+	total := mc.TotalSize
+	if total == 0 {
+		total = start + len(items)
+		if size > 0 && len(items) >= size {
+			total++
+		}
+	}
+	return items, total, nil
+}
@@ -38,6 +38,12 @@ func (l *Library) Content(ctx context.Context, sectionID string, opts *ContentOp
 		if opts.GUID != "" {
 			query["guid"] = opts.GUID
 		}
+		if opts.UpdatedSince > 0 {
+			query["updatedAt>"] = strconv.FormatInt(opts.UpdatedSince, 10)
+		}
+		if opts.IncludeGUIDs {
+			query["includeGuids"] = "1"
+		}
 	}
 
 	var resp mediaContainerResponse[metadataContainer]
@@ -45,6 +45,14 @@ type ContentOptions struct {
 
 	// GUID filters by Plex GUID (e.g., "plex://movie/5d776...").
 	GUID string
+
+	// UpdatedSince limits results to items updated at or after this Unix
+	// timestamp.
+	UpdatedSince int64
+
+	// IncludeGUIDs adds the external identifiers (IMDb, TMDB, TVDB) of each
+	// item to the results.
+	IncludeGUIDs bool
 }
 
 // LibrarySection represents a library section (e.g., Movies, TV Shows).
@@ -135,6 +143,13 @@ type Metadata struct {
 	// Key is the API path to get full details.
 	Key string `json:"key"`
 
+	// LibrarySectionID is the library section the item belongs to.
+	LibrarySectionID int `json:"librarySectionID,omitempty"`
+
+	// GUID is the agent identifier (e.g., "plex://movie/5d776825880197001ec967c9").
+	// Items matched by the Plex agents share it with the online metadata.
+	GUID string `json:"guid,omitempty"`
+
 	// Type is the item type (movie, show, season, episode, artist, album, track).
 	Type string `json:"type"`
 
@@ -145,6 +160,7 @@ type Metadata struct {
 	TitleSort string `json:"titleSort,omitempty"`
 
 	// OriginalTitle is the original title (for foreign content).
+	// For tracks it holds the track artist credit when it differs from the album artist.
 	OriginalTitle string `json:"originalTitle,omitempty"`
 
 	// Summary is the plot summary or description.
@@ -168,6 +184,9 @@ type Metadata struct {
 	// ViewCount is the number of times this item has been watched.
 	ViewCount int `json:"viewCount,omitempty"`
 
+	// LastViewedAt is the Unix timestamp when the item was last watched.
+	LastViewedAt int64 `json:"lastViewedAt,omitempty"`
+
 	// ChildCount is the number of direct children (seasons for shows, episodes for seasons).
 	ChildCount int `json:"childCount,omitempty"`
 
@@ -186,7 +205,7 @@ type Metadata struct {
 	// Index is the item's position (episode number, track number).
 	Index int `json:"index,omitempty"`
 
-	// ParentIndex is the parent's position (season number).
+	// ParentIndex is the parent's position (season number, or disc number for tracks).
 	ParentIndex int `json:"parentIndex,omitempty"`
 
 	// ParentRatingKey is the rating key of the parent item.
@@ -195,6 +214,9 @@ type Metadata struct {
 	// ParentTitle is the title of the parent item.
 	ParentTitle string `json:"parentTitle,omitempty"`
 
+	// ParentYear is the release year of the parent item (album year for tracks).
+	ParentYear int `json:"parentYear,omitempty"`
+
 	// ParentThumb is the URL path to the parent's thumbnail.
 	ParentThumb string `json:"parentThumb,omitempty"`
 
@@ -228,7 +250,7 @@ type Metadata struct {
 	// AudienceRatingImage is the URL path to the audience rating source logo (legacy field).
 	AudienceRatingImage string `json:"audienceRatingImage,omitempty"`
 
-	// Studio is the production studio.
+	// Studio is the production studio (record label for albums).
 	Studio string `json:"studio,omitempty"`
 
 	// OriginallyAvailableAt is the original release date (YYYY-MM-DD).
@@ -251,6 +273,16 @@ type Metadata struct {
 
 	// Marker contains chapter markers (credits, intros, etc.).
 	Marker []Marker `json:"Marker,omitempty"`
+
+	// ExternalGUIDs contains the external identifiers of the item. Only
+	// returned when requested (see [ContentOptions.IncludeGUIDs]).
+	ExternalGUIDs []ExternalGUID `json:"Guid,omitempty"`
+}
+
+// ExternalGUID is an identifier of an item in an external database.
+type ExternalGUID struct {
+	// ID is the identifier URI (e.g., "imdb://tt0133093", "tmdb://603").
+	ID string `json:"id"`
 }
 
 // Marker represents a chapter marker (credits, intro, etc.) for a media item.
@@ -312,6 +344,24 @@ type Media struct {
 	// VideoProfile is the video codec profile (main, high, etc.).
 	VideoProfile string `json:"videoProfile,omitempty"`
 
+	// Aperture is the photo aperture from EXIF data (e.g., "f/2.8").
+	Aperture string `json:"aperture,omitempty"`
+
+	// Exposure is the photo exposure time from EXIF data (e.g., "1/250s").
+	Exposure string `json:"exposure,omitempty"`
+
+	// ISO is the photo ISO sensitivity from EXIF data.
+	ISO int `json:"iso,omitempty"`
+
+	// Lens is the lens model from EXIF data.
+	Lens string `json:"lens,omitempty"`
+
+	// Make is the camera manufacturer from EXIF data.
+	Make string `json:"make,omitempty"`
+
+	// Model is the camera model from EXIF data.
+	Model string `json:"model,omitempty"`
+
 	// Part contains the media file parts.
 	Part []Part `json:"Part,omitempty"`
 }
@@ -348,7 +398,7 @@ type Stream struct {
 	// ID is the unique identifier for this stream.
 	ID int `json:"id"`
 
-	// StreamType indicates the stream type (1=video, 2=audio, 3=subtitle).
+	// StreamType indicates the stream type (1=video, 2=audio, 3=subtitle, 4=lyrics).
 	StreamType int `json:"streamType"`
 
 	// Codec is the codec name.
@@ -404,6 +454,56 @@ type Stream struct {
 
 	// Forced indicates if this is a forced subtitle stream.
 	Forced bool `json:"forced,omitempty"`
+
+	// Gain is the track replay gain in dB, from loudness analysis.
+	Gain Loudness `json:"gain,omitempty"`
+
+	// AlbumGain is the album replay gain in dB, from loudness analysis.
+	AlbumGain Loudness `json:"albumGain,omitempty"`
+
+	// Peak is the track peak amplitude (1.0 = full scale).
+	Peak Loudness `json:"peak,omitempty"`
+
+	// AlbumPeak is the album peak amplitude (1.0 = full scale).
+	AlbumPeak Loudness `json:"albumPeak,omitempty"`
+
+	// Loudness is the integrated loudness in LUFS.
+	Loudness Loudness `json:"loudness,omitempty"`
+
+	// Key is the API path to fetch the stream content (lyrics and sidecar subtitles).
+	Key string `json:"key,omitempty"`
+
+	// Format is the content format of lyrics and subtitle streams (e.g., "lrc", "txt", "srt").
+	Format string `json:"format,omitempty"`
+
+	// Provider is the agent that supplied the stream (e.g., for downloaded lyrics).
+	Provider string `json:"provider,omitempty"`
+}
+
+// Loudness is a loudness analysis value.
+type Loudness float64
+
+// UnmarshalJSON implements custom unmarshaling for Loudness.
+// Plex encodes loudness values as decimal strings.
+func (l *Loudness) UnmarshalJSON(data []byte) error {
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		num = json.Number(str)
+	}
+	if num == "" {
+		*l = 0
+		return nil
+	}
+	f, err := num.Float64()
+	if err != nil {
+		return err
+	}
+	*l = Loudness(f)
+	return nil
 }
 
 // Tag represents a metadata tag (genre, director, actor, etc.).
@@ -445,6 +545,20 @@ type markerContainer struct {
 	Marker []Marker `json:"Marker"`
 }
 
+type lyricsContainer struct {
+	mediaContainer
+	Lyrics []struct {
+		Timed    string `json:"timed"`
+		Provider string `json:"provider"`
+		Line     []struct {
+			StartOffset int `json:"startOffset"`
+			Span        []struct {
+				Text string `json:"text"`
+			} `json:"Span"`
+		} `json:"Line"`
+	} `json:"Lyrics"`
+}
+
 type mediaContainerResponse[T any] struct {
 	MediaContainer T `json:"MediaContainer"`
 }
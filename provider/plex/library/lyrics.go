@@ -0,0 +1,94 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Lyrics is the content of a lyrics stream.
+type Lyrics struct {
+	// Format is the lyrics format ("lrc" for synced lyrics, "txt" for plain text).
+	Format string
+
+	// Text is the lyrics text in the given format.
+	Text string
+}
+
+// LyricsStream returns the preferred lyrics stream of the part, favoring
+// synced (LRC) lyrics over plain text. It returns nil when there is none.
+func (p *Part) LyricsStream() *Stream {
+	var found *Stream
+	for i := range p.Stream {
+		s := &p.Stream[i]
+		if s.StreamType != 4 || s.Key == "" {
+			continue
+		}
+		if strings.EqualFold(s.Format, "lrc") {
+			return s
+		}
+		if found == nil {
+			found = s
+		}
+	}
+	return found
+}
+
+// Lyrics retrieves the content of a lyrics stream.
+//
+// The stream parameter is a lyrics stream, as returned by Part.LyricsStream.
+// Sidecar and embedded lyrics are returned as-is. Lyrics supplied by an
+// online provider come back as timed lines, which are converted to LRC.
+func (l *Library) Lyrics(ctx context.Context, stream *Stream) (*Lyrics, error) {
+	resp, err := l.Get(ctx, stream.Key).Do()
+	if err != nil {
+		return nil, err
+	}
+	if err := resp.CheckStatus(); err != nil {
+		return nil, err
+	}
+
+	format := strings.ToLower(stream.Format)
+	body := bytes.TrimSpace(resp.Bytes())
+	if !bytes.HasPrefix(body, []byte("{")) {
+		return &Lyrics{Format: format, Text: string(body)}, nil
+	}
+
+	var container mediaContainerResponse[lyricsContainer]
+	if err := json.Unmarshal(body, &container); err != nil {
+		return nil, err
+	}
+	return container.MediaContainer.toLyrics(), nil
+}
+
+// toLyrics flattens provider lyrics into a single LRC or text document.
+func (c lyricsContainer) toLyrics() *Lyrics {
+	lyrics := &Lyrics{Format: "txt"}
+	if len(c.Lyrics) == 0 {
+		return lyrics
+	}
+
+	doc := c.Lyrics[0]
+	timed := doc.Timed == "1" || doc.Timed == "true"
+	if timed {
+		lyrics.Format = "lrc"
+	}
+
+	var b strings.Builder
+	for _, line := range doc.Line {
+		var text strings.Builder
+		for _, span := range line.Span {
+			text.WriteString(span.Text)
+		}
+		if timed {
+			ms := line.StartOffset
+			fmt.Fprintf(&b, "[%02d:%02d.%02d]", ms/60000, (ms/1000)%60, (ms%1000)/10)
+		}
+		b.WriteString(text.String())
+		b.WriteByte('\n')
+	}
+	lyrics.Text = b.String()
+	return lyrics
+}
@@ -0,0 +1,25 @@
+package livetv
+
+import "context"
+
+// DVRs returns the DVRs configured on the server.
+func (l *LiveTV) DVRs(ctx context.Context) ([]DVR, error) {
+	var resp mediaContainerResponse[dvrsContainer]
+	err := l.Get(ctx, "/livetv/dvrs").
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MediaContainer.Dvr, nil
+}
+
+// Channels returns the channels in a DVR's guide lineup.
+func (l *LiveTV) Channels(ctx context.Context, dvr *DVR) ([]Channel, error) {
+	var resp mediaContainerResponse[channelsContainer]
+	err := l.GetWithQuery(ctx, "/livetv/epg/channels", map[string]string{"lineup": dvr.Lineup}).
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MediaContainer.Channel, nil
+}
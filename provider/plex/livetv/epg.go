@@ -0,0 +1,40 @@
+package livetv
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// Grid returns the guide entries airing between start and end.
+//
+// Each program's Media lists its airings; a program airing on several
+// channels or at several times in the window appears once.
+func (l *LiveTV) Grid(ctx context.Context, dvr *DVR, start, end time.Time) ([]Program, error) {
+	// Plex reads "beginsAt<=" and "endsAt>=" as inclusive range filters.
+	query := map[string]string{
+		"type":      "1,4", // movies and episodes
+		"lineup":    dvr.Lineup,
+		"beginsAt<": strconv.FormatInt(end.Unix(), 10),
+		"endsAt>":   strconv.FormatInt(start.Unix(), 10),
+	}
+
+	var resp mediaContainerResponse[programsContainer]
+	err := l.GetWithQuery(ctx, "/livetv/epg/grid", query).
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MediaContainer.Metadata, nil
+}
+
+// Hubs returns the guide hubs, such as what is on now and upcoming movies.
+func (l *LiveTV) Hubs(ctx context.Context) ([]Hub, error) {
+	var resp mediaContainerResponse[hubsContainer]
+	err := l.Get(ctx, "/livetv/epg/hubs").
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MediaContainer.Hub, nil
+}
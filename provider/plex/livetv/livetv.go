@@ -0,0 +1,323 @@
+// Package livetv provides access to the Plex Live TV and DVR endpoints.
+package livetv
+
+import (
+	"fmt"
+
+	"github.com/0skillallluck/scanline/provider/plex/base"
+)
+
+// LiveTV provides access to Live TV and DVR endpoints.
+type LiveTV struct {
+	*base.Base
+}
+
+// New creates a new LiveTV service.
+func New(b *base.Base) *LiveTV {
+	return &LiveTV{Base: b}
+}
+
+// DVR represents a configured DVR, combining tuner devices with a guide lineup.
+type DVR struct {
+	// Key is the DVR identifier used in /livetv/dvrs paths.
+	Key string `json:"key"`
+
+	// UUID is the globally unique DVR identifier.
+	UUID string `json:"uuid"`
+
+	// Language is the guide language (e.g., "eng").
+	Language string `json:"language,omitempty"`
+
+	// Lineup is the guide lineup URI the DVR's channels come from.
+	Lineup string `json:"lineup,omitempty"`
+
+	// LineupTitle is the display name of the guide lineup.
+	LineupTitle string `json:"lineupTitle,omitempty"`
+
+	// EPGIdentifier is the guide provider identifier.
+	EPGIdentifier string `json:"epgIdentifier,omitempty"`
+
+	// Device lists the tuner devices attached to the DVR.
+	Device []Device `json:"Device,omitempty"`
+}
+
+// Device represents a tuner device such as an HDHomeRun.
+type Device struct {
+	// Key is the device identifier.
+	Key string `json:"key"`
+
+	// UUID is the grabber device URI (e.g., "device://tv.plex.grabbers.hdhomerun/1053C0CA").
+	UUID string `json:"uuid"`
+
+	// Title is the device display name.
+	Title string `json:"title,omitempty"`
+
+	// Make is the device manufacturer.
+	Make string `json:"make,omitempty"`
+
+	// Model is the device model.
+	Model string `json:"model,omitempty"`
+
+	// URI is the device's network address.
+	URI string `json:"uri,omitempty"`
+
+	// Status is the device state reported by the server ("alive", "dead").
+	Status string `json:"status,omitempty"`
+
+	// Tuners is the number of tuners, i.e. how many channels can be
+	// watched or recorded at once.
+	Tuners int `json:"tuners,omitempty"`
+}
+
+// Channel represents a channel in a guide lineup.
+type Channel struct {
+	// Key is the channel's guide identifier.
+	Key string `json:"key"`
+
+	// Identifier is the channel number used for tuning (e.g., "5.1").
+	Identifier string `json:"identifier"`
+
+	// CallSign is the station call sign (e.g., "WABC").
+	CallSign string `json:"callSign,omitempty"`
+
+	// Title is the channel display name.
+	Title string `json:"title,omitempty"`
+
+	// Thumb is the URL path to the channel logo.
+	Thumb string `json:"thumb,omitempty"`
+
+	// HD indicates the channel broadcasts in high definition.
+	HD bool `json:"hd,omitempty"`
+}
+
+// Program represents a guide entry: a movie or episode together with the
+// airings it has on one or more channels.
+type Program struct {
+	// RatingKey is the guide identifier for this program.
+	RatingKey string `json:"ratingKey"`
+
+	// Key is the API path to the program's metadata.
+	Key string `json:"key"`
+
+	// GUID is the global identifier (e.g., "plex://episode/...").
+	GUID string `json:"guid,omitempty"`
+
+	// Type is the program type (movie, episode).
+	Type string `json:"type"`
+
+	// Title is the program title (episode title for episodes).
+	Title string `json:"title"`
+
+	// GrandparentTitle is the show title for episodes.
+	GrandparentTitle string `json:"grandparentTitle,omitempty"`
+
+	// Summary is the program description.
+	Summary string `json:"summary,omitempty"`
+
+	// Thumb is the URL path to the program artwork.
+	Thumb string `json:"thumb,omitempty"`
+
+	// GrandparentThumb is the URL path to the show artwork for episodes.
+	GrandparentThumb string `json:"grandparentThumb,omitempty"`
+
+	// Year is the release year.
+	Year int `json:"year,omitempty"`
+
+	// Index is the episode number.
+	Index int `json:"index,omitempty"`
+
+	// ParentIndex is the season number.
+	ParentIndex int `json:"parentIndex,omitempty"`
+
+	// Duration is the runtime in milliseconds.
+	Duration int `json:"duration,omitempty"`
+
+	// Media lists the program's airings.
+	Media []Airing `json:"Media,omitempty"`
+}
+
+// DisplayTitle returns the show title for episodes and the title otherwise.
+func (p *Program) DisplayTitle() string {
+	if p.Type == "episode" && p.GrandparentTitle != "" {
+		return p.GrandparentTitle
+	}
+	return p.Title
+}
+
+// Airing is a single broadcast of a program on a channel.
+type Airing struct {
+	// BeginsAt is the Unix timestamp when the airing starts.
+	BeginsAt int64 `json:"beginsAt"`
+
+	// EndsAt is the Unix timestamp when the airing ends.
+	EndsAt int64 `json:"endsAt"`
+
+	// ChannelIdentifier is the channel number (e.g., "5.1").
+	ChannelIdentifier string `json:"channelIdentifier"`
+
+	// ChannelCallSign is the station call sign.
+	ChannelCallSign string `json:"channelCallSign,omitempty"`
+
+	// ChannelTitle is the channel display name.
+	ChannelTitle string `json:"channelTitle,omitempty"`
+
+	// ChannelThumb is the URL path to the channel logo.
+	ChannelThumb string `json:"channelThumb,omitempty"`
+
+	// Premiere indicates a first-run airing.
+	Premiere bool `json:"premiere,omitempty"`
+}
+
+// Hub is a curated row of guide entries, such as what is on now.
+type Hub struct {
+	// HubIdentifier is the hub type identifier (e.g., "tv.onnow").
+	HubIdentifier string `json:"hubIdentifier"`
+
+	// Title is the hub display title.
+	Title string `json:"title"`
+
+	// Metadata is the list of programs in this hub.
+	Metadata []Program `json:"Metadata,omitempty"`
+}
+
+// Session is a tuned channel. Its key is passed as the path of a
+// transcode session to watch the channel.
+type Session struct {
+	// Key is the live session path (e.g., "/livetv/sessions/<uuid>").
+	Key string
+
+	// Program is what is airing on the channel, when known.
+	Program *Program
+}
+
+// Subscription is a recording rule for a single airing, a movie or a series.
+type Subscription struct {
+	// Key is the subscription identifier.
+	Key string `json:"key"`
+
+	// RatingKey is the guide identifier of the subscribed item.
+	RatingKey string `json:"ratingKey,omitempty"`
+
+	// GUID is the global identifier of the subscribed item.
+	GUID string `json:"guid,omitempty"`
+
+	// Type is the subscription type (see [SubscriptionType]).
+	Type SubscriptionType `json:"type"`
+
+	// Title is the subscription title (the show title for series).
+	Title string `json:"title"`
+
+	// TargetLibrarySectionID is the library recordings are added to.
+	TargetLibrarySectionID int `json:"targetLibrarySectionID,omitempty"`
+
+	// CreatedAt is the Unix timestamp when the subscription was created.
+	CreatedAt int64 `json:"createdAt,omitempty"`
+
+	// MediaGrabOperation lists the recordings scheduled or made for this
+	// subscription.
+	MediaGrabOperation []Recording `json:"MediaGrabOperation,omitempty"`
+}
+
+// SubscriptionType identifies what a subscription records.
+type SubscriptionType int
+
+const (
+	SubscriptionMovie   SubscriptionType = 1 // a movie
+	SubscriptionSeries  SubscriptionType = 2 // every new episode of a show
+	SubscriptionEpisode SubscriptionType = 4 // a single episode airing
+)
+
+// Recording is a grab operation: one airing scheduled for, being, or
+// having been recorded.
+type Recording struct {
+	// Key is the grab operation identifier.
+	Key string `json:"key"`
+
+	// MediaSubscriptionID is the subscription this recording belongs to.
+	MediaSubscriptionID int `json:"mediaSubscriptionID"`
+
+	// Status is the recording state (see the Recording* status constants).
+	Status string `json:"status"`
+
+	// Percent is the recording progress for in-progress recordings.
+	Percent float64 `json:"percent,omitempty"`
+
+	// Metadata is the program being recorded.
+	Metadata Program `json:"Metadata"`
+}
+
+// Recording statuses reported by the server.
+const (
+	RecordingScheduled  = "scheduled"
+	RecordingInProgress = "inprogress"
+	RecordingComplete   = "complete"
+	RecordingError      = "error"
+)
+
+// ScheduleOptions describes a recording to schedule.
+type ScheduleOptions struct {
+	// Program is the guide entry to record.
+	Program *Program
+
+	// Type is what to record: the single airing, the movie, or the series.
+	Type SubscriptionType
+
+	// SectionID is the library section recordings are added to.
+	SectionID string
+
+	// LocationID is the section location to record to (optional).
+	LocationID string
+}
+
+// EmptyResultError indicates that a query returned no results.
+type EmptyResultError struct {
+	Resource string
+}
+
+func (e *EmptyResultError) Error() string {
+	return fmt.Sprintf("%s not found", e.Resource)
+}
+
+// Container types for JSON unmarshaling.
+
+type mediaContainer struct {
+	Size int `json:"size"`
+}
+
+type dvrsContainer struct {
+	mediaContainer
+	Dvr []DVR `json:"Dvr"`
+}
+
+type channelsContainer struct {
+	mediaContainer
+	Channel []Channel `json:"Channel"`
+}
+
+type programsContainer struct {
+	mediaContainer
+	Metadata []Program `json:"Metadata"`
+}
+
+type hubsContainer struct {
+	mediaContainer
+	Hub []Hub `json:"Hub"`
+}
+
+type tuneContainer struct {
+	mediaContainer
+	MediaSubscription []struct {
+		MediaGrabOperation []struct {
+			Metadata Program `json:"Metadata"`
+		} `json:"MediaGrabOperation"`
+	} `json:"MediaSubscription"`
+}
+
+type subscriptionsContainer struct {
+	mediaContainer
+	MediaSubscription []Subscription `json:"MediaSubscription"`
+}
+
+type mediaContainerResponse[T any] struct {
+	MediaContainer T `json:"MediaContainer"`
+}
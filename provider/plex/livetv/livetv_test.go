@@ -0,0 +1,308 @@
+package livetv
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/0skillallluck/scanline/provider/plex/base"
+)
+
+// serveFixture answers with a recorded Plex response from testdata.
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Errorf("reading fixture: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func TestDVRs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/livetv/dvrs" {
+			t.Errorf("path = %s, want /livetv/dvrs", r.URL.Path)
+		}
+		if r.Header.Get("X-Plex-Token") != "token" || r.Header.Get("X-Plex-Client-Identifier") != "client" {
+			t.Errorf("headers = %v, want the Plex token and client identifier", r.Header)
+		}
+		serveFixture(t, w, "dvrs.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	dvrs, err := l.DVRs(context.Background())
+	if err != nil {
+		t.Fatalf("DVRs() error = %v", err)
+	}
+	if len(dvrs) != 1 {
+		t.Fatalf("len(dvrs) = %d, want 1", len(dvrs))
+	}
+	dvr := dvrs[0]
+	if dvr.Key != "4" || dvr.Lineup != "lineup://tv.plex.providers.epg.cloud/USA-OTA10001/OTA" {
+		t.Errorf("dvr = %+v", dvr)
+	}
+	if len(dvr.Device) != 1 || dvr.Device[0].Tuners != 4 || dvr.Device[0].Make != "Silicondust" {
+		t.Errorf("Device = %+v, want one Silicondust device with 4 tuners", dvr.Device)
+	}
+}
+
+func TestDVRs_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "wrong", ClientID: "client"})
+	if _, err := l.DVRs(context.Background()); err == nil {
+		t.Error("DVRs() error = nil, want error for unauthorized request")
+	}
+}
+
+func TestChannels(t *testing.T) {
+	const lineup = "lineup://tv.plex.providers.epg.cloud/USA-OTA10001/OTA"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/livetv/epg/channels" {
+			t.Errorf("path = %s, want /livetv/epg/channels", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("lineup"); got != lineup {
+			t.Errorf("lineup = %q, want %q", got, lineup)
+		}
+		serveFixture(t, w, "channels.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	channels, err := l.Channels(context.Background(), &DVR{Lineup: lineup})
+	if err != nil {
+		t.Fatalf("Channels() error = %v", err)
+	}
+	if len(channels) != 2 {
+		t.Fatalf("len(channels) = %d, want 2", len(channels))
+	}
+	if channels[0].Identifier != "2.1" || channels[0].CallSign != "WCBS" || !channels[0].HD {
+		t.Errorf("channels[0] = %+v", channels[0])
+	}
+}
+
+func TestGrid(t *testing.T) {
+	start := time.Unix(1767225600, 0)
+	end := start.Add(3 * time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Programs overlapping the window: starting before its end and
+		// ending after its start.
+		query := r.URL.Query()
+		if got := query.Get("beginsAt<"); got != "1767236400" {
+			t.Errorf("beginsAt< = %q, want 1767236400", got)
+		}
+		if got := query.Get("endsAt>"); got != "1767225600" {
+			t.Errorf("endsAt> = %q, want 1767225600", got)
+		}
+		if got := query.Get("lineup"); got != "lineup://test" {
+			t.Errorf("lineup = %q, want lineup://test", got)
+		}
+		serveFixture(t, w, "grid.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	programs, err := l.Grid(context.Background(), &DVR{Lineup: "lineup://test"}, start, end)
+	if err != nil {
+		t.Fatalf("Grid() error = %v", err)
+	}
+	if len(programs) != 2 {
+		t.Fatalf("len(programs) = %d, want 2", len(programs))
+	}
+	episode := programs[0]
+	if episode.DisplayTitle() != "Evening News" || episode.Title != "The Pilot" {
+		t.Errorf("episode titles = %q / %q", episode.DisplayTitle(), episode.Title)
+	}
+	if len(episode.Media) != 1 || !episode.Media[0].Premiere || episode.Media[0].ChannelIdentifier != "2.1" {
+		t.Errorf("episode airings = %+v", episode.Media)
+	}
+	movie := programs[1]
+	if movie.DisplayTitle() != "Night Movie" || len(movie.Media) != 2 {
+		t.Errorf("movie = %q with %d airings, want Night Movie with 2", movie.DisplayTitle(), len(movie.Media))
+	}
+}
+
+func TestHubs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(t, w, "hubs.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	hubs, err := l.Hubs(context.Background())
+	if err != nil {
+		t.Fatalf("Hubs() error = %v", err)
+	}
+	if len(hubs) != 1 || hubs[0].HubIdentifier != "tv.onnow" || len(hubs[0].Metadata) != 1 {
+		t.Errorf("hubs = %+v, want one On Now hub with one program", hubs)
+	}
+}
+
+func TestTune(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/livetv/dvrs/4/channels/2.1/tune" {
+			t.Errorf("request = %s %s, want POST /livetv/dvrs/4/channels/2.1/tune", r.Method, r.URL.Path)
+		}
+		serveFixture(t, w, "tune.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	session, err := l.Tune(context.Background(), "4", "2.1")
+	if err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+	if session.Key != "/livetv/sessions/b1f2e3d4-5a6b-4c7d-8e9f-0a1b2c3d4e5f" {
+		t.Errorf("Key = %q", session.Key)
+	}
+	if session.Program == nil || session.Program.DisplayTitle() != "Evening News" {
+		t.Errorf("Program = %+v, want Evening News", session.Program)
+	}
+}
+
+func TestTune_NoSession(t *testing.T) {
+	// A tuner that is busy answers without a session.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveFixture(t, w, "dvrs.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	_, err := l.Tune(context.Background(), "4", "9.1")
+	if _, ok := err.(*EmptyResultError); !ok {
+		t.Errorf("Tune() error = %v, want *EmptyResultError", err)
+	}
+}
+
+func TestSubscriptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/media/subscriptions" {
+			t.Errorf("path = %s, want /media/subscriptions", r.URL.Path)
+		}
+		if got := r.URL.Query().Get("includeGrabs"); got != "1" {
+			t.Errorf("includeGrabs = %q, want 1", got)
+		}
+		serveFixture(t, w, "subscriptions.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	subs, err := l.Subscriptions(context.Background())
+	if err != nil {
+		t.Fatalf("Subscriptions() error = %v", err)
+	}
+	if len(subs) != 2 {
+		t.Fatalf("len(subs) = %d, want 2", len(subs))
+	}
+
+	series := subs[0]
+	if series.Type != SubscriptionSeries || series.Title != "Evening News" {
+		t.Errorf("subs[0] = %+v", series)
+	}
+	if len(series.MediaGrabOperation) != 2 {
+		t.Fatalf("len(MediaGrabOperation) = %d, want 2", len(series.MediaGrabOperation))
+	}
+	recording := series.MediaGrabOperation[0]
+	if recording.Status != RecordingInProgress || recording.Percent != 42.5 || recording.Metadata.Title != "The Pilot" {
+		t.Errorf("recording = %+v", recording)
+	}
+	if series.MediaGrabOperation[1].Status != RecordingScheduled {
+		t.Errorf("second recording status = %q, want scheduled", series.MediaGrabOperation[1].Status)
+	}
+	if subs[1].Type != SubscriptionMovie || len(subs[1].MediaGrabOperation) != 0 {
+		t.Errorf("subs[1] = %+v, want movie rule without recordings", subs[1])
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	program := &Program{
+		RatingKey:        "plex://episode/6021a1f0c3a8d3002d2a2b61",
+		GUID:             "plex://episode/6021a1f0c3a8d3002d2a2b61",
+		Type:             "episode",
+		Title:            "The Pilot",
+		GrandparentTitle: "Evening News",
+		Media:            []Airing{{BeginsAt: 1767225600, EndsAt: 1767227400, ChannelIdentifier: "2.1"}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s, want POST", r.Method)
+		}
+		// Plex takes the recording rule as bracketed query parameters.
+		query := r.URL.Query()
+		want := url.Values{
+			"type":                    {"4"},
+			"targetLibrarySectionID":  {"3"},
+			"hints[guid]":             {program.GUID},
+			"hints[grandparentTitle]": {"Evening News"},
+			"params[airingChannels]":  {"2.1"},
+			"params[airingTimes]":     {"1767225600"},
+		}
+		for key, values := range want {
+			if got := query.Get(key); got != values[0] {
+				t.Errorf("%s = %q, want %q", key, got, values[0])
+			}
+		}
+		serveFixture(t, w, "schedule.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	sub, err := l.Schedule(context.Background(), ScheduleOptions{
+		Program:   program,
+		Type:      SubscriptionEpisode,
+		SectionID: "3",
+	})
+	if err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+	if sub.Key != "14" || len(sub.MediaGrabOperation) != 1 {
+		t.Errorf("sub = %+v, want key 14 with one recording", sub)
+	}
+}
+
+func TestSchedule_SeriesIsNotPinnedToAiring(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Has("params[airingChannels]") || query.Has("params[airingTimes]") {
+			t.Errorf("series subscription pinned to airing: %v", query)
+		}
+		serveFixture(t, w, "schedule.json")
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	program := &Program{
+		Type:  "episode",
+		Title: "The Pilot",
+		Media: []Airing{{BeginsAt: 1767225600, ChannelIdentifier: "2.1"}},
+	}
+	if _, err := l.Schedule(context.Background(), ScheduleOptions{Program: program, Type: SubscriptionSeries, SectionID: "3"}); err != nil {
+		t.Fatalf("Schedule() error = %v", err)
+	}
+}
+
+func TestCancelSubscription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/media/subscriptions/12" {
+			t.Errorf("request = %s %s, want DELETE /media/subscriptions/12", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	l := New(&base.Base{BaseURL: server.URL, Token: "token", ClientID: "client"})
+	if err := l.CancelSubscription(context.Background(), "12"); err != nil {
+		t.Fatalf("CancelSubscription() error = %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package livetv
+
+import (
+	"context"
+	"strconv"
+)
+
+// Subscriptions returns the recording rules together with their scheduled,
+// in-progress and completed recordings.
+func (l *LiveTV) Subscriptions(ctx context.Context) ([]Subscription, error) {
+	var resp mediaContainerResponse[subscriptionsContainer]
+	err := l.GetWithQuery(ctx, "/media/subscriptions", map[string]string{"includeGrabs": "1"}).
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MediaContainer.MediaSubscription, nil
+}
+
+// Schedule creates a recording rule for a guide entry.
+func (l *LiveTV) Schedule(ctx context.Context, opts ScheduleOptions) (*Subscription, error) {
+	program := opts.Program
+	query := map[string]string{
+		"type":                   strconv.Itoa(int(opts.Type)),
+		"targetLibrarySectionID": opts.SectionID,
+		"includeGrabs":           "1",
+		"hints[ratingKey]":       program.RatingKey,
+		"hints[guid]":            program.GUID,
+		"hints[type]":            program.Type,
+		"hints[title]":           program.Title,
+	}
+	if opts.LocationID != "" {
+		query["targetSectionLocationID"] = opts.LocationID
+	}
+	if program.GrandparentTitle != "" {
+		query["hints[grandparentTitle]"] = program.GrandparentTitle
+	}
+	if program.Year > 0 {
+		query["hints[year]"] = strconv.Itoa(program.Year)
+	}
+	// A single-airing recording is pinned to the chosen channel and time.
+	if opts.Type == SubscriptionEpisode && len(program.Media) > 0 {
+		airing := program.Media[0]
+		query["params[airingChannels]"] = airing.ChannelIdentifier
+		query["params[airingTimes]"] = strconv.FormatInt(airing.BeginsAt, 10)
+	}
+
+	var resp mediaContainerResponse[subscriptionsContainer]
+	err := l.PostWithQuery(ctx, "/media/subscriptions", query).
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.MediaContainer.MediaSubscription) == 0 {
+		return nil, &EmptyResultError{Resource: "subscription"}
+	}
+	return &resp.MediaContainer.MediaSubscription[0], nil
+}
+
+// CancelSubscription deletes a recording rule and its scheduled recordings.
+func (l *LiveTV) CancelSubscription(ctx context.Context, key string) error {
+	_, err := l.Delete(ctx, "/media/subscriptions/"+key).Do()
+	return err
+}
@@ -0,0 +1,33 @@
+package livetv
+
+import (
+	"context"
+	"net/url"
+)
+
+// Tune tunes a DVR to a channel and returns the live session.
+//
+// The channel parameter is the channel identifier (e.g., "5.1"). The
+// returned session key is used as the path of a transcode session to
+// watch the channel.
+func (l *LiveTV) Tune(ctx context.Context, dvrKey, channel string) (*Session, error) {
+	path := "/livetv/dvrs/" + url.PathEscape(dvrKey) + "/channels/" + url.PathEscape(channel) + "/tune"
+
+	var resp mediaContainerResponse[tuneContainer]
+	err := l.Post(ctx, path).
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, sub := range resp.MediaContainer.MediaSubscription {
+		for _, grab := range sub.MediaGrabOperation {
+			if grab.Metadata.Key == "" {
+				continue
+			}
+			program := grab.Metadata
+			return &Session{Key: program.Key, Program: &program}, nil
+		}
+	}
+	return nil, &EmptyResultError{Resource: "live session"}
+}
@@ -1,8 +1,10 @@
 package plex
 
 import (
+	"github.com/0skillallluck/scanline/provider/plex/history"
 	"github.com/0skillallluck/scanline/provider/plex/hubs"
 	"github.com/0skillallluck/scanline/provider/plex/library"
+	"github.com/0skillallluck/scanline/provider/plex/livetv"
 	"github.com/0skillallluck/scanline/provider/plex/playlists"
 	"github.com/0skillallluck/scanline/provider/plex/search"
 	"github.com/0skillallluck/scanline/provider/plex/server"
@@ -15,6 +17,9 @@ import (
 type (
 	ServerInfo     = server.ServerInfo
 	ServerIdentity = server.ServerIdentity
+	Account        = server.Account
+	Session        = server.Session
+	Setting        = server.Setting
 )
 
 // Library types
@@ -24,7 +29,9 @@ type (
 	Media          = library.Media
 	Part           = library.Part
 	Stream         = library.Stream
+	Lyrics         = library.Lyrics
 	Tag            = library.Tag
+	ExternalGUID   = library.ExternalGUID
 	ContentOptions = library.ContentOptions
 )
 
@@ -37,6 +44,25 @@ type SearchHub = search.Hub
 // Playlist types
 type Playlist = playlists.Playlist
 
+// History types
+type (
+	HistoryEntry   = history.Entry
+	HistoryOptions = history.Options
+)
+
+// Live TV types
+type (
+	DVR              = livetv.DVR
+	Channel          = livetv.Channel
+	Program          = livetv.Program
+	Airing           = livetv.Airing
+	LiveSession      = livetv.Session
+	Subscription     = livetv.Subscription
+	SubscriptionType = livetv.SubscriptionType
+	Recording        = livetv.Recording
+	ScheduleOptions  = livetv.ScheduleOptions
+)
+
 // PlaybackState is the PlaybackState type from the timeline sub-package.
 type PlaybackState = timeline.PlaybackState
 
@@ -0,0 +1,16 @@
+package server
+
+import "context"
+
+// Accounts lists the server-local accounts that have access to this server.
+//
+// Account IDs match the accountID values reported by watch history and sessions.
+func (s *Server) Accounts(ctx context.Context) ([]Account, error) {
+	var resp accountsContainer
+	err := s.Get(ctx, "/accounts").
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MediaContainer.Account, nil
+}
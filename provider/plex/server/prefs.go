@@ -0,0 +1,24 @@
+package server
+
+import "context"
+
+// Preferences retrieves the server preferences.
+//
+// Only the server owner can read the full set of preferences.
+func (s *Server) Preferences(ctx context.Context) ([]Setting, error) {
+	var resp settingsContainer
+	err := s.Get(ctx, "/:/prefs").
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MediaContainer.Setting, nil
+}
+
+// SetPreferences updates server preferences, keyed by setting ID.
+//
+// Booleans are passed as "true"/"false" and numbers in decimal form.
+func (s *Server) SetPreferences(ctx context.Context, values map[string]string) error {
+	_, err := s.PutWithQuery(ctx, "/:/prefs", values).Do()
+	return err
+}
@@ -1,7 +1,12 @@
 // Package server provides access to Plex server information endpoints.
 package server
 
-import "github.com/0skillallluck/scanline/provider/plex/base"
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/0skillallluck/scanline/provider/plex/base"
+)
 
 // Server provides access to server information endpoints.
 type Server struct {
@@ -44,6 +49,24 @@ type ServerInfo struct {
 
 	// TranscoderActiveVideoSessions is the number of active transcode sessions.
 	TranscoderActiveVideoSessions int `json:"transcoderActiveVideoSessions"`
+
+	// The Allow* fields report what the requesting user may do on this
+	// server; on shared servers they reflect the owner's restrictions.
+
+	// AllowSync indicates the user may download media for offline use.
+	AllowSync bool `json:"allowSync"`
+
+	// AllowMediaDeletion indicates the user may delete media.
+	AllowMediaDeletion bool `json:"allowMediaDeletion"`
+
+	// AllowCameraUpload indicates the user may upload photos.
+	AllowCameraUpload bool `json:"allowCameraUpload"`
+
+	// AllowChannelAccess indicates the user may access channels.
+	AllowChannelAccess bool `json:"allowChannelAccess"`
+
+	// AllowSharing indicates the user may share the server.
+	AllowSharing bool `json:"allowSharing"`
 }
 
 // ServerIdentity contains minimal identification information for a server.
@@ -55,6 +78,220 @@ type ServerIdentity struct {
 	Version string `json:"version"`
 }
 
+// Account is a server-local user account.
+type Account struct {
+	// ID is the server-local account identifier.
+	ID int `json:"id"`
+
+	// Name is the display name of the account.
+	Name string `json:"name"`
+
+	// Thumb is the URL of the account avatar.
+	Thumb string `json:"thumb,omitempty"`
+}
+
+// Session is an item currently being played from the server.
+type Session struct {
+	// RatingKey is the unique identifier of the item being played.
+	RatingKey string `json:"ratingKey"`
+
+	// Key is the API path of the item being played.
+	Key string `json:"key"`
+
+	// Type is the item type (movie, episode, track).
+	Type string `json:"type"`
+
+	// Title is the display title of the item.
+	Title string `json:"title"`
+
+	// ParentTitle is the title of the parent item (season or album).
+	ParentTitle string `json:"parentTitle,omitempty"`
+
+	// GrandparentTitle is the title of the grandparent item (show or artist).
+	GrandparentTitle string `json:"grandparentTitle,omitempty"`
+
+	// Index is the item's position (episode number, track number).
+	Index int `json:"index,omitempty"`
+
+	// ParentIndex is the parent's position (season number).
+	ParentIndex int `json:"parentIndex,omitempty"`
+
+	// Thumb is the URL path to the item thumbnail.
+	Thumb string `json:"thumb,omitempty"`
+
+	// GrandparentThumb is the URL path to the grandparent's thumbnail.
+	GrandparentThumb string `json:"grandparentThumb,omitempty"`
+
+	// Duration is the item length in milliseconds.
+	Duration int `json:"duration,omitempty"`
+
+	// ViewOffset is the current playback position in milliseconds.
+	ViewOffset int `json:"viewOffset,omitempty"`
+
+	// User is the account that is playing the item.
+	User SessionUser `json:"User"`
+
+	// Player is the client device playing the item.
+	Player SessionPlayer `json:"Player"`
+
+	// Session contains the stream's network details.
+	Session SessionInfo `json:"Session"`
+
+	// TranscodeSession is set when the server is transcoding the stream.
+	TranscodeSession *TranscodeSession `json:"TranscodeSession,omitempty"`
+}
+
+// SessionUser identifies the account behind a playback session.
+type SessionUser struct {
+	// ID is the server-local account identifier.
+	ID string `json:"id"`
+
+	// Title is the display name of the account.
+	Title string `json:"title"`
+
+	// Thumb is the URL of the account avatar.
+	Thumb string `json:"thumb,omitempty"`
+}
+
+// SessionPlayer describes the client device of a playback session.
+type SessionPlayer struct {
+	// Address is the IP address of the client.
+	Address string `json:"address"`
+
+	// Device is the device name (e.g., "iPhone", "Linux").
+	Device string `json:"device,omitempty"`
+
+	// MachineIdentifier is the client's unique identifier.
+	MachineIdentifier string `json:"machineIdentifier"`
+
+	// Platform is the client platform.
+	Platform string `json:"platform,omitempty"`
+
+	// Product is the client application name.
+	Product string `json:"product,omitempty"`
+
+	// State is the playback state (playing, paused, buffering).
+	State string `json:"state"`
+
+	// Title is the user-configured client name.
+	Title string `json:"title,omitempty"`
+
+	// Local indicates the client is on the server's local network.
+	Local bool `json:"local"`
+}
+
+// SessionInfo contains the network details of a playback session.
+type SessionInfo struct {
+	// ID is the session identifier used to terminate the session.
+	ID string `json:"id"`
+
+	// Bandwidth is the bandwidth used by the stream in kbps.
+	Bandwidth int `json:"bandwidth"`
+
+	// Location is "lan" or "wan".
+	Location string `json:"location"`
+}
+
+// TranscodeSession describes an active transcode.
+type TranscodeSession struct {
+	// Key is the API path of the transcode session.
+	Key string `json:"key"`
+
+	// Progress is the transcode progress as a percentage.
+	Progress float64 `json:"progress"`
+
+	// Speed is the transcode speed relative to realtime.
+	Speed float64 `json:"speed"`
+
+	// Throttled indicates the transcoder is ahead and has been throttled.
+	Throttled bool `json:"throttled"`
+
+	// VideoDecision is "transcode", "copy" or "directplay".
+	VideoDecision string `json:"videoDecision,omitempty"`
+
+	// AudioDecision is "transcode", "copy" or "directplay".
+	AudioDecision string `json:"audioDecision,omitempty"`
+
+	// SubtitleDecision is "transcode", "copy" or "burn".
+	SubtitleDecision string `json:"subtitleDecision,omitempty"`
+
+	// TranscodeHwRequested indicates hardware transcoding was requested.
+	TranscodeHwRequested bool `json:"transcodeHwRequested"`
+}
+
+// Decision summarizes how the stream is being delivered: "transcode" if
+// either video or audio is being transcoded, "copy" for direct stream and
+// "directplay" otherwise.
+func (s *Session) Decision() string {
+	t := s.TranscodeSession
+	if t == nil {
+		return "directplay"
+	}
+	if t.VideoDecision == "transcode" || t.AudioDecision == "transcode" {
+		return "transcode"
+	}
+	return "copy"
+}
+
+// Setting is a single server preference from /:/prefs.
+type Setting struct {
+	// ID is the preference key used when updating it.
+	ID string `json:"id"`
+
+	// Label is the human-readable name of the preference.
+	Label string `json:"label"`
+
+	// Summary describes what the preference does.
+	Summary string `json:"summary,omitempty"`
+
+	// Type is the value type (bool, int, double, text).
+	Type string `json:"type"`
+
+	// Default is the server default value.
+	Default SettingValue `json:"default"`
+
+	// Value is the current value.
+	Value SettingValue `json:"value"`
+
+	// Hidden indicates the preference is not meant to be shown to users.
+	Hidden bool `json:"hidden"`
+
+	// Advanced indicates the preference is only shown in advanced mode.
+	Advanced bool `json:"advanced"`
+
+	// Group is the category the preference belongs to (e.g., "general", "network").
+	Group string `json:"group,omitempty"`
+
+	// EnumValues lists the allowed values as "value:Label" pairs separated by "|".
+	EnumValues string `json:"enumValues,omitempty"`
+}
+
+// SettingValue is a preference value normalized to its string form.
+type SettingValue string
+
+// UnmarshalJSON implements custom unmarshaling for SettingValue.
+// Plex encodes values as strings, numbers or booleans depending on the setting type.
+func (v *SettingValue) UnmarshalJSON(data []byte) error {
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		*v = SettingValue(str)
+		return nil
+	}
+
+	var b bool
+	if err := json.Unmarshal(data, &b); err == nil {
+		*v = SettingValue(strconv.FormatBool(b))
+		return nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err != nil {
+		return err
+	}
+	*v = SettingValue(num.String())
+	return nil
+}
+
 // Container types for JSON unmarshaling.
 
 type serverInfoContainer struct {
@@ -64,3 +301,21 @@ type serverInfoContainer struct {
 type serverIdentityContainer struct {
 	MediaContainer ServerIdentity `json:"MediaContainer"`
 }
+
+type accountsContainer struct {
+	MediaContainer struct {
+		Account []Account `json:"Account"`
+	} `json:"MediaContainer"`
+}
+
+type sessionsContainer struct {
+	MediaContainer struct {
+		Metadata []Session `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+type settingsContainer struct {
+	MediaContainer struct {
+		Setting []Setting `json:"Setting"`
+	} `json:"MediaContainer"`
+}
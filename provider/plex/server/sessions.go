@@ -0,0 +1,29 @@
+package server
+
+import "context"
+
+// Sessions lists the playback sessions currently active on the server.
+//
+// Only the server owner can see sessions of other accounts.
+func (s *Server) Sessions(ctx context.Context) ([]Session, error) {
+	var resp sessionsContainer
+	err := s.Get(ctx, "/status/sessions").
+		DoAndDecode(&resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp.MediaContainer.Metadata, nil
+}
+
+// TerminateSession stops a playback session, showing reason to the user
+// on the client.
+func (s *Server) TerminateSession(ctx context.Context, sessionID, reason string) error {
+	query := map[string]string{
+		"sessionId": sessionID,
+	}
+	if reason != "" {
+		query["reason"] = reason
+	}
+	_, err := s.GetWithQuery(ctx, "/status/sessions/terminate", query).Do()
+	return err
+}
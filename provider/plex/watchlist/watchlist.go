@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
 const discoverBaseURL = "https://discover.provider.plex.tv"
@@ -19,7 +22,85 @@ type Item struct {
 	Thumb     string `json:"thumb,omitempty"`
 	Art       string `json:"art,omitempty"`
 	Summary   string `json:"summary,omitempty"`
-	GUID string `json:"guid,omitempty"` // e.g. "plex://movie/..."
+	GUID      string `json:"guid,omitempty"` // e.g. "plex://movie/..."
+
+	OriginallyAvailableAt string `json:"originallyAvailableAt,omitempty"` // "YYYY-MM-DD"
+}
+
+// ReleaseDate returns the item's release date, or false if it is unknown.
+func (i *Item) ReleaseDate() (time.Time, bool) {
+	if len(i.OriginallyAvailableAt) < 10 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", i.OriginallyAvailableAt[:10])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// Released reports whether the item has been released as of now. Items
+// without a release date count as released.
+func (i *Item) Released(now time.Time) bool {
+	date, ok := i.ReleaseDate()
+	return !ok || !date.After(now)
+}
+
+// Metadata is the full discover record of a movie or show.
+type Metadata struct {
+	Item
+
+	Tagline        string  `json:"tagline,omitempty"`
+	ContentRating  string  `json:"contentRating,omitempty"`
+	Studio         string  `json:"studio,omitempty"`
+	Duration       int     `json:"duration,omitempty"`   // milliseconds
+	ChildCount     int     `json:"childCount,omitempty"` // seasons, for shows
+	AudienceRating float64 `json:"audienceRating,omitempty"`
+	Genre          []Tag   `json:"Genre,omitempty"`
+	Director       []Tag   `json:"Director,omitempty"`
+	Role           []Tag   `json:"Role,omitempty"`
+}
+
+// Tag is a genre, director or cast member of a discover record.
+type Tag struct {
+	Tag   string `json:"tag"`
+	Role  string `json:"role,omitempty"` // character name, for cast
+	Thumb string `json:"thumb,omitempty"`
+}
+
+// RatingKeyFromGUID returns the discover rating key of a "plex://" GUID,
+// as found on items matched by the Plex agents. It returns false for GUIDs
+// of legacy agents, which have no discover record.
+func RatingKeyFromGUID(guid string) (string, bool) {
+	rest, ok := strings.CutPrefix(guid, "plex://")
+	if !ok {
+		return "", false
+	}
+	_, key, ok := strings.Cut(rest, "/")
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
+}
+
+// Filters accepted by [Client.List].
+const (
+	FilterAll       = "all"
+	FilterAvailable = "available" // available to stream or on a server
+	FilterReleased  = "released"
+)
+
+// Sort orders accepted by [Client.List].
+const (
+	SortDateAdded   = "watchlistedAt:desc"
+	SortReleaseDate = "originallyAvailableAt:desc"
+	SortTitle       = "titleSort:asc"
+)
+
+// ListOptions configures a watchlist request.
+type ListOptions struct {
+	Filter string // one of the Filter* constants; defaults to FilterAll
+	Sort   string // one of the Sort* constants; defaults to SortDateAdded
 }
 
 // Client fetches watchlist data from the Plex Discover API.
@@ -45,41 +126,116 @@ type watchlistResponse struct {
 	} `json:"MediaContainer"`
 }
 
-// List fetches the watchlist with the given filter ("all", "available", "released").
-func (c *Client) List(ctx context.Context, filter string) ([]Item, error) {
+// metadataResponse is the JSON envelope of a discover metadata request.
+type metadataResponse struct {
+	MediaContainer struct {
+		Metadata []Metadata `json:"Metadata"`
+	} `json:"MediaContainer"`
+}
+
+// userStateResponse is the JSON envelope of a discover user state request.
+type userStateResponse struct {
+	MediaContainer struct {
+		UserState struct {
+			WatchlistedAt int64 `json:"watchlistedAt,omitempty"`
+		} `json:"UserState"`
+	} `json:"MediaContainer"`
+}
+
+// List fetches the watchlist.
+func (c *Client) List(ctx context.Context, opts ListOptions) ([]Item, error) {
+	filter := opts.Filter
 	if filter == "" {
-		filter = "all"
+		filter = FilterAll
+	}
+	sort := opts.Sort
+	if sort == "" {
+		sort = SortDateAdded
+	}
+
+	query := url.Values{}
+	query.Set("includeCollections", "1")
+	query.Set("includeExternalMedia", "1")
+	query.Set("sort", sort)
+
+	var result watchlistResponse
+	if err := c.do(ctx, http.MethodGet, "/library/sections/watchlist/"+filter, query, &result); err != nil {
+		return nil, fmt.Errorf("watchlist request failed: %w", err)
+	}
+	return result.MediaContainer.Metadata, nil
+}
+
+// Add adds an item to the watchlist by its discover rating key.
+func (c *Client) Add(ctx context.Context, ratingKey string) error {
+	query := url.Values{}
+	query.Set("ratingKey", ratingKey)
+	if err := c.do(ctx, http.MethodPut, "/actions/addToWatchlist", query, nil); err != nil {
+		return fmt.Errorf("adding to watchlist: %w", err)
+	}
+	return nil
+}
+
+// Remove removes an item from the watchlist by its discover rating key.
+func (c *Client) Remove(ctx context.Context, ratingKey string) error {
+	query := url.Values{}
+	query.Set("ratingKey", ratingKey)
+	if err := c.do(ctx, http.MethodPut, "/actions/removeFromWatchlist", query, nil); err != nil {
+		return fmt.Errorf("removing from watchlist: %w", err)
+	}
+	return nil
+}
+
+// IsWatchlisted reports whether an item is on the watchlist, by its
+// discover rating key. It asks for the account's state of the one item
+// rather than listing the whole watchlist.
+func (c *Client) IsWatchlisted(ctx context.Context, ratingKey string) (bool, error) {
+	var result userStateResponse
+	if err := c.do(ctx, http.MethodGet, "/library/metadata/"+url.PathEscape(ratingKey)+"/userState", nil, &result); err != nil {
+		return false, fmt.Errorf("user state request failed: %w", err)
+	}
+	return result.MediaContainer.UserState.WatchlistedAt > 0, nil
+}
+
+// Metadata fetches the full discover record of an item.
+func (c *Client) Metadata(ctx context.Context, ratingKey string) (*Metadata, error) {
+	var result metadataResponse
+	if err := c.do(ctx, http.MethodGet, "/library/metadata/"+url.PathEscape(ratingKey), nil, &result); err != nil {
+		return nil, fmt.Errorf("discover metadata request failed: %w", err)
 	}
+	if len(result.MediaContainer.Metadata) == 0 {
+		return nil, fmt.Errorf("discover metadata for %q not found", ratingKey)
+	}
+	return &result.MediaContainer.Metadata[0], nil
+}
 
-	url := discoverBaseURL + "/library/sections/watchlist/" + filter
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+// do sends an authenticated discover request and decodes the response into
+// out, unless out is nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, discoverBaseURL+path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
+		return fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("X-Plex-Token", c.token)
 	req.Header.Set("X-Plex-Client-Identifier", c.clientID)
-
-	q := req.URL.Query()
-	q.Set("includeCollections", "1")
-	q.Set("includeExternalMedia", "1")
-	req.URL.RawQuery = q.Encode()
+	req.URL.RawQuery = query.Encode()
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("executing request: %w", err)
+		return fmt.Errorf("executing request: %w", err)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("watchlist request failed: %s", resp.Status)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status: %s", resp.Status)
 	}
-
-	var result watchlistResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decoding response: %w", err)
+	if out == nil {
+		return nil
 	}
 
-	return result.MediaContainer.Metadata, nil
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
 }
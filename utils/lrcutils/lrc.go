@@ -0,0 +1,205 @@
+// Package lrcutils parses lyrics in the LRC format.
+//
+// Synced lyrics carry one or more "[mm:ss.xx]" timestamps per line. Plain
+// text without timestamps is accepted as unsynced lyrics.
+package lrcutils
+
+import (
+	"bufio"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Line is a single lyrics line.
+type Line struct {
+	// Time is when the line starts. It is zero for unsynced lyrics.
+	Time time.Duration
+
+	// Text is the line text. Empty lines mark instrumental breaks.
+	Text string
+}
+
+// Lyrics is a parsed lyrics document.
+type Lyrics struct {
+	// Lines are the lyrics lines, ordered by time when synced.
+	Lines []Line
+
+	// Synced indicates the lines carry timestamps.
+	Synced bool
+
+	// Tags holds ID tags such as "ti" (title), "ar" (artist) and "al" (album).
+	Tags map[string]string
+}
+
+// Parse parses LRC or plain text lyrics. The "offset" tag, in milliseconds,
+// is applied to all timestamps; positive values make lines appear earlier.
+// Word-level timestamps from enhanced LRC ("<mm:ss.xx>") are stripped.
+func Parse(text string) *Lyrics {
+	lyrics := &Lyrics{Tags: make(map[string]string)}
+	var plain []Line
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		line = strings.TrimPrefix(line, "\uFEFF")
+
+		times, rest := parseTimestamps(line)
+		if len(times) > 0 {
+			rest = strings.TrimSpace(stripWordTimestamps(rest))
+			for _, t := range times {
+				lyrics.Lines = append(lyrics.Lines, Line{Time: t, Text: rest})
+			}
+			continue
+		}
+
+		if key, value, ok := parseTag(line); ok {
+			lyrics.Tags[key] = value
+			continue
+		}
+
+		plain = append(plain, Line{Text: strings.TrimSpace(line)})
+	}
+
+	if len(lyrics.Lines) == 0 {
+		lyrics.Lines = trimBlankLines(plain)
+		return lyrics
+	}
+
+	lyrics.Synced = true
+	if offset, err := strconv.Atoi(strings.TrimSpace(lyrics.Tags["offset"])); err == nil && offset != 0 {
+		shift := time.Duration(offset) * time.Millisecond
+		for i := range lyrics.Lines {
+			lyrics.Lines[i].Time = max(lyrics.Lines[i].Time-shift, 0)
+		}
+	}
+	sort.SliceStable(lyrics.Lines, func(i, j int) bool {
+		return lyrics.Lines[i].Time < lyrics.Lines[j].Time
+	})
+	return lyrics
+}
+
+// LineAt returns the index of the line playing at pos, or -1 before the
+// first line and for unsynced lyrics.
+func (l *Lyrics) LineAt(pos time.Duration) int {
+	if !l.Synced {
+		return -1
+	}
+	// Index of the first line starting after pos; the one before it is current.
+	i := sort.Search(len(l.Lines), func(i int) bool {
+		return l.Lines[i].Time > pos
+	})
+	return i - 1
+}
+
+// parseTimestamps consumes leading "[mm:ss.xx]" timestamps and returns them
+// along with the remaining text.
+func parseTimestamps(line string) ([]time.Duration, string) {
+	var times []time.Duration
+	rest := line
+	for strings.HasPrefix(rest, "[") {
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			break
+		}
+		t, ok := parseTime(rest[1:end])
+		if !ok {
+			break
+		}
+		times = append(times, t)
+		rest = rest[end+1:]
+	}
+	return times, rest
+}
+
+// parseTime parses "mm:ss", "mm:ss.xx" or "mm:ss.xxx".
+func parseTime(s string) (time.Duration, bool) {
+	minutes, seconds, ok := strings.Cut(s, ":")
+	if !ok {
+		return 0, false
+	}
+	m, err := strconv.Atoi(minutes)
+	if err != nil || m < 0 {
+		return 0, false
+	}
+
+	whole, frac, hasFrac := strings.Cut(seconds, ".")
+	if !hasFrac {
+		// Some files use a colon before the fraction ("mm:ss:xx").
+		whole, frac, hasFrac = strings.Cut(seconds, ":")
+	}
+	sec, err := strconv.Atoi(whole)
+	if err != nil || sec < 0 || sec >= 60 {
+		return 0, false
+	}
+
+	t := time.Duration(m)*time.Minute + time.Duration(sec)*time.Second
+	if hasFrac {
+		if frac == "" || len(frac) > 3 {
+			return 0, false
+		}
+		f, err := strconv.Atoi(frac)
+		if err != nil {
+			return 0, false
+		}
+		// Scale hundredths or tenths to milliseconds.
+		for i := len(frac); i < 3; i++ {
+			f *= 10
+		}
+		t += time.Duration(f) * time.Millisecond
+	}
+	return t, true
+}
+
+// parseTag parses an ID tag such as "[ar:Artist]".
+func parseTag(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "[") || !strings.HasSuffix(line, "]") {
+		return "", "", false
+	}
+	key, value, ok = strings.Cut(line[1:len(line)-1], ":")
+	if !ok || key == "" || strings.ContainsAny(key, " []") {
+		return "", "", false
+	}
+	return strings.ToLower(key), strings.TrimSpace(value), true
+}
+
+// stripWordTimestamps removes enhanced LRC word timestamps ("<mm:ss.xx>").
+func stripWordTimestamps(text string) string {
+	if !strings.Contains(text, "<") {
+		return text
+	}
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(text, '<')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start:], '>')
+		if end < 0 {
+			break
+		}
+		if _, ok := parseTime(text[start+1 : start+end]); !ok {
+			b.WriteString(text[:start+end+1])
+			text = text[start+end+1:]
+			continue
+		}
+		b.WriteString(text[:start])
+		text = text[start+end+1:]
+	}
+	b.WriteString(text)
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// trimBlankLines drops leading and trailing empty lines.
+func trimBlankLines(lines []Line) []Line {
+	for len(lines) > 0 && lines[0].Text == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1].Text == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
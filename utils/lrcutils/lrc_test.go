@@ -0,0 +1,123 @@
+package lrcutils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_SyncedLyrics(t *testing.T) {
+	lyrics := Parse("[ti:Song]\n[ar:Artist]\n[00:01.50]First line\n[00:04.20]Second line\n[01:02.345]Third line\n")
+
+	if !lyrics.Synced {
+		t.Fatal("Synced = false, want true")
+	}
+	if lyrics.Tags["ti"] != "Song" || lyrics.Tags["ar"] != "Artist" {
+		t.Errorf("Tags = %v, want ti=Song ar=Artist", lyrics.Tags)
+	}
+
+	want := []Line{
+		{Time: 1500 * time.Millisecond, Text: "First line"},
+		{Time: 4200 * time.Millisecond, Text: "Second line"},
+		{Time: time.Minute + 2345*time.Millisecond, Text: "Third line"},
+	}
+	if len(lyrics.Lines) != len(want) {
+		t.Fatalf("len(Lines) = %d, want %d", len(lyrics.Lines), len(want))
+	}
+	for i, line := range lyrics.Lines {
+		if line != want[i] {
+			t.Errorf("Lines[%d] = %+v, want %+v", i, line, want[i])
+		}
+	}
+}
+
+func TestParse_RepeatedTimestampsAreSorted(t *testing.T) {
+	lyrics := Parse("[00:10.00][00:30.00]Chorus\n[00:20.00]Verse\n")
+
+	want := []string{"Chorus", "Verse", "Chorus"}
+	if len(lyrics.Lines) != len(want) {
+		t.Fatalf("len(Lines) = %d, want %d", len(lyrics.Lines), len(want))
+	}
+	for i, line := range lyrics.Lines {
+		if line.Text != want[i] {
+			t.Errorf("Lines[%d].Text = %q, want %q", i, line.Text, want[i])
+		}
+	}
+	if lyrics.Lines[2].Time != 30*time.Second {
+		t.Errorf("Lines[2].Time = %v, want 30s", lyrics.Lines[2].Time)
+	}
+}
+
+func TestParse_AppliesOffset(t *testing.T) {
+	lyrics := Parse("[offset:+500]\n[00:01.00]Line\n[00:00.20]Early\n")
+
+	if lyrics.Lines[0].Time != 0 {
+		t.Errorf("Lines[0].Time = %v, want 0 (clamped)", lyrics.Lines[0].Time)
+	}
+	if lyrics.Lines[1].Time != 500*time.Millisecond {
+		t.Errorf("Lines[1].Time = %v, want 500ms", lyrics.Lines[1].Time)
+	}
+}
+
+func TestParse_StripsWordTimestamps(t *testing.T) {
+	lyrics := Parse("[00:01.00]<00:01.00>Hello <00:01.50>world <b>\n")
+
+	if got := lyrics.Lines[0].Text; got != "Hello world <b>" {
+		t.Errorf("Text = %q, want %q", got, "Hello world <b>")
+	}
+}
+
+func TestParse_PlainText(t *testing.T) {
+	lyrics := Parse("\nFirst line\r\n\r\nSecond line\n\n")
+
+	if lyrics.Synced {
+		t.Error("Synced = true, want false")
+	}
+	want := []string{"First line", "", "Second line"}
+	if len(lyrics.Lines) != len(want) {
+		t.Fatalf("len(Lines) = %d, want %d", len(lyrics.Lines), len(want))
+	}
+	for i, line := range lyrics.Lines {
+		if line.Text != want[i] || line.Time != 0 {
+			t.Errorf("Lines[%d] = %+v, want text %q", i, line, want[i])
+		}
+	}
+}
+
+func TestParse_BracketedTextIsNotATimestamp(t *testing.T) {
+	lyrics := Parse("[Chorus]\nLa la la\n")
+
+	if lyrics.Synced {
+		t.Error("Synced = true, want false")
+	}
+	if len(lyrics.Lines) != 2 || lyrics.Lines[0].Text != "[Chorus]" {
+		t.Errorf("Lines = %+v, want section marker kept as text", lyrics.Lines)
+	}
+}
+
+func TestLineAt(t *testing.T) {
+	lyrics := Parse("[00:01.00]One\n[00:05.00]Two\n[00:09.00]Three\n")
+
+	tests := []struct {
+		pos  time.Duration
+		want int
+	}{
+		{0, -1},
+		{time.Second, 0},
+		{4 * time.Second, 0},
+		{5 * time.Second, 1},
+		{time.Minute, 2},
+	}
+	for _, tt := range tests {
+		if got := lyrics.LineAt(tt.pos); got != tt.want {
+			t.Errorf("LineAt(%v) = %d, want %d", tt.pos, got, tt.want)
+		}
+	}
+}
+
+func TestLineAt_Unsynced(t *testing.T) {
+	lyrics := Parse("Just text\n")
+
+	if got := lyrics.LineAt(time.Minute); got != -1 {
+		t.Errorf("LineAt() = %d, want -1", got)
+	}
+}